@@ -28,9 +28,11 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	defer bot.Close()
 
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM)
 	<-sc
+
+	// running commands get a grace period to finish before they are cancelled
+	bot.Close()
 }
@@ -6,7 +6,6 @@ require (
 	github.com/bwmarrin/discordgo v0.27.2-0.20240104191117-afc57886f91a
 	github.com/dustin/go-humanize v1.0.1
 	github.com/g4s8/hexcolor v1.2.0
-	github.com/google/uuid v1.5.0
 	github.com/intrntsrfr/gol v0.0.0-20230204094040-9acd533ddee3
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/lib/pq v1.10.9
@@ -13,6 +13,10 @@ type DB interface {
 
 	ICommandLogDB
 	IGuildDB
+	IGuildToggleDB
+	ICommandPermissionDB
+	ICooldownDB
+	IScheduledJobDB
 	IProcessedEventsDB
 }
 
@@ -27,6 +31,44 @@ type IGuildDB interface {
 	GetGuild(guildID string) (*structs.Guild, error)
 }
 
+type IGuildToggleDB interface {
+	CreateGuildToggle(guildID, kind, name string) error
+	DeleteGuildToggle(guildID, kind, name string) error
+	GetGuildToggles(guildID string) ([]*structs.GuildToggle, error)
+}
+
+type ICommandPermissionDB interface {
+	UpsertCommandPermission(p *structs.CommandPermission) error
+	DeleteCommandPermission(guildID, command, target, targetID string) error
+	DeleteCommandPermissions(guildID, command string) error
+	GetCommandPermissions(guildID string) ([]*structs.CommandPermission, error)
+}
+
+type ICooldownDB interface {
+	// UpdateCooldown locks the cooldown of key, creating it if needed, and saves
+	// it after update returns. Nothing is saved if update returns an error.
+	UpdateCooldown(key string, update func(c *structs.Cooldown) error) error
+	DeleteCooldown(key string) error
+	DeleteExpiredCooldowns() error
+}
+
+type IScheduledJobDB interface {
+	CreateScheduledJob(j *structs.ScheduledJob) error
+	// ClaimScheduledJobs claims up to limit jobs that are due at now and not failed
+	// or claimed, for claimer until until. Jobs locked by another claim are skipped.
+	ClaimScheduledJobs(now, until time.Time, claimer string, limit int) ([]*structs.ScheduledJob, error)
+	DeleteScheduledJob(uid int64) (bool, error)
+	// CompleteScheduledJob, RetryScheduledJob and FailScheduledJob only change a
+	// job claimed by claimer, and return false if it is not.
+	CompleteScheduledJob(uid int64, claimer string) (bool, error)
+	RetryScheduledJob(uid int64, claimer string, runAt time.Time, lastErr string) (bool, error)
+	FailScheduledJob(uid int64, claimer, lastErr string) (bool, error)
+	GetScheduledJobs() ([]*structs.ScheduledJob, error)
+	// ClaimRecurringJobRun claims the run of the recurring job name due at due for
+	// claimer, and returns false if that run or a later one was claimed already.
+	ClaimRecurringJobRun(name string, due time.Time, claimer string) (bool, error)
+}
+
 type IProcessedEventsDB interface {
 	UpsertCount(eventType string, sentAt time.Time) error
 }
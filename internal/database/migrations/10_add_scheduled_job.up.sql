@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS scheduled_job (
+    uid BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT 'null',
+    run_at timestamp with time zone NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    failed BOOLEAN NOT NULL DEFAULT false,
+    claimed_by TEXT NOT NULL DEFAULT '',
+    claimed_until timestamp with time zone
+);
+CREATE INDEX IF NOT EXISTS scheduled_job_due_idx ON scheduled_job (run_at) WHERE NOT failed;
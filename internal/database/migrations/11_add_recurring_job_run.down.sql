@@ -0,0 +1 @@
+DROP TABLE IF EXISTS recurring_job_run;
\ No newline at end of file
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS recurring_job_run (
+    name TEXT PRIMARY KEY,
+    due_at timestamp with time zone NOT NULL,
+    claimed_by TEXT NOT NULL
+);
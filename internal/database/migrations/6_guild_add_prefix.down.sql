@@ -0,0 +1,2 @@
+alter table guild
+	drop column prefix;
@@ -0,0 +1,2 @@
+alter table guild
+	add column prefix text default '' not null;
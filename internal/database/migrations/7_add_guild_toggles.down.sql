@@ -0,0 +1 @@
+DROP TABLE IF EXISTS guild_toggle;
\ No newline at end of file
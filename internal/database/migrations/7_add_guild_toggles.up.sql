@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS guild_toggle (
+    guild_id TEXT NOT NULL REFERENCES guild,
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    PRIMARY KEY (guild_id, kind, name)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS command_permission;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS command_permission (
+    guild_id TEXT NOT NULL REFERENCES guild,
+    command TEXT NOT NULL,
+    target TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    allow BOOLEAN NOT NULL,
+    PRIMARY KEY (guild_id, command, target, target_id)
+);
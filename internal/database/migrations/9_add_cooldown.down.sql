@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cooldown;
\ No newline at end of file
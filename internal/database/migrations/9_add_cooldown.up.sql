@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS cooldown (
+    cooldown_key TEXT PRIMARY KEY,
+    state JSONB NOT NULL DEFAULT '{}',
+    expires_at timestamp with time zone NOT NULL
+);
@@ -0,0 +1,382 @@
+package mocks
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/intrntsrfr/meido/internal/database"
+	"github.com/intrntsrfr/meido/internal/structs"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ database.DB = (*DBMock)(nil)
+
+// DBMock is a database.DB that keeps everything in memory, and records the
+// calls made to it, so tests can check what a module wrote.
+type DBMock struct {
+	sync.Mutex
+	calls []string
+
+	commandLog  []*structs.CommandLogEntry
+	guilds      map[string]*structs.Guild
+	toggles     []*structs.GuildToggle
+	permissions []*structs.CommandPermission
+	cooldowns   map[string]*structs.Cooldown
+	jobs        map[int64]*structs.ScheduledJob
+	nextJobID   int64
+	runs        map[string]time.Time
+	events      map[string]int
+}
+
+func NewDB() *DBMock {
+	return &DBMock{
+		guilds:    make(map[string]*structs.Guild),
+		cooldowns: make(map[string]*structs.Cooldown),
+		jobs:      make(map[int64]*structs.ScheduledJob),
+		runs:      make(map[string]time.Time),
+		events:    make(map[string]int),
+	}
+}
+
+// Record records a call to method with args, formatted like "Method(a, b)". Fakes
+// that embed DBMock use it to record their own methods.
+func (db *DBMock) Record(method string, args ...any) {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = fmt.Sprint(a)
+	}
+	db.Lock()
+	defer db.Unlock()
+	db.calls = append(db.calls, fmt.Sprintf("%v(%v)", method, strings.Join(parts, ", ")))
+}
+
+// Calls returns the calls made so far, in order.
+func (db *DBMock) Calls() []string {
+	db.Lock()
+	defer db.Unlock()
+	return append([]string(nil), db.calls...)
+}
+
+// Conn returns nil, as there is no connection to a real database.
+func (db *DBMock) Conn() *sqlx.DB {
+	return nil
+}
+
+func (db *DBMock) Close() error {
+	db.Record("Close")
+	return nil
+}
+
+func (db *DBMock) CreateCommandLogEntry(e *structs.CommandLogEntry) error {
+	db.Record("CreateCommandLogEntry", e.Command, e.UserID)
+	db.Lock()
+	defer db.Unlock()
+	c := *e
+	db.commandLog = append(db.commandLog, &c)
+	return nil
+}
+
+func (db *DBMock) GetCommandCount() (int, error) {
+	db.Record("GetCommandCount")
+	db.Lock()
+	defer db.Unlock()
+	return len(db.commandLog), nil
+}
+
+// CreateGuild creates a guild with the defaults of the guild table.
+func (db *DBMock) CreateGuild(guildID string, joinedAt time.Time) error {
+	db.Record("CreateGuild", guildID)
+	db.Lock()
+	defer db.Unlock()
+	if _, ok := db.guilds[guildID]; !ok {
+		db.guilds[guildID] = &structs.Guild{GuildID: guildID, JoinedAt: &joinedAt, MaxWarns: 3, WarnDuration: 30}
+	}
+	return nil
+}
+
+func (db *DBMock) UpdateGuild(g *structs.Guild) error {
+	db.Record("UpdateGuild", g.GuildID)
+	db.Lock()
+	defer db.Unlock()
+	c := *g
+	db.guilds[g.GuildID] = &c
+	return nil
+}
+
+// GetGuild returns sql.ErrNoRows if the guild was never created.
+func (db *DBMock) GetGuild(guildID string) (*structs.Guild, error) {
+	db.Record("GetGuild", guildID)
+	db.Lock()
+	defer db.Unlock()
+	g, ok := db.guilds[guildID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	c := *g
+	return &c, nil
+}
+
+func (db *DBMock) CreateGuildToggle(guildID, kind, name string) error {
+	db.Record("CreateGuildToggle", guildID, kind, name)
+	db.Lock()
+	defer db.Unlock()
+	db.toggles = append(db.toggles, &structs.GuildToggle{GuildID: guildID, Kind: kind, Name: name})
+	return nil
+}
+
+func (db *DBMock) DeleteGuildToggle(guildID, kind, name string) error {
+	db.Record("DeleteGuildToggle", guildID, kind, name)
+	db.Lock()
+	defer db.Unlock()
+	kept := db.toggles[:0]
+	for _, t := range db.toggles {
+		if t.GuildID != guildID || t.Kind != kind || t.Name != name {
+			kept = append(kept, t)
+		}
+	}
+	db.toggles = kept
+	return nil
+}
+
+func (db *DBMock) GetGuildToggles(guildID string) ([]*structs.GuildToggle, error) {
+	db.Record("GetGuildToggles", guildID)
+	db.Lock()
+	defer db.Unlock()
+	var toggles []*structs.GuildToggle
+	for _, t := range db.toggles {
+		if t.GuildID == guildID {
+			c := *t
+			toggles = append(toggles, &c)
+		}
+	}
+	return toggles, nil
+}
+
+func (db *DBMock) UpsertCommandPermission(p *structs.CommandPermission) error {
+	db.Record("UpsertCommandPermission", p.GuildID, p.Command, p.Target, p.TargetID, p.Allow)
+	db.Lock()
+	defer db.Unlock()
+	c := *p
+	for i, e := range db.permissions {
+		if e.GuildID == p.GuildID && e.Command == p.Command && e.Target == p.Target && e.TargetID == p.TargetID {
+			db.permissions[i] = &c
+			return nil
+		}
+	}
+	db.permissions = append(db.permissions, &c)
+	return nil
+}
+
+func (db *DBMock) DeleteCommandPermission(guildID, command, target, targetID string) error {
+	db.Record("DeleteCommandPermission", guildID, command, target, targetID)
+	db.Lock()
+	defer db.Unlock()
+	kept := db.permissions[:0]
+	for _, p := range db.permissions {
+		if p.GuildID != guildID || p.Command != command || p.Target != target || p.TargetID != targetID {
+			kept = append(kept, p)
+		}
+	}
+	db.permissions = kept
+	return nil
+}
+
+func (db *DBMock) DeleteCommandPermissions(guildID, command string) error {
+	db.Record("DeleteCommandPermissions", guildID, command)
+	db.Lock()
+	defer db.Unlock()
+	kept := db.permissions[:0]
+	for _, p := range db.permissions {
+		if p.GuildID != guildID || p.Command != command {
+			kept = append(kept, p)
+		}
+	}
+	db.permissions = kept
+	return nil
+}
+
+func (db *DBMock) GetCommandPermissions(guildID string) ([]*structs.CommandPermission, error) {
+	db.Record("GetCommandPermissions", guildID)
+	db.Lock()
+	defer db.Unlock()
+	var perms []*structs.CommandPermission
+	for _, p := range db.permissions {
+		if p.GuildID == guildID {
+			c := *p
+			perms = append(perms, &c)
+		}
+	}
+	return perms, nil
+}
+
+func (db *DBMock) UpdateCooldown(key string, update func(c *structs.Cooldown) error) error {
+	db.Record("UpdateCooldown", key)
+	db.Lock()
+	defer db.Unlock()
+	c := structs.Cooldown{Key: key, ExpiresAt: time.Now()}
+	if e, ok := db.cooldowns[key]; ok {
+		c = *e
+	}
+	if err := update(&c); err != nil {
+		return err
+	}
+	db.cooldowns[key] = &c
+	return nil
+}
+
+func (db *DBMock) DeleteCooldown(key string) error {
+	db.Record("DeleteCooldown", key)
+	db.Lock()
+	defer db.Unlock()
+	delete(db.cooldowns, key)
+	return nil
+}
+
+func (db *DBMock) DeleteExpiredCooldowns() error {
+	db.Record("DeleteExpiredCooldowns")
+	db.Lock()
+	defer db.Unlock()
+	now := time.Now()
+	for key, c := range db.cooldowns {
+		if c.ExpiresAt.Before(now) {
+			delete(db.cooldowns, key)
+		}
+	}
+	return nil
+}
+
+func (db *DBMock) CreateScheduledJob(j *structs.ScheduledJob) error {
+	db.Record("CreateScheduledJob", j.Kind)
+	db.Lock()
+	defer db.Unlock()
+	db.nextJobID++
+	j.UID = db.nextJobID
+	c := *j
+	db.jobs[j.UID] = &c
+	return nil
+}
+
+func (db *DBMock) ClaimScheduledJobs(now, until time.Time, claimer string, limit int) ([]*structs.ScheduledJob, error) {
+	db.Record("ClaimScheduledJobs", claimer, limit)
+	db.Lock()
+	defer db.Unlock()
+	var claimed []*structs.ScheduledJob
+	for _, j := range db.sortedJobs() {
+		if len(claimed) == limit {
+			break
+		}
+		if j.Failed || j.RunAt.After(now) || (j.ClaimedUntil != nil && !j.ClaimedUntil.Before(now)) {
+			continue
+		}
+		u := until
+		j.ClaimedBy, j.ClaimedUntil = claimer, &u
+		c := *j
+		claimed = append(claimed, &c)
+	}
+	return claimed, nil
+}
+
+func (db *DBMock) DeleteScheduledJob(uid int64) (bool, error) {
+	db.Record("DeleteScheduledJob", uid)
+	db.Lock()
+	defer db.Unlock()
+	_, ok := db.jobs[uid]
+	delete(db.jobs, uid)
+	return ok, nil
+}
+
+// claimedJob returns the job uid if it is claimed by claimer. The lock must be held.
+func (db *DBMock) claimedJob(uid int64, claimer string) (*structs.ScheduledJob, bool) {
+	j, ok := db.jobs[uid]
+	return j, ok && j.ClaimedBy == claimer
+}
+
+func (db *DBMock) CompleteScheduledJob(uid int64, claimer string) (bool, error) {
+	db.Record("CompleteScheduledJob", uid, claimer)
+	db.Lock()
+	defer db.Unlock()
+	if _, ok := db.claimedJob(uid, claimer); !ok {
+		return false, nil
+	}
+	delete(db.jobs, uid)
+	return true, nil
+}
+
+func (db *DBMock) RetryScheduledJob(uid int64, claimer string, runAt time.Time, lastErr string) (bool, error) {
+	db.Record("RetryScheduledJob", uid, claimer, lastErr)
+	db.Lock()
+	defer db.Unlock()
+	j, ok := db.claimedJob(uid, claimer)
+	if !ok {
+		return false, nil
+	}
+	j.RunAt = runAt
+	j.Attempts++
+	j.LastError = lastErr
+	j.ClaimedBy, j.ClaimedUntil = "", nil
+	return true, nil
+}
+
+func (db *DBMock) FailScheduledJob(uid int64, claimer, lastErr string) (bool, error) {
+	db.Record("FailScheduledJob", uid, claimer, lastErr)
+	db.Lock()
+	defer db.Unlock()
+	j, ok := db.claimedJob(uid, claimer)
+	if !ok {
+		return false, nil
+	}
+	j.Failed = true
+	j.Attempts++
+	j.LastError = lastErr
+	j.ClaimedBy, j.ClaimedUntil = "", nil
+	return true, nil
+}
+
+func (db *DBMock) GetScheduledJobs() ([]*structs.ScheduledJob, error) {
+	db.Record("GetScheduledJobs")
+	db.Lock()
+	defer db.Unlock()
+	jobs := db.sortedJobs()
+	for i, j := range jobs {
+		c := *j
+		jobs[i] = &c
+	}
+	return jobs, nil
+}
+
+func (db *DBMock) ClaimRecurringJobRun(name string, due time.Time, claimer string) (bool, error) {
+	db.Record("ClaimRecurringJobRun", name, claimer)
+	db.Lock()
+	defer db.Unlock()
+	if last, ok := db.runs[name]; ok && !due.After(last) {
+		return false, nil
+	}
+	db.runs[name] = due
+	return true, nil
+}
+
+func (db *DBMock) sortedJobs() []*structs.ScheduledJob {
+	jobs := make([]*structs.ScheduledJob, 0, len(db.jobs))
+	for _, j := range db.jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].RunAt.Equal(jobs[j].RunAt) {
+			return jobs[i].UID < jobs[j].UID
+		}
+		return jobs[i].RunAt.Before(jobs[j].RunAt)
+	})
+	return jobs
+}
+
+// UpsertCount is not recorded, as it is called for every event.
+func (db *DBMock) UpsertCount(eventType string, sentAt time.Time) error {
+	db.Lock()
+	defer db.Unlock()
+	db.events[eventType]++
+	return nil
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/intrntsrfr/meido/internal/structs"
@@ -13,6 +14,10 @@ type PsqlDB struct {
 	pool    *sqlx.DB
 	connStr string
 	IGuildDB
+	IGuildToggleDB
+	ICommandPermissionDB
+	ICooldownDB
+	IScheduledJobDB
 	ICommandLogDB
 	IProcessedEventsDB
 }
@@ -28,6 +33,10 @@ func NewPSQLDatabase(connStr string) (*PsqlDB, error) {
 		connStr: connStr,
 	}
 	db.IGuildDB = &GuildDB{db}
+	db.IGuildToggleDB = &GuildToggleDB{db}
+	db.ICommandPermissionDB = &CommandPermissionDB{db}
+	db.ICooldownDB = &CooldownDB{db}
+	db.IScheduledJobDB = &ScheduledJobDB{db}
 	db.ICommandLogDB = &CommandLogDB{db}
 	db.IProcessedEventsDB = &ProcessedEventsDB{db}
 	return db, nil
@@ -73,11 +82,170 @@ func (db *GuildDB) GetGuild(guildID string) (*structs.Guild, error) {
 }
 
 func (db *GuildDB) UpdateGuild(g *structs.Guild) error {
-	_, err := db.Conn().Exec("UPDATE guild SET use_warns=$1, max_warns=$2, warn_duration=$3, automod_log_channel_id=$4, fishing_channel_id=$5, joined_at=$6 WHERE guild_id=$7",
-		g.UseWarns, g.MaxWarns, g.WarnDuration, g.AutomodLogChannelID, g.FishingChannelID, g.JoinedAt, g.GuildID)
+	_, err := db.Conn().Exec("UPDATE guild SET use_warns=$1, max_warns=$2, warn_duration=$3, automod_log_channel_id=$4, fishing_channel_id=$5, joined_at=$6, prefix=$7 WHERE guild_id=$8",
+		g.UseWarns, g.MaxWarns, g.WarnDuration, g.AutomodLogChannelID, g.FishingChannelID, g.JoinedAt, g.Prefix, g.GuildID)
 	return err
 }
 
+type GuildToggleDB struct {
+	DB
+}
+
+func (db *GuildToggleDB) CreateGuildToggle(guildID, kind, name string) error {
+	_, err := db.Conn().Exec("INSERT INTO guild_toggle VALUES($1, $2, $3) ON CONFLICT DO NOTHING", guildID, kind, name)
+	return err
+}
+
+func (db *GuildToggleDB) DeleteGuildToggle(guildID, kind, name string) error {
+	_, err := db.Conn().Exec("DELETE FROM guild_toggle WHERE guild_id=$1 AND kind=$2 AND name=$3", guildID, kind, name)
+	return err
+}
+
+func (db *GuildToggleDB) GetGuildToggles(guildID string) ([]*structs.GuildToggle, error) {
+	var toggles []*structs.GuildToggle
+	err := db.Conn().Select(&toggles, "SELECT * FROM guild_toggle WHERE guild_id=$1", guildID)
+	return toggles, err
+}
+
+type CommandPermissionDB struct {
+	DB
+}
+
+func (db *CommandPermissionDB) UpsertCommandPermission(p *structs.CommandPermission) error {
+	query := `
+    INSERT INTO command_permission (guild_id, command, target, target_id, allow)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (guild_id, command, target, target_id)
+    DO UPDATE SET allow = $5
+    `
+
+	_, err := db.Conn().Exec(query, p.GuildID, p.Command, p.Target, p.TargetID, p.Allow)
+	return err
+}
+
+func (db *CommandPermissionDB) DeleteCommandPermission(guildID, command, target, targetID string) error {
+	_, err := db.Conn().Exec("DELETE FROM command_permission WHERE guild_id=$1 AND command=$2 AND target=$3 AND target_id=$4",
+		guildID, command, target, targetID)
+	return err
+}
+
+func (db *CommandPermissionDB) DeleteCommandPermissions(guildID, command string) error {
+	_, err := db.Conn().Exec("DELETE FROM command_permission WHERE guild_id=$1 AND command=$2", guildID, command)
+	return err
+}
+
+func (db *CommandPermissionDB) GetCommandPermissions(guildID string) ([]*structs.CommandPermission, error) {
+	var perms []*structs.CommandPermission
+	err := db.Conn().Select(&perms, "SELECT * FROM command_permission WHERE guild_id=$1", guildID)
+	return perms, err
+}
+
+type CooldownDB struct {
+	DB
+}
+
+func (db *CooldownDB) UpdateCooldown(key string, update func(c *structs.Cooldown) error) error {
+	tx, err := db.Conn().Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec("INSERT INTO cooldown VALUES ($1, DEFAULT, now()) ON CONFLICT DO NOTHING", key); err != nil {
+		return err
+	}
+	var c structs.Cooldown
+	if err := tx.Get(&c, "SELECT * FROM cooldown WHERE cooldown_key=$1 FOR UPDATE", key); err != nil {
+		return err
+	}
+	if err := update(&c); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE cooldown SET state=$1, expires_at=$2 WHERE cooldown_key=$3", c.State, c.ExpiresAt, key); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db *CooldownDB) DeleteCooldown(key string) error {
+	_, err := db.Conn().Exec("DELETE FROM cooldown WHERE cooldown_key=$1", key)
+	return err
+}
+
+func (db *CooldownDB) DeleteExpiredCooldowns() error {
+	_, err := db.Conn().Exec("DELETE FROM cooldown WHERE expires_at < now()")
+	return err
+}
+
+type ScheduledJobDB struct {
+	DB
+}
+
+func (db *ScheduledJobDB) CreateScheduledJob(j *structs.ScheduledJob) error {
+	return db.Conn().Get(&j.UID, "INSERT INTO scheduled_job (kind, payload, run_at) VALUES ($1, $2, $3) RETURNING uid",
+		j.Kind, j.Payload, j.RunAt)
+}
+
+func (db *ScheduledJobDB) ClaimScheduledJobs(now, until time.Time, claimer string, limit int) ([]*structs.ScheduledJob, error) {
+	query := `
+    UPDATE scheduled_job SET claimed_by=$1, claimed_until=$2
+    WHERE uid IN (
+        SELECT uid FROM scheduled_job
+        WHERE NOT failed AND run_at <= $3 AND (claimed_until IS NULL OR claimed_until < $3)
+        ORDER BY run_at
+        LIMIT $4
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING *
+    `
+
+	var jobs []*structs.ScheduledJob
+	err := db.Conn().Select(&jobs, query, claimer, until, now, limit)
+	return jobs, err
+}
+
+func (db *ScheduledJobDB) DeleteScheduledJob(uid int64) (bool, error) {
+	return affected(db.Conn().Exec("DELETE FROM scheduled_job WHERE uid=$1", uid))
+}
+
+func (db *ScheduledJobDB) CompleteScheduledJob(uid int64, claimer string) (bool, error) {
+	return affected(db.Conn().Exec("DELETE FROM scheduled_job WHERE uid=$1 AND claimed_by=$2", uid, claimer))
+}
+
+func (db *ScheduledJobDB) RetryScheduledJob(uid int64, claimer string, runAt time.Time, lastErr string) (bool, error) {
+	return affected(db.Conn().Exec(`UPDATE scheduled_job SET run_at=$3, attempts=attempts+1, last_error=$4, claimed_by='', claimed_until=NULL
+		WHERE uid=$1 AND claimed_by=$2`, uid, claimer, runAt, lastErr))
+}
+
+func (db *ScheduledJobDB) FailScheduledJob(uid int64, claimer, lastErr string) (bool, error) {
+	return affected(db.Conn().Exec(`UPDATE scheduled_job SET failed=true, attempts=attempts+1, last_error=$3, claimed_by='', claimed_until=NULL
+		WHERE uid=$1 AND claimed_by=$2`, uid, claimer, lastErr))
+}
+
+func (db *ScheduledJobDB) GetScheduledJobs() ([]*structs.ScheduledJob, error) {
+	var jobs []*structs.ScheduledJob
+	err := db.Conn().Select(&jobs, "SELECT * FROM scheduled_job ORDER BY run_at, uid")
+	return jobs, err
+}
+
+func (db *ScheduledJobDB) ClaimRecurringJobRun(name string, due time.Time, claimer string) (bool, error) {
+	query := `
+    INSERT INTO recurring_job_run (name, due_at, claimed_by) VALUES ($1, $2, $3)
+    ON CONFLICT (name) DO UPDATE SET due_at=EXCLUDED.due_at, claimed_by=EXCLUDED.claimed_by
+    WHERE recurring_job_run.due_at < EXCLUDED.due_at
+    `
+	return affected(db.Conn().Exec(query, name, due, claimer))
+}
+
+// affected returns whether a statement changed any rows.
+func affected(res sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 type ProcessedEventsDB struct {
 	DB
 }
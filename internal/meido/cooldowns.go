@@ -0,0 +1,50 @@
+package meido
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/intrntsrfr/meido/internal/database"
+	"github.com/intrntsrfr/meido/internal/structs"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+)
+
+// cooldownStore is a bot.CooldownStore that keeps cooldowns in the cooldown table,
+// so they survive restarts and are shared between processes.
+type cooldownStore struct {
+	db database.ICooldownDB
+}
+
+func newCooldownStore(db database.ICooldownDB) *cooldownStore {
+	return &cooldownStore{db: db}
+}
+
+func (s *cooldownStore) Take(key string, limit bot.CooldownLimit) (time.Duration, bool, error) {
+	var (
+		wait time.Duration
+		ok   bool
+	)
+	err := s.db.UpdateCooldown(key, func(c *structs.Cooldown) error {
+		now := time.Now()
+		var state bot.CooldownState
+		if now.Before(c.ExpiresAt) {
+			if err := json.Unmarshal(c.State, &state); err != nil {
+				return err
+			}
+		}
+		wait, ok = limit.Take(&state, now)
+
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		c.State = data
+		c.ExpiresAt = limit.Expires(&state)
+		return nil
+	})
+	return wait, ok, err
+}
+
+func (s *cooldownStore) Reset(key string) error {
+	return s.db.DeleteCooldown(key)
+}
@@ -0,0 +1,105 @@
+package meido
+
+import (
+	"time"
+
+	"github.com/intrntsrfr/meido/internal/database"
+	"github.com/intrntsrfr/meido/internal/structs"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+)
+
+// jobStore is a bot.JobStore that keeps one-shot jobs in the scheduled_job table,
+// so they survive restarts and are shared between processes. Runs of recurring
+// jobs are claimed in the recurring_job_run table.
+type jobStore struct {
+	db database.IScheduledJobDB
+}
+
+func newJobStore(db database.IScheduledJobDB) *jobStore {
+	return &jobStore{db: db}
+}
+
+func (s *jobStore) AddJob(job *bot.ScheduledJob) error {
+	j := &structs.ScheduledJob{Kind: job.Kind, Payload: job.Payload, RunAt: job.RunAt}
+	if err := s.db.CreateScheduledJob(j); err != nil {
+		return err
+	}
+	job.ID = j.UID
+	return nil
+}
+
+func (s *jobStore) ClaimJobs(now, until time.Time, claimer string, limit int) ([]*bot.ScheduledJob, error) {
+	jobs, err := s.db.ClaimScheduledJobs(now, until, claimer, limit)
+	if err != nil {
+		return nil, err
+	}
+	return toBotJobs(jobs), nil
+}
+
+func (s *jobStore) CompleteJob(id int64, claimer string) error {
+	return claimed(s.db.CompleteScheduledJob(id, claimer))
+}
+
+func (s *jobStore) RetryJob(id int64, claimer string, runAt time.Time, lastErr string) error {
+	return claimed(s.db.RetryScheduledJob(id, claimer, runAt, lastErr))
+}
+
+func (s *jobStore) FailJob(id int64, claimer, lastErr string) error {
+	return claimed(s.db.FailScheduledJob(id, claimer, lastErr))
+}
+
+// claimed turns a job that was not changed, as it is not claimed by the caller,
+// into bot.ErrJobClaimLost.
+func claimed(changed bool, err error) error {
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return bot.ErrJobClaimLost
+	}
+	return nil
+}
+
+func (s *jobStore) CancelJob(id int64) error {
+	deleted, err := s.db.DeleteScheduledJob(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return bot.ErrJobNotFound
+	}
+	return nil
+}
+
+func (s *jobStore) Jobs() ([]*bot.ScheduledJob, error) {
+	jobs, err := s.db.GetScheduledJobs()
+	if err != nil {
+		return nil, err
+	}
+	return toBotJobs(jobs), nil
+}
+
+func (s *jobStore) ClaimRun(name string, due time.Time, claimer string) (bool, error) {
+	return s.db.ClaimRecurringJobRun(name, due, claimer)
+}
+
+func toBotJobs(jobs []*structs.ScheduledJob) []*bot.ScheduledJob {
+	res := make([]*bot.ScheduledJob, 0, len(jobs))
+	for _, j := range jobs {
+		job := &bot.ScheduledJob{
+			ID:        j.UID,
+			Kind:      j.Kind,
+			Payload:   j.Payload,
+			RunAt:     j.RunAt,
+			Attempts:  j.Attempts,
+			LastError: j.LastError,
+			Failed:    j.Failed,
+			ClaimedBy: j.ClaimedBy,
+		}
+		if j.ClaimedUntil != nil {
+			job.ClaimedUntil = *j.ClaimedUntil
+		}
+		res = append(res, job)
+	}
+	return res
+}
@@ -61,6 +61,15 @@ func (m *Meido) addHandlers() {
 		m.countProcessedEvent(bot.BotEventModalSubmitPanicked.String())
 	})
 
+	m.Bot.AddHandler(func(evt *bot.CommandTimedOut) {
+		m.logCommandTimedOut(evt)
+		m.countProcessedEvent(bot.BotEventCommandTimedOut.String())
+	})
+
+	m.Bot.AddHandler(func(evt *bot.CommandFailed) {
+		m.countProcessedEvent(bot.BotEventCommandFailed.String())
+	})
+
 	m.Bot.AddHandler(func(evt *bot.MessageProcessed) {
 		m.countProcessedEvent(bot.BotEventMessageProcessed.String())
 	})
@@ -108,6 +117,16 @@ func (m *Meido) logCommandPanicked(cmd *bot.CommandPanicked) {
 	)
 }
 
+func (m *Meido) logCommandTimedOut(evt *bot.CommandTimedOut) {
+	m.logger.Warn("Command timed out",
+		zap.String("type", evt.Invocation.Type.String()),
+		zap.String("name", evt.Invocation.Name),
+		zap.String("channelID", evt.Invocation.ChannelID()),
+		zap.String("userID", evt.Invocation.AuthorID()),
+		zap.Duration("timeout", evt.Timeout),
+	)
+}
+
 func (m *Meido) logPassiveRan(pas *bot.PassiveRan) {
 	m.logger.Debug("Passive",
 		zap.String("name", pas.Passive.Name),
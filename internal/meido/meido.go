@@ -19,15 +19,17 @@ import (
 	"github.com/intrntsrfr/meido/internal/module/utility"
 	"github.com/intrntsrfr/meido/pkg/mio"
 	"github.com/intrntsrfr/meido/pkg/mio/bot"
+	"github.com/intrntsrfr/meido/pkg/mio/export"
 	"github.com/intrntsrfr/meido/pkg/utils"
 	"go.uber.org/zap"
 )
 
 type Meido struct {
-	Bot    *bot.Bot
-	db     database.DB
-	logger mio.Logger
-	config *utils.Config
+	Bot       *bot.Bot
+	db        database.DB
+	logger    mio.Logger
+	config    *utils.Config
+	exporters []*export.Exporter
 }
 
 func New(config *utils.Config, db database.DB) *Meido {
@@ -36,6 +38,16 @@ func New(config *utils.Config, db database.DB) *Meido {
 	b := bot.NewBotBuilder(config).
 		WithDefaultHandlers().
 		WithLogger(logger).
+		WithPrefixResolver(newPrefixResolver(db, config.GetString("prefix"))).
+		WithToggleStore(newToggleStore(db)).
+		WithPermissionStore(newPermissionStore(db)).
+		WithCooldownStore(newCooldownStore(db)).
+		WithJobStore(newJobStore(db)).
+		WithGracePeriod(time.Duration(config.GetInt("shutdown_grace_period")) * time.Second).
+		WithCommandSync(bot.CommandSyncConfig{
+			DryRun:   config.GetBool("command_sync_dry_run"),
+			GuildIDs: config.GetStringSlice("dev_guild_ids"),
+		}).
 		Build()
 
 	return &Meido{
@@ -47,14 +59,56 @@ func New(config *utils.Config, db database.DB) *Meido {
 }
 
 func (m *Meido) Run(ctx context.Context, useDefHandlers bool) error {
+	if err := m.addExporters(); err != nil {
+		return err
+	}
 	m.addHandlers()
 	m.registerModules()
 	m.registerDiscordHandlers()
+	if err := m.registerJobs(); err != nil {
+		return err
+	}
 	return m.Bot.Run(ctx)
 }
 
+// Close shuts the bot down gracefully, and closes the database once running
+// commands are done with it.
 func (m *Meido) Close() {
 	m.Bot.Close()
+	for _, e := range m.exporters {
+		if err := e.Close(); err != nil {
+			m.logger.Error("Closing event exporter failed", zap.Error(err))
+		}
+	}
+	if err := m.db.Close(); err != nil {
+		m.logger.Error("Closing database failed", zap.Error(err))
+	}
+}
+
+// addExporters exports bot events to the file and webhook in the config, if any.
+func (m *Meido) addExporters() error {
+	var sinks []export.Sink
+	if path := m.config.GetString("event_export_file"); path != "" {
+		sink, err := export.NewFileSink(path, 0, 0)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	if url := m.config.GetString("event_export_webhook"); url != "" {
+		sink := export.NewWebhookSink(url)
+		if token := m.config.GetString("event_export_webhook_token"); token != "" {
+			sink.Header.Set("Authorization", "Bearer "+token)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	for _, sink := range sinks {
+		e := export.NewExporter(sink, export.ExporterConfig{}, m.logger.Named("export"))
+		e.Attach(m.Bot.EventBus)
+		m.exporters = append(m.exporters, e)
+	}
+	return nil
 }
 
 func (m *Meido) registerModules() {
@@ -79,7 +133,27 @@ func (m *Meido) registerModules() {
 
 func (m *Meido) registerDiscordHandlers() {
 	m.Bot.Discord.AddEventHandler(insertGuild(m))
-	m.Bot.Discord.AddEventHandlerOnce(statusLoop(m))
+}
+
+func (m *Meido) registerJobs() error {
+	// every process sets the presence of its own shards
+	err := m.Bot.Scheduler.Add(&bot.Job{
+		Name:     "meido.status",
+		Schedule: bot.Every(time.Second * 15),
+		Local:    true,
+		Run:      m.updateStatus(),
+	})
+	if err != nil {
+		return err
+	}
+	return m.Bot.Scheduler.Add(&bot.Job{
+		Name:     "meido.clear_expired_cooldowns",
+		Schedule: bot.MustParseCron("@hourly"),
+		Jitter:   time.Minute * 5,
+		Run: func(ctx context.Context) error {
+			return m.db.DeleteExpiredCooldowns()
+		},
+	})
 }
 
 func insertGuild(m *Meido) func(s *discordgo.Session, g *discordgo.GuildCreate) {
@@ -99,48 +173,47 @@ func insertGuild(m *Meido) func(s *discordgo.Session, g *discordgo.GuildCreate)
 
 const totalStatusDisplays = 3
 
-func statusLoop(m *Meido) func(s *discordgo.Session, r *discordgo.Ready) {
-	statusTimer := time.NewTicker(time.Second * 15)
-	return func(s *discordgo.Session, r *discordgo.Ready) {
-		display := 0
-		go func() {
-			for range statusTimer.C {
-				var (
-					name       string
-					statusType discordgo.ActivityType
-				)
-				switch display {
-				case 0:
-					srvCount := m.Bot.Discord.GuildCount()
-					name = fmt.Sprintf("%v servers", srvCount)
-					statusType = discordgo.ActivityTypeWatching
-				case 1:
-					name = "for /help | m?help"
-					statusType = discordgo.ActivityTypeWatching
-				case 2:
-					name = "around with fish"
+// updateStatus returns a job that cycles through the statuses of the bot.
+func (m *Meido) updateStatus() bot.JobFunc {
+	display := 0
+	return func(ctx context.Context) error {
+		var (
+			name       string
+			statusType discordgo.ActivityType
+		)
+		switch display {
+		case 0:
+			srvCount := m.Bot.Discord.GuildCount()
+			name = fmt.Sprintf("%v servers", srvCount)
+			statusType = discordgo.ActivityTypeWatching
+		case 1:
+			name = fmt.Sprintf("for /help | %vhelp", m.Bot.Prefixes.DefaultPrefix())
+			statusType = discordgo.ActivityTypeWatching
+		case 2:
+			name = "around with fish"
+			statusType = discordgo.ActivityTypeGame
+			/*
+				case 3:
+					name = "m?fish"
 					statusType = discordgo.ActivityTypeGame
-					/*
-						case 3:
-							name = "m?fish"
-							statusType = discordgo.ActivityTypeGame
-						case 4:
-							name = "Changed custom role commands"
-							statusType = discordgo.ActivityTypeGame
-						case 5:
-							name = "Auto roles are added!! Wow!!"
-							statusType = discordgo.ActivityTypeGame
-					*/
-				}
-
-				_ = s.UpdateStatusComplex(discordgo.UpdateStatusData{
-					Activities: []*discordgo.Activity{{
-						Name: name,
-						Type: statusType,
-					}},
-				})
-				display = (display + 1) % totalStatusDisplays
-			}
-		}()
+				case 4:
+					name = "Changed custom role commands"
+					statusType = discordgo.ActivityTypeGame
+				case 5:
+					name = "Auto roles are added!! Wow!!"
+					statusType = discordgo.ActivityTypeGame
+			*/
+		}
+
+		for _, s := range m.Bot.Discord.Sessions {
+			_ = s.UpdateStatusComplex(discordgo.UpdateStatusData{
+				Activities: []*discordgo.Activity{{
+					Name: name,
+					Type: statusType,
+				}},
+			})
+		}
+		display = (display + 1) % totalStatusDisplays
+		return nil
 	}
 }
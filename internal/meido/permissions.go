@@ -0,0 +1,103 @@
+package meido
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/intrntsrfr/meido/internal/database"
+	"github.com/intrntsrfr/meido/internal/structs"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+)
+
+// permissionStore is a bot.PermissionStore that stores permission rules in the
+// command_permission table. The rules of a guild are loaded into an in-memory
+// store the first time they are needed, so commands don't hit the DB every time.
+type permissionStore struct {
+	sync.Mutex
+	db     database.ICommandPermissionDB
+	cache  *bot.MemoryPermissionStore
+	loaded map[string]bool
+}
+
+func newPermissionStore(db database.ICommandPermissionDB) *permissionStore {
+	return &permissionStore{
+		db:     db,
+		cache:  bot.NewMemoryPermissionStore(),
+		loaded: make(map[string]bool),
+	}
+}
+
+func (s *permissionStore) Rules(guildID, command string) ([]*bot.PermissionRule, error) {
+	if err := s.load(guildID); err != nil {
+		return nil, err
+	}
+	return s.cache.Rules(guildID, command)
+}
+
+func (s *permissionStore) GuildRules(guildID string) ([]*bot.PermissionRule, error) {
+	if err := s.load(guildID); err != nil {
+		return nil, err
+	}
+	return s.cache.GuildRules(guildID)
+}
+
+func (s *permissionStore) SetRule(rule *bot.PermissionRule) error {
+	if err := s.load(rule.GuildID); err != nil {
+		return err
+	}
+	err := s.db.UpsertCommandPermission(&structs.CommandPermission{
+		GuildID:  rule.GuildID,
+		Command:  strings.ToLower(rule.Command),
+		Target:   string(rule.Target),
+		TargetID: rule.TargetID,
+		Allow:    rule.Allow,
+	})
+	if err != nil {
+		return err
+	}
+	return s.cache.SetRule(rule)
+}
+
+func (s *permissionStore) RemoveRule(guildID, command string, target bot.PermissionTarget, targetID string) error {
+	if err := s.load(guildID); err != nil {
+		return err
+	}
+	if err := s.db.DeleteCommandPermission(guildID, strings.ToLower(command), string(target), targetID); err != nil {
+		return err
+	}
+	return s.cache.RemoveRule(guildID, command, target, targetID)
+}
+
+func (s *permissionStore) ClearRules(guildID, command string) error {
+	if err := s.load(guildID); err != nil {
+		return err
+	}
+	if err := s.db.DeleteCommandPermissions(guildID, strings.ToLower(command)); err != nil {
+		return err
+	}
+	return s.cache.ClearRules(guildID, command)
+}
+
+// load fetches the rules of a guild into the cache, unless they are already there.
+func (s *permissionStore) load(guildID string) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.loaded[guildID] {
+		return nil
+	}
+	perms, err := s.db.GetCommandPermissions(guildID)
+	if err != nil {
+		return err
+	}
+	for _, p := range perms {
+		_ = s.cache.SetRule(&bot.PermissionRule{
+			GuildID:  p.GuildID,
+			Command:  p.Command,
+			Target:   bot.PermissionTarget(p.Target),
+			TargetID: p.TargetID,
+			Allow:    p.Allow,
+		})
+	}
+	s.loaded[guildID] = true
+	return nil
+}
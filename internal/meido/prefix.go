@@ -0,0 +1,84 @@
+package meido
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/intrntsrfr/meido/internal/database"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+)
+
+// prefixResolver is a bot.PrefixResolver that stores guild prefixes in the
+// guild table, caching them so messages don't hit the DB every time.
+type prefixResolver struct {
+	sync.RWMutex
+	db            database.IGuildDB
+	defaultPrefix string
+	cache         map[string]string
+}
+
+func newPrefixResolver(db database.IGuildDB, defaultPrefix string) *prefixResolver {
+	if defaultPrefix == "" {
+		defaultPrefix = bot.DefaultPrefix
+	}
+	return &prefixResolver{
+		db:            db,
+		defaultPrefix: defaultPrefix,
+		cache:         make(map[string]string),
+	}
+}
+
+func (r *prefixResolver) Prefix(guildID string) string {
+	if guildID == "" {
+		return r.defaultPrefix
+	}
+
+	r.RLock()
+	prefix, ok := r.cache[guildID]
+	r.RUnlock()
+	if !ok {
+		g, err := r.db.GetGuild(guildID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// guilds are inserted with no prefix, so the default is cached until
+			// one is set, instead of looking the guild up for every message
+			prefix = ""
+		case err != nil:
+			return r.defaultPrefix
+		default:
+			prefix = g.Prefix
+		}
+		r.Lock()
+		r.cache[guildID] = prefix
+		r.Unlock()
+	}
+
+	if prefix == "" {
+		return r.defaultPrefix
+	}
+	return prefix
+}
+
+func (r *prefixResolver) SetPrefix(guildID, prefix string) error {
+	if prefix == r.defaultPrefix {
+		prefix = ""
+	}
+	g, err := r.db.GetGuild(guildID)
+	if err != nil {
+		return err
+	}
+	g.Prefix = prefix
+	if err := r.db.UpdateGuild(g); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.cache[guildID] = prefix
+	return nil
+}
+
+func (r *prefixResolver) DefaultPrefix() string {
+	return r.defaultPrefix
+}
@@ -0,0 +1,107 @@
+package meido
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/intrntsrfr/meido/internal/database"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+)
+
+// toggleStore is a bot.ToggleStore that stores what is disabled in each guild in
+// the guild_toggle table, caching it so messages don't hit the DB every time.
+type toggleStore struct {
+	sync.RWMutex
+	db    database.IGuildToggleDB
+	cache map[string]map[bot.Toggle]struct{}
+}
+
+func newToggleStore(db database.IGuildToggleDB) *toggleStore {
+	return &toggleStore{
+		db:    db,
+		cache: make(map[string]map[bot.Toggle]struct{}),
+	}
+}
+
+func (s *toggleStore) IsDisabled(guildID string, kind bot.ToggleKind, name string) bool {
+	if guildID == "" {
+		return false
+	}
+	toggles, err := s.load(guildID)
+	if err != nil {
+		return false
+	}
+	s.RLock()
+	defer s.RUnlock()
+	_, ok := toggles[bot.Toggle{Kind: kind, Name: strings.ToLower(name)}]
+	return ok
+}
+
+func (s *toggleStore) SetDisabled(guildID string, kind bot.ToggleKind, name string, disabled bool) error {
+	name = strings.ToLower(name)
+	toggles, err := s.load(guildID)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		err = s.db.CreateGuildToggle(guildID, string(kind), name)
+	} else {
+		err = s.db.DeleteGuildToggle(guildID, string(kind), name)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	t := bot.Toggle{Kind: kind, Name: name}
+	if disabled {
+		toggles[t] = struct{}{}
+	} else {
+		delete(toggles, t)
+	}
+	return nil
+}
+
+func (s *toggleStore) Disabled(guildID string) ([]bot.Toggle, error) {
+	toggles, err := s.load(guildID)
+	if err != nil {
+		return nil, err
+	}
+	s.RLock()
+	defer s.RUnlock()
+	list := make([]bot.Toggle, 0, len(toggles))
+	for t := range toggles {
+		list = append(list, t)
+	}
+	bot.SortToggles(list)
+	return list, nil
+}
+
+// load returns the cached toggles of a guild, fetching them from the DB if they
+// are not cached yet.
+func (s *toggleStore) load(guildID string) (map[bot.Toggle]struct{}, error) {
+	s.RLock()
+	toggles, ok := s.cache[guildID]
+	s.RUnlock()
+	if ok {
+		return toggles, nil
+	}
+
+	rows, err := s.db.GetGuildToggles(guildID)
+	if err != nil {
+		return nil, err
+	}
+	toggles = make(map[bot.Toggle]struct{}, len(rows))
+	for _, r := range rows {
+		toggles[bot.Toggle{Kind: bot.ToggleKind(r.Kind), Name: r.Name}] = struct{}{}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if cached, ok := s.cache[guildID]; ok {
+		return cached, nil
+	}
+	s.cache[guildID] = toggles
+	return toggles, nil
+}
@@ -34,6 +34,7 @@ func (m *module) Hook() error {
 	return m.RegisterCommands(
 		newToggleCommandCommand(m),
 		newMessageCommand(m),
+		newJobsCommand(m),
 	)
 }
 
@@ -42,8 +43,8 @@ func newMessageCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "message",
 		Description:      "Sends a message to a channel",
-		Triggers:         []string{"m?msg"},
-		Usage:            "m?msg [channelID] [message]",
+		Triggers:         []string{"msg"},
+		Usage:            "msg [channelID] [message]",
 		Cooldown:         0,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -84,8 +85,8 @@ func newToggleCommandCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "togglecommand",
 		Description:      "Enables or disables a command. Bot owner only.",
-		Triggers:         []string{"m?togglecommand", "m?tc"},
-		Usage:            "m?tc ping",
+		Triggers:         []string{"togglecommand", "tc"},
+		Usage:            "tc ping",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -95,7 +96,10 @@ func newToggleCommandCommand(m *module) *bot.ModuleCommand {
 		AllowDMs:         true,
 		Enabled:          true,
 		Execute: func(msg *discord.DiscordMessage) {
-			if cmd, err := m.Bot.FindCommand(msg.RawContent()); err == nil {
+			if len(msg.Args()) < 2 {
+				return
+			}
+			if cmd, err := m.Bot.FindCommand(strings.Join(msg.Args()[1:], " ")); err == nil {
 				if cmd.Name == "togglecommand" {
 					return
 				}
@@ -110,6 +114,69 @@ func newToggleCommandCommand(m *module) *bot.ModuleCommand {
 	}
 }
 
+func newJobsCommand(m *module) *bot.ModuleCommand {
+	return &bot.ModuleCommand{
+		Mod:              m,
+		Name:             "jobs",
+		Description:      "Shows the status of scheduled jobs. Bot owner only.",
+		Triggers:         []string{"jobs"},
+		Usage:            "jobs",
+		Cooldown:         time.Second * 2,
+		CooldownScope:    bot.CooldownScopeChannel,
+		RequiredPerms:    0,
+		CheckBotPerms:    false,
+		RequiresUserType: bot.UserTypeBotOwner,
+		AllowedTypes:     discord.MessageTypeCreate,
+		AllowDMs:         true,
+		Enabled:          true,
+		Execute: func(msg *discord.DiscordMessage) {
+			var sb strings.Builder
+			sb.WriteString("**Recurring jobs**\n```\n")
+			for _, j := range m.Bot.Scheduler.Jobs() {
+				state := "ok"
+				if j.Running {
+					state = "running"
+				} else if j.LastError != "" {
+					state = "failed: " + j.LastError
+				}
+				next := "-"
+				if !j.NextRun.IsZero() {
+					next = time.Until(j.NextRun).Round(time.Second).String()
+				}
+				sb.WriteString(fmt.Sprintf("%v (%v)\n  runs: %v, failures: %v, next in: %v, %v\n",
+					j.Name, j.Schedule, j.Runs, j.Failures, next, state))
+			}
+			sb.WriteString("```")
+
+			pending, err := m.Bot.Scheduler.Pending()
+			if err != nil {
+				_, _ = msg.Reply("There was an issue, please try again!")
+				return
+			}
+			sb.WriteString(fmt.Sprintf("**One-shot jobs** (%v)\n", len(pending)))
+			if len(pending) > 0 {
+				sb.WriteString("```\n")
+				for i, j := range pending {
+					if i == 10 {
+						sb.WriteString(fmt.Sprintf("...and %v more\n", len(pending)-i))
+						break
+					}
+					state := "pending"
+					if j.Failed {
+						state = "failed: " + j.LastError
+					} else if j.ClaimedBy != "" && j.ClaimedUntil.After(time.Now()) {
+						state = "running on " + j.ClaimedBy
+					}
+					sb.WriteString(fmt.Sprintf("#%v %v at %v, attempts: %v, %v\n",
+						j.ID, j.Kind, j.RunAt.UTC().Format(time.RFC3339), j.Attempts, state))
+				}
+				sb.WriteString("```")
+			}
+			_, _ = msg.Reply(sb.String())
+		},
+	}
+}
+
 func newForwardDmsPassive(m *module) *bot.ModulePassive {
 	return &bot.ModulePassive{
 		Mod:          m,
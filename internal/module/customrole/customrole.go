@@ -1,10 +1,10 @@
 package customrole
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/g4s8/hexcolor"
@@ -34,9 +34,18 @@ func New(b *bot.Bot, db database.DB, logger mio.Logger) bot.Module {
 }
 
 func (m *module) Hook() error {
-	m.Bot.Discord.Sess.AddHandlerOnce(func(s *discordgo.Session, r *discordgo.Ready) {
-		go clearDeletedRoles(m)
-	})
+	if err := m.Bot.Scheduler.Add(&bot.Job{
+		Name:     "customrole.clear_deleted_roles",
+		Schedule: bot.Every(time.Hour),
+		Jitter:   time.Minute * 5,
+		Run:      m.clearDeletedRoles,
+	}); err != nil {
+		return err
+	}
+
+	if err := m.RegisterPages(newListCustomRolesPages(m)); err != nil {
+		return err
+	}
 
 	return m.RegisterCommands(
 		newSetCustomRoleCommand(m),
@@ -46,40 +55,42 @@ func (m *module) Hook() error {
 	)
 }
 
-func clearDeletedRoles(m *module) {
-	refreshTicker := time.NewTicker(time.Hour)
-	for range refreshTicker.C {
-		m.Logger.Info("Checking for deleted roles")
-		for _, g := range m.Bot.Discord.Guilds() {
-			if g.Unavailable {
-				continue
+// clearDeletedRoles removes the custom roles whose role was deleted from the guild.
+func (m *module) clearDeletedRoles(ctx context.Context) error {
+	m.Logger.Info("Checking for deleted roles")
+	for _, g := range m.Bot.Discord.Guilds() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if g.Unavailable {
+			continue
+		}
+		roles, err := m.db.GetCustomRolesByGuild(g.ID)
+		if err != nil {
+			continue
+		}
+		for _, ur := range roles {
+			hasRole := false
+			for _, gr := range g.Roles {
+				if gr.ID == ur.RoleID {
+					hasRole = true
+					break
+				}
 			}
-			roles, err := m.db.GetCustomRolesByGuild(g.ID)
-			if err != nil {
+			if hasRole {
 				continue
 			}
-			for _, ur := range roles {
-				hasRole := false
-				for _, gr := range g.Roles {
-					if gr.ID == ur.RoleID {
-						hasRole = true
-						break
-					}
-				}
-				if hasRole {
-					continue
-				}
-				// delete role from guild if it no longer exists
-				if err := m.db.DeleteCustomRole(ur.UID); err != nil {
-					m.Logger.Error("Delete custom role failed",
-						zap.Int("member roleID", ur.UID),
-						zap.String("guildID", ur.GuildID),
-						zap.String("roleID", ur.RoleID),
-						zap.String("userID", ur.UserID))
-				}
+			// delete role from guild if it no longer exists
+			if err := m.db.DeleteCustomRole(ur.UID); err != nil {
+				m.Logger.Error("Delete custom role failed",
+					zap.Int("member roleID", ur.UID),
+					zap.String("guildID", ur.GuildID),
+					zap.String("roleID", ur.RoleID),
+					zap.String("userID", ur.UserID))
 			}
 		}
 	}
+	return nil
 }
 
 func newSetCustomRoleCommand(m *module) *bot.ModuleCommand {
@@ -87,8 +98,8 @@ func newSetCustomRoleCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "setcustomrole",
 		Description:      "Sets or changes a custom role for a user",
-		Triggers:         []string{"m?setuserrole", "m?setcustomrole"},
-		Usage:            "m?setcustomrole [userID] [role]",
+		Triggers:         []string{"setuserrole", "setcustomrole"},
+		Usage:            "setcustomrole [userID] [role]",
 		Cooldown:         time.Second * 3,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageRoles,
@@ -161,8 +172,8 @@ func newRemoveCustomRoleCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "removecustomrole",
 		Description:      "Removes a custom role that is bound to a user",
-		Triggers:         []string{"m?removeuserrole", "m?removecustomrole"},
-		Usage:            "m?removecustomrole [userID]",
+		Triggers:         []string{"removeuserrole", "removecustomrole"},
+		Usage:            "removecustomrole [userID]",
 		Cooldown:         time.Second * 3,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageRoles,
@@ -206,8 +217,8 @@ func newMyRoleCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "myrole",
 		Description:      "Displays a users bound role, or lets the user change the name or color of their bound role",
-		Triggers:         []string{"m?myrole"},
-		Usage:            "m?myrole | m?myrole 123123123123 | m?myrole color c0ffee | m?myrole name jeff",
+		Triggers:         []string{"myrole"},
+		Usage:            "myrole\nmyrole 123123123123\nmyrole color c0ffee\nmyrole name jeff",
 		Cooldown:         time.Second * 3,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -354,8 +365,8 @@ func newListCustomRolesCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "listcustomroles",
 		Description:      "Returns a list of custom roles for the server. It also shows whether users with custom roles are in the server or not",
-		Triggers:         []string{"m?listuserroles", "m?listcustomroles"},
-		Usage:            "m?listcustomroles",
+		Triggers:         []string{"listuserroles", "listcustomroles"},
+		Usage:            "listcustomroles",
 		Cooldown:         time.Second * 30,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageRoles,
@@ -365,56 +376,86 @@ func newListCustomRolesCommand(m *module) *bot.ModuleCommand {
 		AllowDMs:         false,
 		Enabled:          true,
 		Execute: func(msg *discord.DiscordMessage) {
-			roles, err := m.db.GetCustomRolesByGuild(msg.GuildID())
+			pages, err := m.listCustomRolePages(msg.Discord, msg.GuildID())
 			if err != nil {
 				_, _ = msg.Reply("There was an issue, please try again!")
 				return
 			}
-
-			g, err := msg.Discord.Guild(msg.Message.GuildID)
-			if err != nil {
-				_, _ = msg.Reply("some error occurred, please try again")
+			if len(pages) == 0 {
+				_, _ = msg.Reply("There are no custom roles in this server")
 				return
 			}
+			if err := bot.NewPaginator(m, "rolepages", pages).Send(msg); err != nil {
+				_, _ = msg.Reply("There was an issue, please try again!")
+			}
+		},
+	}
+}
 
-			var builder strings.Builder
-			builder.WriteString(fmt.Sprintf("Custom roles in %v | Amount: %v\n\n", g.Name, len(roles)))
+func newListCustomRolesPages(m *module) *bot.ModulePages {
+	return &bot.ModulePages{
+		Mod:  m,
+		Name: "rolepages",
+		Pages: func(it *discord.DiscordInteraction, _ *bot.ComponentArgs) (bot.PageSource, error) {
+			return m.listCustomRolePages(it.Discord, it.GuildID())
+		},
+	}
+}
 
-			w := tabwriter.NewWriter(&builder, 0, 0, 1, ' ', tabwriter.Debug)
-			fmt.Fprintln(w, "Role Name\tRole ID\tUser\tUser ID\tStatus")
+// listCustomRolePages renders the custom roles of a guild, and the roles whose
+// users are no longer in it.
+func (m *module) listCustomRolePages(d *discord.Discord, guildID string) (bot.EmbedPages, error) {
+	roles, err := m.db.GetCustomRolesByGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
 
-			usedRoles := make(map[string]bool)   // tracks roles with at least one active user
-			orphanRoles := make(map[string]bool) // tracks roles with only users not in the guild
+	g, err := d.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
 
-			for _, ur := range roles {
-				role, err := msg.Discord.Role(g.ID, ur.RoleID)
-				if err != nil {
-					continue
-				}
+	var roleLines, orphanLines []string
+	usedRoles := make(map[string]bool)   // tracks roles with at least one active user
+	orphanRoles := make(map[string]bool) // tracks roles with only users not in the guild
 
-				mem, err := msg.Discord.Member(g.ID, ur.UserID)
-				if err != nil {
-					fmt.Fprintf(w, "%v\t%v\t%v\t%v\tUser no longer in guild\n", role.Name, role.ID, "N/A", ur.UserID)
-					orphanRoles[role.ID] = true
-				} else {
-					fmt.Fprintf(w, "%v\t%v\t%v\t%v\tIn guild\n", role.Name, role.ID, mem.User.String(), mem.User.ID)
-					usedRoles[role.ID] = true
-					orphanRoles[role.ID] = false
-				}
-			}
-			w.Flush()
+	for _, ur := range roles {
+		role, err := d.Role(g.ID, ur.RoleID)
+		if err != nil {
+			continue
+		}
 
-			builder.WriteString("\nRoles without users:\n")
-			for _, role := range g.Roles {
-				if !usedRoles[role.ID] && orphanRoles[role.ID] {
-					builder.WriteString(fmt.Sprintf("%v (%v)\n", role.Name, role.ID))
-				}
-			}
+		mem, err := d.Member(g.ID, ur.UserID)
+		if err != nil {
+			roleLines = append(roleLines, fmt.Sprintf("%v (`%v`)\nUser no longer in server: `%v`", role.Mention(), role.ID, ur.UserID))
+			orphanRoles[role.ID] = true
+		} else {
+			roleLines = append(roleLines, fmt.Sprintf("%v (`%v`)\nIn server: %v (`%v`)", role.Mention(), role.ID, mem.User.String(), mem.User.ID))
+			usedRoles[role.ID] = true
+			orphanRoles[role.ID] = false
+		}
+	}
 
-			reply := builders.NewMessageSendBuilder().
-				AddTextFile("roles.txt", builder.String()).
-				Build()
-			_, _ = msg.ReplyComplex(reply)
-		},
+	for _, role := range g.Roles {
+		if !usedRoles[role.ID] && orphanRoles[role.ID] {
+			orphanLines = append(orphanLines, fmt.Sprintf("%v (`%v`)", role.Name, role.ID))
+		}
+	}
+
+	title := fmt.Sprintf("Custom roles in %v | Amount: %v", g.Name, len(roles))
+	pages := customRolePages(title, roleLines, 10)
+	return append(pages, customRolePages("Roles without users", orphanLines, 20)...), nil
+}
+
+// customRolePages splits lines into embeds of at most perPage lines.
+func customRolePages(title string, lines []string, perPage int) bot.EmbedPages {
+	var pages bot.EmbedPages
+	for i := 0; i < len(lines); i += perPage {
+		pages = append(pages, builders.NewEmbedBuilder().
+			WithTitle(title).
+			WithOkColor().
+			WithDescription(strings.Join(lines[i:min(i+perPage, len(lines))], "\n\n")).
+			Build())
 	}
+	return pages
 }
@@ -46,8 +46,8 @@ func newFishCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "fish",
 		Description:      "Go fishin'",
-		Triggers:         []string{"m?fish"},
-		Usage:            "m?fish",
+		Triggers:         []string{"fish"},
+		Usage:            "fish",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeUser,
 		RequiredPerms:    0,
@@ -75,8 +75,8 @@ func newAquariumCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "aquarium",
 		Description:      "Displays your or someone else's aquarium",
-		Triggers:         []string{"m?aquarium", "m?aq"},
-		Usage:            "m?Aquarium <userID>",
+		Triggers:         []string{"aquarium", "aq"},
+		Usage:            "aquarium <userID>",
 		Cooldown:         time.Second * 3,
 		CooldownScope:    bot.CooldownScopeUser,
 		RequiredPerms:    0,
@@ -126,8 +126,8 @@ func newSetFishingSettingsCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "fishingsettings",
 		Description:      "Fishing settings:\n- Set fishing channel [channelID]",
-		Triggers:         []string{"m?settings fishing"},
-		Usage:            "m?settings fishing fishingchannel [channelID]",
+		Triggers:         []string{"settings fishing"},
+		Usage:            "settings fishing fishingchannel [channelID]",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionAdministrator,
@@ -2,8 +2,13 @@ package fun
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
 	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"math/rand"
 	"strings"
 	"time"
 
@@ -40,8 +45,8 @@ func newLifeCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "life",
 		Description:      "Shows a gif of Conway's Game of Life. If no seed is provided, it uses your user ID",
-		Triggers:         []string{"m?life"},
-		Usage:            "m?life | m?life <seed | user>",
+		Triggers:         []string{"life"},
+		Usage:            "life\nlife <seed | user>",
 		Cooldown:         time.Second * 5,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -57,7 +62,7 @@ func newLifeCommand(m *module) *bot.ModuleCommand {
 				seedStr = strings.Join(msg.Args()[1:], " ")
 			}
 
-			buf, err := generateGif(seedStr)
+			buf, err := generateGif(msg.Context(), seedStr)
 			if err != nil {
 				_, _ = msg.Reply("There was an issue, please try again!")
 				return
@@ -73,6 +78,7 @@ func newLifeSlash(m *module) *bot.ModuleApplicationCommand {
 		Type(discordgo.ChatApplicationCommand).
 		Description("Show Conway's Game of Life").
 		Cooldown(time.Second*5, bot.CooldownScopeChannel).
+		AutoDefer(bot.DefaultAutoDefer, false).
 		AddOption(&discordgo.ApplicationCommandOption{
 			Type:        discordgo.ApplicationCommandOptionString,
 			Name:        "seed",
@@ -85,7 +91,7 @@ func newLifeSlash(m *module) *bot.ModuleApplicationCommand {
 			seed = seedOpt.StringValue()
 		}
 
-		buf, err := generateGif(seed)
+		buf, err := generateGif(dac.Context(), seed)
 		if err != nil {
 			_ = dac.Respond("Generation failed")
 			return
@@ -96,16 +102,62 @@ func newLifeSlash(m *module) *bot.ModuleApplicationCommand {
 	return cmd.Execute(exec).Build()
 }
 
-func generateGif(seedStr string) (*bytes.Buffer, error) {
+const (
+	gameSize   = 100
+	gameFrames = 100
+	gameScale  = 2
+)
+
+// generateGif runs the game and renders it as a GIF. It stops between frames
+// once ctx is done, so a timed out command does not keep rendering.
+func generateGif(ctx context.Context, seedStr string) (*bytes.Buffer, error) {
 	ye := sha1.New()
 	_, err := ye.Write([]byte(seedStr))
 	if err != nil {
 		return nil, err
 	}
 	seed := int64(binary.BigEndian.Uint64(ye.Sum(nil)[:8]))
-	game, _ := gol.NewGame(seed, 100, 100, true)
-	game.Run(100, 50, false, true, "game.gif", 2)
+
+	// this is what gol.Game.Run does, but with a way to stop it
+	grid, err := gol.NewGrid(gameSize, gameSize, seed, true)
+	if err != nil {
+		return nil, err
+	}
+	prev, _ := gol.NewGrid(gameSize, gameSize, seed, true)
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < gameSize*gameSize/6; i++ {
+		grid.Set(rng.Intn(gameSize), rng.Intn(gameSize), 1)
+	}
+	grid.DeepCopy(prev)
+
+	render := &gif.GIF{}
+	for i := 0; i < gameFrames; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		grid.Step(prev)
+		grid.DeepCopy(prev)
+		render.Image = append(render.Image, gameFrame(grid))
+		render.Delay = append(render.Delay, 10)
+	}
+
 	buf := bytes.Buffer{}
-	_ = game.Export(&buf) // no need to check error, because export will always be populated
+	if err := gif.EncodeAll(&buf, render); err != nil {
+		return nil, err
+	}
 	return &buf, nil
 }
+
+var gamePalette = []color.Color{color.Black, color.White}
+
+func gameFrame(grid *gol.Grid) *image.Paletted {
+	frame := image.NewPaletted(image.Rect(0, 0, gameSize*gameScale, gameSize*gameScale), gamePalette)
+	for y := 0; y < gameSize*gameScale; y++ {
+		for x := 0; x < gameSize*gameScale; x++ {
+			if grid.At(x/gameScale, y/gameScale) != 0 {
+				frame.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return frame
+}
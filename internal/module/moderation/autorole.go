@@ -1,6 +1,7 @@
 package moderation
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -28,8 +29,8 @@ func newSetAutoRoleCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "setautorole",
 		Description:      "Sets an autorole for the server to a provided role",
-		Triggers:         []string{"m?setautorole"},
-		Usage:            "m?setautorole [role name / role ID]",
+		Triggers:         []string{"setautorole"},
+		Usage:            "setautorole [role name / role ID]",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionAdministrator,
@@ -69,8 +70,8 @@ func newRemoveAutoRoleCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "removeautorole",
 		Description:      "Removes the autorole for the server",
-		Triggers:         []string{"m?removeautorole"},
-		Usage:            "m?removeautorole",
+		Triggers:         []string{"removeautorole"},
+		Usage:            "removeautorole",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionAdministrator,
@@ -80,36 +81,17 @@ func newRemoveAutoRoleCommand(m *module) *bot.ModuleCommand {
 		AllowDMs:         false,
 		Enabled:          true,
 		Execute: func(msg *discord.DiscordMessage) {
-			rpl, err := msg.Reply("Are you sure you want to REMOVE the autorole? Please answer `yes` if you are.")
-			if err != nil {
-				_, _ = msg.Reply("There was an issue, please try again!")
+			reply, err := m.Bot.Prompts.Prompt(msg, "Are you sure you want to REMOVE the autorole? Please answer `yes` if you are.",
+				bot.PromptOptions{Timeout: time.Second * 15},
+				func(reply *discord.DiscordMessage) bool { return strings.ToLower(reply.RawContent()) == "yes" })
+			if errors.Is(err, bot.ErrPromptTimedOut) {
+				_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
 				return
-			}
-
-			ch, err := m.Bot.Callbacks.Make(fmt.Sprintf("%v:%v", msg.ChannelID(), msg.AuthorID()))
-			if err != nil {
-				_, _ = msg.Reply("There was an issue, please try again!")
+			} else if err != nil {
 				return
 			}
-			defer m.Bot.Callbacks.Delete(fmt.Sprintf("%v:%v", msg.ChannelID(), msg.AuthorID()))
-
-			var reply *discord.DiscordMessage
-			t := time.NewTimer(time.Second * 15)
-			for {
-				select {
-				case reply = <-ch:
-				case <-t.C:
-					_ = msg.Sess.ChannelMessageDelete(rpl.ChannelID, rpl.ID)
-					_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
-					return
-				}
-
-				if strings.ToLower(reply.RawContent()) == "yes" {
-					_ = msg.Sess.ChannelMessageDelete(reply.ChannelID(), reply.Message.ID)
-					_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
-					break
-				}
-			}
+			_ = msg.Sess.ChannelMessageDelete(reply.ChannelID(), reply.Message.ID)
+			_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
 
 			// the autorole exists, remove it
 			if g, err := m.db.GetGuild(msg.GuildID()); err == nil {
@@ -2,15 +2,15 @@ package moderation
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/internal/structs"
 	"github.com/intrntsrfr/meido/pkg/mio/bot"
 	"github.com/intrntsrfr/meido/pkg/mio/discord"
-	"github.com/intrntsrfr/meido/pkg/utils"
 	"github.com/intrntsrfr/meido/pkg/utils/builders"
 	"go.uber.org/zap"
 )
@@ -20,8 +20,8 @@ func newFilterWordCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "filterword",
 		Description:      "Adds or removes a word or phrase to the server filter.",
-		Triggers:         []string{"m?fw", "m?filterword"},
-		Usage:            "m?fw jeff",
+		Triggers:         []string{"fw", "filterword"},
+		Usage:            "fw jeff",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageMessages,
@@ -65,8 +65,8 @@ func newFilterWordListCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "filterwordlist",
 		Description:      "Lists of all filtered phrases for this server",
-		Triggers:         []string{"m?fwl", "m?filterwordlist"},
-		Usage:            "m?fwl",
+		Triggers:         []string{"fwl", "filterwordlist"},
+		Usage:            "fwl",
 		Cooldown:         time.Second * 10,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageMessages,
@@ -109,8 +109,8 @@ func newClearFilterCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "clearfilter",
 		Description:      "Removes all phrases from the server filter",
-		Triggers:         []string{"m?clearfilter"},
-		Usage:            "m?clearfilter",
+		Triggers:         []string{"clearfilter"},
+		Usage:            "clearfilter",
 		Cooldown:         time.Second * 10,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionAdministrator,
@@ -127,34 +127,18 @@ func (m *module) clearfilterCommand(msg *discord.DiscordMessage) {
 	if len(msg.Args()) < 1 {
 		return
 	}
-	rpl, err := msg.Reply("Are you sure you want to REMOVE ALL FILTERS? Please reply `YES`, in all caps, if you are")
-	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
+	reply, err := m.Bot.Prompts.Prompt(msg, "Are you sure you want to REMOVE ALL FILTERS? Please reply `YES`, in all caps, if you are",
+		bot.PromptOptions{Timeout: time.Second * 15},
+		func(reply *discord.DiscordMessage) bool { return reply.RawContent() == "YES" })
+	if errors.Is(err, bot.ErrPromptTimedOut) {
+		_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
 		return
-	}
-	cb, err := m.Bot.Callbacks.Make(fmt.Sprintf("%v:%v", msg.ChannelID(), msg.AuthorID()))
-	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
+	} else if err != nil {
 		return
 	}
-	defer m.Bot.Callbacks.Delete(fmt.Sprintf("%v:%v", msg.ChannelID(), msg.AuthorID()))
+	_ = msg.Sess.ChannelMessageDelete(reply.ChannelID(), reply.Message.ID)
+	_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
 
-	var reply *discord.DiscordMessage
-	t := time.NewTimer(time.Second * 15)
-	for {
-		select {
-		case reply = <-cb:
-		case <-t.C:
-			_ = msg.Sess.ChannelMessageDelete(rpl.ChannelID, rpl.ID)
-			_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
-			return
-		}
-		if strings.ToLower(reply.RawContent()) == "YES" {
-			_ = msg.Sess.ChannelMessageDelete(reply.ChannelID(), reply.Message.ID)
-			_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
-			break
-		}
-	}
 	if err = m.db.DeleteGuildFilters(msg.GuildID()); err != nil {
 		_, _ = msg.Reply("There was an issue, please try again!")
 		m.Logger.Error("Deleting guild filters failed", zap.Any("message", msg))
@@ -168,8 +152,8 @@ func newModerationSettingsCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "moderationsettings",
 		Description:      "Moderation settings:\n- Toggle warn system [enable / disable]\n- Set max warns [0 - 10]\n- Set warn duration [0 (forever) - 365]",
-		Triggers:         []string{"m?settings moderation"},
-		Usage:            "m?settings moderation warns [enable / disable]\nm?settings moderation maxwarns [0 - 10]\nm?settings moderation warnduration [0 - 365]",
+		Triggers:         []string{"settings moderation"},
+		Usage:            "settings moderation warns [enable / disable]\nsettings moderation maxwarns [0 - 10]\nsettings moderation warnduration [0 - 365]",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionAdministrator,
@@ -178,79 +162,89 @@ func newModerationSettingsCommand(m *module) *bot.ModuleCommand {
 		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         false,
 		Enabled:          true,
-		Execute:          m.moderationsettingsCommand,
-	}
-}
-
-var (
-	warnsEnabledText     = map[bool]string{true: "Enabled", false: "Disabled"}
-	warnsEnabledSettings = map[string]bool{"enable": true, "disable": false}
-)
-
-func (m *module) moderationsettingsCommand(msg *discord.DiscordMessage) {
-	if len(msg.Args()) < 2 {
-		return
-	}
-	gc, err := m.db.GetGuild(msg.GuildID())
-	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
-		return
-	}
-
-	switch len(msg.Args()) {
-	case 2:
-		embed := builders.NewEmbedBuilder().
-			WithTitle("Moderation settings").
-			WithOkColor().
-			AddField("Warnings", warnsEnabledText[gc.UseWarns], true).
-			AddField("Max warnings", fmt.Sprint(gc.MaxWarns), true).
-			AddField("Warning duration", fmt.Sprintf("%v days", gc.WarnDuration), true)
-		_, _ = msg.ReplyEmbed(embed.Build())
-	case 4:
-		switch msg.Args()[2] {
-		case "warns":
-			if v, ok := warnsEnabledSettings[msg.Args()[3]]; ok && gc.UseWarns != v {
-				gc.UseWarns = v
-				if err := m.db.UpdateGuild(gc); err != nil {
-					_, _ = msg.Reply("There was an issue, please try again!")
-					return
-				}
-				_, _ = msg.Reply(fmt.Sprintf("Warnings: %v -> %v", !gc.UseWarns, gc.UseWarns))
-			}
-		case "maxwarns":
-			n, err := strconv.Atoi(msg.Args()[3])
+		Execute: func(msg *discord.DiscordMessage) {
+			gc, err := m.db.GetGuild(msg.GuildID())
 			if err != nil {
-				_, _ = msg.Reply("Please provide a number between 0 and 10")
-				return
-			}
-
-			before := gc.MaxWarns
-			n = utils.Clamp(0, 10, n)
-			gc.MaxWarns = n
-			if err := m.db.UpdateGuild(gc); err != nil {
 				_, _ = msg.Reply("There was an issue, please try again!")
 				return
 			}
-			_, _ = msg.Reply(fmt.Sprintf("Max warnings: %v -> %v", before, n))
-		case "warnduration":
-			n, err := strconv.Atoi(msg.Args()[3])
+			embed := builders.NewEmbedBuilder().
+				WithTitle("Moderation settings").
+				WithOkColor().
+				AddField("Warnings", warnsEnabledText[gc.UseWarns], true).
+				AddField("Max warnings", fmt.Sprint(gc.MaxWarns), true).
+				AddField("Warning duration", fmt.Sprintf("%v days", gc.WarnDuration), true)
+			_, _ = msg.ReplyEmbed(embed.Build())
+		},
+	}
+}
+
+// newModerationSettingCommand creates a command that changes a single moderation
+// setting, such as settings moderation maxwarns.
+func newModerationSettingCommand(m *module, setting, description string, arg *bot.CommandArgument, update func(*structs.Guild, *bot.CommandArgs) string) *bot.ModuleCommand {
+	arg.Required = true
+	return &bot.ModuleCommand{
+		Mod:              m,
+		Name:             "moderationsettings" + setting,
+		Description:      description,
+		Triggers:         []string{"settings moderation " + setting},
+		Cooldown:         time.Second * 2,
+		CooldownScope:    bot.CooldownScopeChannel,
+		RequiredPerms:    discordgo.PermissionAdministrator,
+		CheckBotPerms:    false,
+		RequiresUserType: bot.UserTypeAny,
+		AllowedTypes:     discord.MessageTypeCreate,
+		AllowDMs:         false,
+		Enabled:          true,
+		Arguments:        []*bot.CommandArgument{arg},
+		ExecuteArgs: func(msg *discord.DiscordMessage, args *bot.CommandArgs) {
+			gc, err := m.db.GetGuild(msg.GuildID())
 			if err != nil {
-				_, _ = msg.Reply("Please provide a number between 0 and 365")
+				_, _ = msg.Reply("There was an issue, please try again!")
 				return
 			}
-
-			before := gc.MaxWarns
-			n = utils.Clamp(0, 365, n)
-			gc.WarnDuration = n
+			reply := update(gc, args)
 			if err := m.db.UpdateGuild(gc); err != nil {
 				_, _ = msg.Reply("There was an issue, please try again!")
 				return
 			}
-			_, _ = msg.Reply(fmt.Sprintf("Warn duration: %v days -> %v days", before, n))
-		}
+			_, _ = msg.Reply(reply)
+		},
 	}
 }
 
+var warnsEnabledText = map[bool]string{true: "Enabled", false: "Disabled"}
+
+func newWarnsSettingCommand(m *module) *bot.ModuleCommand {
+	return newModerationSettingCommand(m, "warns", "Enables or disables the warn system",
+		&bot.CommandArgument{Name: "mode", Type: bot.ArgumentEnum, Choices: []string{"enable", "disable"}},
+		func(gc *structs.Guild, args *bot.CommandArgs) string {
+			before := gc.UseWarns
+			gc.UseWarns = args.String("mode") == "enable"
+			return fmt.Sprintf("Warnings: %v -> %v", warnsEnabledText[before], warnsEnabledText[gc.UseWarns])
+		})
+}
+
+func newMaxWarnsSettingCommand(m *module) *bot.ModuleCommand {
+	return newModerationSettingCommand(m, "maxwarns", "Sets how many warns a member can get before they are banned",
+		&bot.CommandArgument{Name: "amount", Type: bot.ArgumentInt, Min: 0, Max: 10, HasMin: true, HasMax: true},
+		func(gc *structs.Guild, args *bot.CommandArgs) string {
+			before := gc.MaxWarns
+			gc.MaxWarns = args.Int("amount")
+			return fmt.Sprintf("Max warnings: %v -> %v", before, gc.MaxWarns)
+		})
+}
+
+func newWarnDurationSettingCommand(m *module) *bot.ModuleCommand {
+	return newModerationSettingCommand(m, "warnduration", "Sets how many days warns last, or 0 for forever",
+		&bot.CommandArgument{Name: "days", Type: bot.ArgumentInt, Min: 0, Max: 365, HasMin: true, HasMax: true},
+		func(gc *structs.Guild, args *bot.CommandArgs) string {
+			before := gc.WarnDuration
+			gc.WarnDuration = args.Int("days")
+			return fmt.Sprintf("Warn duration: %v days -> %v days", before, gc.WarnDuration)
+		})
+}
+
 func newCheckFilterPassive(m *module) *bot.ModulePassive {
 	return &bot.ModulePassive{
 		Mod:          m,
@@ -13,8 +13,8 @@ func newLockdownChannelCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "lockdown",
 		Description:      "Locks the current channel.",
-		Triggers:         []string{"m?lockdown"},
-		Usage:            "m?lockdown",
+		Triggers:         []string{"lockdown"},
+		Usage:            "lockdown",
 		Cooldown:         time.Second * 10,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageRoles,
@@ -103,8 +103,8 @@ func newUnlockChannelCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "unlock",
 		Description:      "Unlocks a previously locked channel.",
-		Triggers:         []string{"m?unlock"},
-		Usage:            "m?unlock",
+		Triggers:         []string{"unlock"},
+		Usage:            "unlock",
 		Cooldown:         time.Second * 10,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageRoles,
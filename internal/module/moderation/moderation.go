@@ -1,6 +1,7 @@
 package moderation
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -22,15 +23,27 @@ type module struct {
 }
 
 func New(b *bot.Bot, db database.DB, logger mio.Logger) bot.Module {
+	return newModule(b, &ModerationDB{DB: db, IFilterDB: &FilterDB{db}, IWarnDB: &WarnDB{db}}, logger)
+}
+
+func newModule(b *bot.Bot, db IModerationDB, logger mio.Logger) *module {
 	logger = logger.Named("Moderation")
 	return &module{
 		ModuleBase: bot.NewModule(b, "Moderation", logger),
-		db:         &ModerationDB{DB: db, IFilterDB: &FilterDB{db}, IWarnDB: &WarnDB{db}},
+		db:         db,
 	}
 }
 
 func (m *module) Hook() error {
-	m.Bot.Discord.AddEventHandlerOnce(checkWarnInterval(m))
+	if err := m.Bot.Scheduler.Add(&bot.Job{
+		Name:     "moderation.expire_warns",
+		Schedule: bot.Every(time.Hour),
+		Jitter:   time.Minute * 5,
+		Run:      m.expireWarns,
+	}); err != nil {
+		return err
+	}
+	m.Bot.Scheduler.Handle(unbanJobKind, m.unban)
 	m.Bot.Discord.AddEventHandler(addAutoRoleOnJoin(m))
 
 	err := m.RegisterPassives(newCheckFilterPassive(m))
@@ -38,9 +51,20 @@ func (m *module) Hook() error {
 		return err
 	}
 
+	if err := m.RegisterApplicationCommands(newWarnUserCommand(m)); err != nil {
+		return err
+	}
+	if err := m.RegisterModalSubmits(newWarnReasonModalSubmit(m)); err != nil {
+		return err
+	}
+	if err := m.RegisterPages(newWarnLogPages(m)); err != nil {
+		return err
+	}
+
 	return m.RegisterCommands(
 		newBanCommand(m),
 		newUnbanCommand(m),
+		newTempbanCommand(m),
 		newHackbanCommand(m),
 		newKickCommand(m),
 		newWarnCommand(m),
@@ -52,6 +76,9 @@ func (m *module) Hook() error {
 		newClearFilterCommand(m),
 		newFilterWordListCommand(m),
 		newModerationSettingsCommand(m),
+		newWarnsSettingCommand(m),
+		newMaxWarnsSettingCommand(m),
+		newWarnDurationSettingCommand(m),
 		newLockdownChannelCommand(m),
 		newUnlockChannelCommand(m),
 		newMuteCommand(m),
@@ -62,42 +89,40 @@ func (m *module) Hook() error {
 	)
 }
 
-func checkWarnInterval(m *module) func(s *discordgo.Session, r *discordgo.Ready) {
-	return func(s *discordgo.Session, r *discordgo.Ready) {
-		refreshTicker := time.NewTicker(time.Hour)
-		go func() {
-			for range refreshTicker.C {
-				m.Logger.Info("Checking for expired warns")
-				for _, g := range m.Bot.Discord.Guilds() {
-					if g.Unavailable {
-						continue
-					}
-					gc, err := m.db.GetGuild(g.ID)
-					if err != nil || gc.WarnDuration <= 0 {
-						continue
-					}
-
-					warns, err := m.db.GetGuildWarnsIfActive(g.ID)
-					if err != nil {
-						continue
-					}
-
-					dur := time.Duration(gc.WarnDuration) * 24 * time.Hour
-					for _, warn := range warns {
-						if time.Since(warn.GivenAt) > dur {
-							t := time.Now()
-							warn.IsValid = false
-							warn.ClearedByID = &m.Bot.Discord.Sess.State().User.ID
-							warn.ClearedAt = &t
-							if err := m.db.UpdateMemberWarn(warn); err != nil {
-								m.Logger.Error("Updating warn failed", zap.Error(err), zap.Int("warn UID", warn.UID))
-							}
-						}
-					}
+// expireWarns clears the warns that are older than the warn duration of their guild.
+func (m *module) expireWarns(ctx context.Context) error {
+	m.Logger.Info("Checking for expired warns")
+	for _, g := range m.Bot.Discord.Guilds() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if g.Unavailable {
+			continue
+		}
+		gc, err := m.db.GetGuild(g.ID)
+		if err != nil || gc.WarnDuration <= 0 {
+			continue
+		}
+
+		warns, err := m.db.GetGuildWarnsIfActive(g.ID)
+		if err != nil {
+			continue
+		}
+
+		dur := time.Duration(gc.WarnDuration) * 24 * time.Hour
+		for _, warn := range warns {
+			if time.Since(warn.GivenAt) > dur {
+				t := time.Now()
+				warn.IsValid = false
+				warn.ClearedByID = &m.Bot.Discord.Sess.State().User.ID
+				warn.ClearedAt = &t
+				if err := m.db.UpdateMemberWarn(warn); err != nil {
+					m.Logger.Error("Updating warn failed", zap.Error(err), zap.Int("warn UID", warn.UID))
 				}
 			}
-		}()
+		}
 	}
+	return nil
 }
 
 func newBanCommand(m *module) *bot.ModuleCommand {
@@ -105,8 +130,8 @@ func newBanCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "ban",
 		Description:      "Bans a user. Days of messages to be deleted and reason is optional",
-		Triggers:         []string{"m?ban", "m?b", ".b", ".ban"},
-		Usage:            ".b [user] <days> <reason>",
+		Triggers:         []string{"ban", "b"},
+		Usage:            "b [user] <days> <reason>",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionBanMembers,
@@ -216,8 +241,8 @@ func newUnbanCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "unban",
 		Description:      "Unbans a user",
-		Triggers:         []string{"m?unban", "m?ub", ".ub", ".unban"},
-		Usage:            ".unban [userID]",
+		Triggers:         []string{"unban", "ub"},
+		Usage:            "unban [userID]",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionBanMembers,
@@ -261,8 +286,8 @@ func newHackbanCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "hackban",
 		Description:      "Hackbans one or several users. Prunes 7 days. Only accepts user IDs.",
-		Triggers:         []string{"m?hackban", "m?hb"},
-		Usage:            "m?hb [userID] <additional userIDs...>",
+		Triggers:         []string{"hackban", "hb"},
+		Usage:            "hb [userID] <additional userIDs...>",
 		Cooldown:         time.Second * 3,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionBanMembers,
@@ -298,8 +323,8 @@ func newKickCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "kick",
 		Description:      "Kicks a user. Reason is optional",
-		Triggers:         []string{"m?kick", "m?k", ".kick", ".k"},
-		Usage:            "m?k [user] <reason>",
+		Triggers:         []string{"kick", "k"},
+		Usage:            "k [user] <reason>",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionKickMembers,
@@ -380,8 +405,8 @@ func newPruneCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "prune",
 		Description:      "Prunes all of Meido's messages in the last 100 messages. Amount of messages can be specified, but max 100. If a user is specified, it removes all messages from that user in the last 100 messages.",
-		Triggers:         []string{"m?prune"},
-		Usage:            "m?prune <user> <amount>",
+		Triggers:         []string{"prune"},
+		Usage:            "prune <user> <amount>",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageMessages,
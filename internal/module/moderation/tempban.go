@@ -0,0 +1,105 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/intrntsrfr/meido/pkg/utils/builders"
+)
+
+// unbanJobKind is the kind of the scheduled jobs that lift a temporary ban.
+const unbanJobKind = "moderation.unban"
+
+type unbanPayload struct {
+	GuildID string `json:"guild_id"`
+	UserID  string `json:"user_id"`
+}
+
+func newTempbanCommand(m *module) *bot.ModuleCommand {
+	return &bot.ModuleCommand{
+		Mod:              m,
+		Name:             "tempban",
+		Description:      "Bans a user, and unbans them again once the duration has passed.",
+		Triggers:         []string{"tempban", "tb"},
+		Usage:            "tempban [user] [duration] <reason>\ntempban 163454407999094786 48h spam",
+		Cooldown:         time.Second * 2,
+		CooldownScope:    bot.CooldownScopeChannel,
+		RequiredPerms:    discordgo.PermissionBanMembers,
+		CheckBotPerms:    true,
+		RequiresUserType: bot.UserTypeAny,
+		AllowedTypes:     discord.MessageTypeCreate,
+		AllowDMs:         false,
+		Enabled:          true,
+		Arguments: []*bot.CommandArgument{
+			{Name: "user", Type: bot.ArgumentUser, Required: true},
+			{Name: "duration", Type: bot.ArgumentDuration, Required: true, MinDuration: time.Minute, MaxDuration: time.Hour * 24 * 365},
+			{Name: "reason", Type: bot.ArgumentRest},
+		},
+		Run: m.tempbanCommand,
+	}
+}
+
+func (m *module) tempbanCommand(msg *discord.DiscordMessage, args *bot.CommandArgs) error {
+	target := args.User("user")
+	duration := args.Duration("duration")
+	reason := strings.TrimSpace(args.String("reason"))
+	if reason == "" {
+		reason = "No reason"
+	}
+
+	if target.ID == msg.Discord.BotUser().ID {
+		return bot.PermissionError("no (i can not ban myself)")
+	}
+	if target.ID == msg.AuthorID() {
+		return bot.PermissionError("no (you can not ban yourself)")
+	}
+	topUserRole := msg.Discord.HighestRolePosition(msg.GuildID(), msg.AuthorID())
+	topTargetRole := msg.Discord.HighestRolePosition(msg.GuildID(), target.ID)
+	topBotRole := msg.Discord.HighestRolePosition(msg.GuildID(), msg.Discord.BotUser().ID)
+	if topUserRole <= topTargetRole || topBotRole <= topTargetRole {
+		return bot.PermissionError("no (you can only ban users who are below you and me in the role hierarchy)")
+	}
+
+	err := msg.Sess.GuildBanCreateWithReason(msg.GuildID(), target.ID, fmt.Sprintf("%v - %v (for %v)", msg.Author().String(), reason, duration), 0)
+	if err != nil {
+		return &bot.CommandError{Kind: bot.ErrorInternal, Message: "I could not ban that user!", Err: err}
+	}
+	until := time.Now().Add(duration)
+	if _, err := m.Bot.Scheduler.ScheduleAt(unbanJobKind, until, unbanPayload{GuildID: msg.GuildID(), UserID: target.ID}); err != nil {
+		return &bot.CommandError{Kind: bot.ErrorInternal, Message: "I banned that user, but could not schedule the unban!", Err: err}
+	}
+
+	embed := builders.NewEmbedBuilder().
+		WithTitle("User banned").
+		WithOkColor().
+		AddField("Username", target.Mention(), true).
+		AddField("ID", target.ID, true).
+		AddField("Unbanned", fmt.Sprintf("<t:%v:R>", until.Unix()), true)
+	_, _ = msg.ReplyEmbed(embed.Build())
+	return nil
+}
+
+// unban lifts a temporary ban once it has run out.
+func (m *module) unban(ctx context.Context, job *bot.ScheduledJob) error {
+	var p unbanPayload
+	if err := job.Decode(&p); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		// the lease ran out, so the job may be claimed again
+		return err
+	}
+	err := m.Bot.Discord.Sess.GuildBanDelete(p.GuildID, p.UserID)
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeUnknownBan {
+		// they were unbanned by hand already
+		return nil
+	}
+	return err
+}
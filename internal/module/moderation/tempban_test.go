@@ -0,0 +1,55 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio/bottest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTempbanCommand(t *testing.T) {
+	h := newTestHarness(t, 3)
+	h.Member("20", "spammer")
+
+	h.Dispatch(bottest.ChannelID, h.mod, "m?tempban <@20> 2h spam")
+	h.Wait()
+	bans := h.Session.Bans(bottest.GuildID)
+	require.Len(t, bans, 1)
+	assert.Equal(t, "20", bans[0].User.ID)
+	assert.Contains(t, bans[0].Reason, "spam (for 2h0m0s)")
+	require.NotEmpty(t, h.LastReply().Embeds)
+	assert.Equal(t, "User banned", h.LastReply().Embeds[0].Title)
+
+	jobs, err := h.Bot.Scheduler.Pending()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, unbanJobKind, jobs[0].Kind)
+	assert.WithinDuration(t, time.Now().Add(time.Hour*2), jobs[0].RunAt, time.Minute)
+	var p unbanPayload
+	require.NoError(t, jobs[0].Decode(&p))
+	assert.Equal(t, unbanPayload{GuildID: bottest.GuildID, UserID: "20"}, p)
+
+	// a run whose lease ran out leaves the ban for the next claimer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, h.m.unban(ctx, jobs[0]), context.Canceled)
+	assert.Len(t, h.Session.Bans(bottest.GuildID), 1)
+
+	require.NoError(t, h.m.unban(context.Background(), jobs[0]))
+	assert.Empty(t, h.Session.Bans(bottest.GuildID))
+}
+
+func TestTempbanCommand_Duration(t *testing.T) {
+	h := newTestHarness(t, 3)
+	h.Member("20", "spammer")
+
+	h.Dispatch(bottest.ChannelID, h.mod, "m?tempban <@20> soon")
+	h.Wait()
+	assert.Empty(t, h.Session.Bans(bottest.GuildID))
+	jobs, err := h.Bot.Scheduler.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+}
@@ -14,8 +14,8 @@ func newMuteCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "mute",
 		Description:      "Mutes a member, making them unable to chat or speak. Duration will be 1 day unless something else is specified.",
-		Triggers:         []string{"m?mute"},
-		Usage:            "m?mute <user> [duration] | m?mute 163454407999094786 1h30m",
+		Triggers:         []string{"mute"},
+		Usage:            "mute [user] <duration>\nmute 163454407999094786 1h30m",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionModerateMembers,
@@ -24,34 +24,21 @@ func newMuteCommand(m *module) *bot.ModuleCommand {
 		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         false,
 		Enabled:          true,
-		Execute:          m.muteCommand,
+		Arguments: []*bot.CommandArgument{
+			{Name: "user", Type: bot.ArgumentMember, Required: true},
+			{Name: "duration", Type: bot.ArgumentDuration, MinDuration: time.Minute, MaxDuration: time.Hour * 24 * 28},
+		},
+		ExecuteArgs: m.muteCommand,
 	}
 }
 
-func (m *module) muteCommand(msg *discord.DiscordMessage) {
-	if len(msg.Args()) < 2 {
-		return
-	}
+func (m *module) muteCommand(msg *discord.DiscordMessage, args *bot.CommandArgs) {
 	duration := time.Hour * 24
-	if len(msg.Args()) > 2 {
-		pDur, err := time.ParseDuration(msg.Args()[2])
-		if err != nil {
-			_, _ = msg.Reply("invalid time format - I allow hours and minutes! Example: 1h30m")
-			return
-		}
-		if pDur < time.Minute || pDur > time.Hour*24*28 {
-			_, _ = msg.Reply("duration is either too short or too long - Minimum 1 minute, max 28 days")
-			return
-		}
-		duration = pDur
+	if args.Has("duration") {
+		duration = args.Duration("duration")
 	}
 	until := time.Now().Add(duration)
-
-	// get the target member
-	targetMember, err := msg.GetMemberAtArg(1)
-	if err != nil {
-		return
-	}
+	targetMember := args.Member("user")
 
 	if msg.AuthorID() == targetMember.User.ID {
 		_, _ = msg.Reply("you cannot mute yourself")
@@ -68,8 +55,7 @@ func (m *module) muteCommand(msg *discord.DiscordMessage) {
 		return
 	}
 
-	// just unmute 4head
-	err = msg.Discord.Sess.GuildMemberTimeout(msg.GuildID(), targetMember.User.ID, &until)
+	err := msg.Discord.Sess.GuildMemberTimeout(msg.GuildID(), targetMember.User.ID, &until)
 	if err != nil {
 		_, _ = msg.Reply("I was unable to mute that member")
 		return
@@ -82,8 +68,8 @@ func newUnmuteCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "unmute",
 		Description:      "Unmutes a member",
-		Triggers:         []string{"m?unmute"},
-		Usage:            "m?unmute <user>",
+		Triggers:         []string{"unmute"},
+		Usage:            "unmute <user>",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionModerateMembers,
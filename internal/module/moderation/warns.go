@@ -20,8 +20,8 @@ func newWarnCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "warn",
 		Description:      "Warns a user. Requires warnings enabled.",
-		Triggers:         []string{"m?warn", ".warn"},
-		Usage:            "m?warn [user] <reason>",
+		Triggers:         []string{"warn"},
+		Usage:            "warn [user] <reason>",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionBanMembers,
@@ -30,99 +30,195 @@ func newWarnCommand(m *module) *bot.ModuleCommand {
 		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         false,
 		Enabled:          true,
-		Execute:          m.warnCommand,
+		Run:              m.warnCommand,
 	}
 }
 
-func (m *module) warnCommand(msg *discord.DiscordMessage) {
+func (m *module) warnCommand(msg *discord.DiscordMessage, _ *bot.CommandArgs) error {
 	if len(msg.Args()) < 2 {
-		return
+		return bot.UsageError("Who should be warned?")
 	}
 
-	gc, err := m.db.GetGuild(msg.GuildID())
+	targetMember, err := msg.GetMemberAtArg(1)
 	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
-		return
+		return bot.NotFoundError("Could not find that user!")
+	}
+	if err := checkWarnTarget(msg.Discord, msg.GuildID(), msg.AuthorID(), targetMember.User.ID); err != nil {
+		return err
 	}
 
+	reason := "No reason"
+	if len(msg.Args()) > 2 {
+		reason = strings.Join(msg.RawArgs()[2:], " ")
+	}
+	reply, err := m.warnMember(msg.Discord, msg.GuildID(), msg.AuthorID(), targetMember.User, reason)
+	if err != nil {
+		return err
+	}
+	_, _ = msg.Reply(reply)
+	return nil
+}
+
+// warnReasonArgs is carried by the custom ID of the warn reason modal.
+type warnReasonArgs struct {
+	UserID string
+}
+
+func newWarnUserCommand(m *module) *bot.ModuleApplicationCommand {
+	return bot.NewModuleApplicationCommandBuilder(m, "Warn").
+		Type(discordgo.UserApplicationCommand).
+		Permissions(discordgo.PermissionBanMembers).
+		CheckBotPerms().
+		NoDM().
+		Run(m.warnUserCommand).
+		Build()
+}
+
+func (m *module) warnUserCommand(cmd *discord.DiscordApplicationCommand) error {
+	gc, err := m.db.GetGuild(cmd.GuildID())
+	if err != nil {
+		return bot.InternalError(err)
+	}
 	if !gc.UseWarns {
-		_, _ = msg.Reply("Warnings are not enabled")
-		return
+		return errWarnsDisabled
 	}
 
-	reason := "No reason"
-	targetMember, err := msg.GetMemberAtArg(1)
+	targetUser := cmd.Data.Resolved.Users[cmd.Data.TargetID]
+	if targetUser == nil {
+		return bot.NotFoundError("Could not find that user!")
+	}
+	if err := checkWarnTarget(cmd.Discord, cmd.GuildID(), cmd.AuthorID(), targetUser.ID); err != nil {
+		return err
+	}
+
+	customID, err := m.ModalCustomID("warn-reason", warnReasonArgs{UserID: targetUser.ID})
 	if err != nil {
-		_, _ = msg.Reply("Could not find that user!")
-		return
+		return bot.InternalError(err)
+	}
+	modal := builders.NewModalBuilder(customID, "Warn "+targetUser.Username).
+		AddTextInput(&discordgo.TextInput{
+			CustomID:    "reason",
+			Label:       "Reason",
+			Style:       discordgo.TextInputParagraph,
+			Placeholder: "No reason",
+			MaxLength:   512,
+		}).
+		Build()
+	return cmd.RespondModal(modal)
+}
+
+func newWarnReasonModalSubmit(m *module) *bot.ModuleModalSubmit {
+	return &bot.ModuleModalSubmit{
+		Mod:           m,
+		Name:          "warn-reason",
+		Permissions:   discordgo.PermissionBanMembers,
+		CheckBotPerms: true,
+		Enabled:       true,
+		Command:       "Warn",
+		ExecuteArgs:   m.warnReasonModalSubmit,
 	}
+}
 
-	if targetMember.User.ID == msg.Discord.BotUser().ID {
-		_, _ = msg.Reply("no (I will not warn myself)")
+func (m *module) warnReasonModalSubmit(it *discord.DiscordModalSubmit, args *bot.ComponentArgs) {
+	var payload warnReasonArgs
+	if err := args.Decode(&payload); err != nil {
+		_ = it.RespondEphemeral("This has expired, please try again")
+		return
+	}
+	// the target may have changed roles or left while the modal was open
+	targetMember, err := it.Discord.Member(it.GuildID(), payload.UserID)
+	if err != nil {
+		_ = it.RespondEphemeral("Could not find that user!")
 		return
 	}
-	if targetMember.User.ID == msg.AuthorID() {
-		_, _ = msg.Reply("no (you can not warn yourself)")
+	if err := checkWarnTarget(it.Discord, it.GuildID(), it.AuthorID(), payload.UserID); err != nil {
+		_ = it.RespondEphemeral(bot.UserMessage(err))
+		return
+	}
+	reply, err := m.warnMember(it.Discord, it.GuildID(), it.AuthorID(), targetMember.User, it.FieldOr("reason", "No reason"))
+	if err != nil {
+		if bot.AsCommandError(err).Kind == bot.ErrorInternal {
+			m.Logger.Error("could not warn member", zap.Error(err), zap.String("userID", payload.UserID))
+		}
+		_ = it.RespondEphemeral(bot.UserMessage(err))
 		return
 	}
+	_ = it.Respond(reply)
+}
 
-	topUserRole := msg.Discord.HighestRolePosition(msg.Message.GuildID, msg.Message.Author.ID)
-	topTargetRole := msg.Discord.HighestRolePosition(msg.Message.GuildID, targetMember.User.ID)
-	topBotRole := msg.Discord.HighestRolePosition(msg.Message.GuildID, msg.Sess.State().User.ID)
+var errWarnsDisabled = bot.UsageError("Warnings are not enabled")
+
+// checkWarnTarget returns why authorID can not warn targetID, or nil if they can.
+func checkWarnTarget(d *discord.Discord, guildID, authorID, targetID string) error {
+	if targetID == d.BotUser().ID {
+		return bot.PermissionError("no (I will not warn myself)")
+	}
+	if targetID == authorID {
+		return bot.PermissionError("no (you can not warn yourself)")
+	}
+
+	topUserRole := d.HighestRolePosition(guildID, authorID)
+	topTargetRole := d.HighestRolePosition(guildID, targetID)
+	topBotRole := d.HighestRolePosition(guildID, d.BotUser().ID)
 	if topUserRole <= topTargetRole || topBotRole <= topTargetRole {
-		_, _ = msg.Reply("no (you can only kick users who are below you and me in the role hierarchy)")
-		return
+		return bot.PermissionError("no (you can only warn users who are below you and me in the role hierarchy)")
 	}
+	return nil
+}
 
-	if len(msg.Args()) > 2 {
-		reason = strings.Join(msg.RawArgs()[2:], " ")
+// warnMember warns target on behalf of authorID, and bans them once they reach
+// the warn limit of the guild. It returns the reply for the author.
+func (m *module) warnMember(d *discord.Discord, guildID, authorID string, target *discordgo.User, reason string) (string, error) {
+	gc, err := m.db.GetGuild(guildID)
+	if err != nil {
+		return "", bot.InternalError(err)
+	}
+	if !gc.UseWarns {
+		return "", errWarnsDisabled
 	}
 
-	warns, err := m.db.GetMemberWarnsIfActive(msg.GuildID(), targetMember.User.ID)
+	warns, err := m.db.GetMemberWarnsIfActive(guildID, target.ID)
 	if err != nil {
-		return
+		return "", bot.InternalError(err)
 	}
 	warnCount := len(warns)
 
-	g, err := msg.Discord.Guild(msg.GuildID())
+	g, err := d.Guild(guildID)
 	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
-		return
+		return "", bot.InternalError(err)
 	}
 
-	if err := m.db.CreateMemberWarn(msg.GuildID(), targetMember.User.ID, reason, msg.AuthorID()); err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
-		return
+	if err := m.db.CreateMemberWarn(guildID, target.ID, reason, authorID); err != nil {
+		return "", bot.InternalError(err)
 	}
 
-	userChannel, userChError := msg.Discord.Sess.UserChannelCreate(targetMember.User.ID)
+	userChannel, userChError := d.Sess.UserChannelCreate(target.ID)
 	if warnCount+1 < gc.MaxWarns {
 		if userChError == nil {
-			_, _ = msg.Discord.Sess.ChannelMessageSend(userChannel.ID, fmt.Sprintf("You have been warned in %v.\nYou were warned for: %v\nYou now have %v/%v warnings",
+			_, _ = d.Sess.ChannelMessageSend(userChannel.ID, fmt.Sprintf("You have been warned in %v.\nYou were warned for: %v\nYou now have %v/%v warnings",
 				g.Name, reason, warnCount+1, gc.MaxWarns))
 		}
-		_, _ = msg.Reply(fmt.Sprintf("%v has been warned\nThey now have %v/%v warnings", targetMember.Mention(), warnCount+1, gc.MaxWarns))
-		return
+		return fmt.Sprintf("%v has been warned\nThey now have %v/%v warnings", target.Mention(), warnCount+1, gc.MaxWarns), nil
 	}
 
 	if userChError == nil {
-		_, _ = msg.Discord.Sess.ChannelMessageSend(userChannel.ID, fmt.Sprintf("You have been banned from %v for acquiring %v warnings.\nLast warning was: %v",
+		_, _ = d.Sess.ChannelMessageSend(userChannel.ID, fmt.Sprintf("You have been banned from %v for acquiring %v warnings.\nLast warning was: %v",
 			g.Name, gc.MaxWarns, reason))
 	}
-	if err := msg.Discord.Sess.GuildBanCreateWithReason(g.ID, targetMember.User.ID, fmt.Sprintf("Acquired %v warnings.", gc.MaxWarns), 0); err != nil {
-		_, _ = msg.Reply("Failed to ban user!")
-		return
+	if err := d.Sess.GuildBanCreateWithReason(g.ID, target.ID, fmt.Sprintf("Acquired %v warnings.", gc.MaxWarns), 0); err != nil {
+		return "", &bot.CommandError{Kind: bot.ErrorInternal, Message: "Failed to ban user!", Err: err}
 	}
 
 	t := time.Now()
 	for _, warn := range warns {
 		warn.IsValid = false
-		warn.ClearedByID = &msg.Discord.BotUser().ID
+		warn.ClearedByID = &d.BotUser().ID
 		warn.ClearedAt = &t
 		if err := m.db.UpdateMemberWarn(warn); err != nil {
 			m.Logger.Error("could not update warn", zap.Error(err), zap.Int("warnID", warn.UID))
 		}
 	}
+	return fmt.Sprintf("%v has been banned for acquiring %v warnings", target.Mention(), gc.MaxWarns), nil
 }
 
 func newWarnLogCommand(m *module) *bot.ModuleCommand {
@@ -130,8 +226,7 @@ func newWarnLogCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "warnlog",
 		Description:      "Displays a users warns",
-		Triggers:         []string{"m?warnlog"},
-		Usage:            "m?warnlog [user] <page>",
+		Triggers:         []string{"warnlog"},
 		Cooldown:         time.Second * 5,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionManageMessages,
@@ -140,90 +235,114 @@ func newWarnLogCommand(m *module) *bot.ModuleCommand {
 		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         false,
 		Enabled:          true,
-		Execute:          m.warnlogCommand,
+		Arguments: []*bot.CommandArgument{
+			{Name: "user", Type: bot.ArgumentUser, Required: true},
+		},
+		Run: m.warnlogCommand,
 	}
 }
 
-func (m *module) warnlogCommand(msg *discord.DiscordMessage) {
-	if len(msg.Args()) < 2 {
-		return
-	}
-	page := 0
-	var err error
-	if len(msg.Args()) > 2 {
-		page, err = strconv.Atoi(msg.Args()[2])
-		if err != nil || page < 1 {
-			_, _ = msg.Reply("Invalid page")
-			return
-		}
-		page--
-	}
-
-	targetUser, err := msg.GetMemberOrUserAtArg(1)
-	if err != nil {
-		_, _ = msg.Reply("Could not find that user!")
-		return
-	}
+func (m *module) warnlogCommand(msg *discord.DiscordMessage, args *bot.CommandArgs) error {
+	targetUser := args.User("user")
 
 	warns, err := m.db.GetMemberWarns(msg.GuildID(), targetUser.ID)
 	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
-		return
+		return bot.InternalError(err)
 	}
 
-	embed := builders.NewEmbedBuilder().
-		WithTitle(fmt.Sprintf("Warnings issued to %v", targetUser.String())).
-		WithOkColor().
-		WithFooter(fmt.Sprintf("Page %v", page+1), "")
-
+	title := fmt.Sprintf("Warnings issued to %v", targetUser.String())
 	if len(warns) <= 0 {
-		embed.WithDescription("No warns")
+		embed := builders.NewEmbedBuilder().
+			WithTitle(title).
+			WithOkColor().
+			WithDescription("No warns")
 		_, _ = msg.ReplyEmbed(embed.Build())
-		return
-	}
-	if page*10 > len(warns) {
-		_, _ = msg.Reply("Page does not exist.")
-		return
+		return nil
 	}
 
-	warns = warns[page*10 : min(page*10+10, len(warns))]
-	userCache := make(map[string]*discordgo.User) // to cache users in case someone authored or cleared multiple
-	for _, warn := range warns {
-		field := &discordgo.MessageEmbedField{}
-		field.Value = warn.Reason
+	pages := warnPages(msg.Sess, title, warns)
+	return bot.NewPaginator(m, "warnpages", pages).Args(&warnLogArgs{UserID: targetUser.ID}).Send(msg)
+}
 
-		gb, ok := userCache[warn.GivenByID]
-		if !ok {
-			gb, err = msg.Discord.Sess.User(warn.GivenByID)
+// warnLogArgs is what the warnlog paginator needs to show the warns again.
+type warnLogArgs struct {
+	UserID string
+}
+
+func newWarnLogPages(m *module) *bot.ModulePages {
+	return &bot.ModulePages{
+		Mod:  m,
+		Name: "warnpages",
+		Pages: func(it *discord.DiscordInteraction, args *bot.ComponentArgs) (bot.PageSource, error) {
+			var wa warnLogArgs
+			if err := args.Decode(&wa); err != nil {
+				return nil, err
+			}
+			targetUser, err := it.Sess.User(wa.UserID)
 			if err != nil {
-				_, _ = msg.Reply("There was an issue, please try again!")
-				continue
+				return nil, err
 			}
-			userCache[warn.GivenByID] = gb
-		}
+			warns, err := m.db.GetMemberWarns(it.GuildID(), wa.UserID)
+			if err != nil {
+				return nil, err
+			}
+			return warnPages(it.Sess, fmt.Sprintf("Warnings issued to %v", targetUser.String()), warns), nil
+		},
+	}
+}
 
-		if warn.IsValid {
-			field.Name = fmt.Sprintf("ID: %v | Issued by %v (%v) %v", warn.UID, gb.String(), gb.ID, humanize.Time(warn.GivenAt))
-			embed.Fields = append(embed.Fields, field)
-			continue
+// warnPages renders warns 10 at a time.
+func warnPages(sess discord.DiscordSession, title string, warns []*Warn) bot.PageSource {
+	userCache := make(map[string]*discordgo.User) // to cache users in case someone authored or cleared multiple
+	getUser := func(userID string) (*discordgo.User, error) {
+		if u, ok := userCache[userID]; ok {
+			return u, nil
 		}
-		if warn.ClearedByID == nil {
-			continue
+		u, err := sess.User(userID)
+		if err != nil {
+			return nil, err
 		}
-
-		cb, ok := userCache[*warn.ClearedByID]
-		if !ok {
-			cb, err = msg.Discord.Sess.User(*warn.ClearedByID)
-			if err != nil {
-				_, _ = msg.Reply("There was an issue, please try again!")
-				continue
+		userCache[userID] = u
+		return u, nil
+	}
+
+	pageCount := bot.PageCount(len(warns), 10)
+	return bot.PageFunc{
+		Count: pageCount,
+		Render: func(index int) (*discordgo.MessageEmbed, error) {
+			embed := builders.NewEmbedBuilder().
+				WithTitle(title).
+				WithOkColor().
+				WithFooter(fmt.Sprintf("Page %v / %v", index+1, pageCount), "")
+
+			for _, warn := range warns[index*10 : min(index*10+10, len(warns))] {
+				field := &discordgo.MessageEmbedField{}
+				field.Value = warn.Reason
+
+				gb, err := getUser(warn.GivenByID)
+				if err != nil {
+					return nil, err
+				}
+
+				if warn.IsValid {
+					field.Name = fmt.Sprintf("ID: %v | Issued by %v (%v) %v", warn.UID, gb.String(), gb.ID, humanize.Time(warn.GivenAt))
+					embed.Fields = append(embed.Fields, field)
+					continue
+				}
+				if warn.ClearedByID == nil {
+					continue
+				}
+
+				cb, err := getUser(*warn.ClearedByID)
+				if err != nil {
+					return nil, err
+				}
+				field.Name = fmt.Sprintf("ID: %v | !CLEARED! | Cleared by %v (%v) %v", warn.UID, cb.String(), cb.ID, humanize.Time(*warn.ClearedAt))
+				embed.Fields = append(embed.Fields, field)
 			}
-			userCache[*warn.ClearedByID] = cb
-		}
-		field.Name = fmt.Sprintf("ID: %v | !CLEARED! | Cleared by %v (%v) %v", warn.UID, cb.String(), cb.ID, humanize.Time(*warn.ClearedAt))
-		embed.Fields = append(embed.Fields, field)
+			return embed.Build(), nil
+		},
 	}
-	_, _ = msg.ReplyEmbed(embed.Build())
 }
 
 func newWarnCountCommand(m *module) *bot.ModuleCommand {
@@ -231,8 +350,8 @@ func newWarnCountCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "warncount",
 		Description:      "Displays how many warns a user has. User can be specified. Message author will be used if no user is provided.",
-		Triggers:         []string{"m?warncount"},
-		Usage:            "m?warncount <user>",
+		Triggers:         []string{"warncount"},
+		Usage:            "warncount <user>",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -241,35 +360,33 @@ func newWarnCountCommand(m *module) *bot.ModuleCommand {
 		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         false,
 		Enabled:          true,
-		Execute:          m.warncountCommand,
+		Run:              m.warncountCommand,
 	}
 }
 
-func (m *module) warncountCommand(msg *discord.DiscordMessage) {
+func (m *module) warncountCommand(msg *discord.DiscordMessage, _ *bot.CommandArgs) error {
 	gc, err := m.db.GetGuild(msg.GuildID())
 	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
-		return
+		return bot.InternalError(err)
 	}
 
 	if !gc.UseWarns {
-		_, _ = msg.Reply("Warnings are not enabled")
-		return
+		return errWarnsDisabled
 	}
 
 	targetUser := msg.Author()
 	if len(msg.Args()) > 1 {
 		targetUser, err = msg.GetMemberOrUserAtArg(1)
 		if err != nil {
-			_, _ = msg.Reply("Could not find that user!")
-			return
+			return bot.NotFoundError("Could not find that user!")
 		}
 	}
 	warns, err := m.db.GetMemberWarnsIfActive(msg.GuildID(), targetUser.ID)
 	if err != nil {
-		return
+		return bot.InternalError(err)
 	}
 	_, _ = msg.Reply(fmt.Sprintf("%v is at %v/%v warns", targetUser.String(), len(warns), gc.MaxWarns))
+	return nil
 }
 
 func newClearWarnCommand(m *module) *bot.ModuleCommand {
@@ -277,8 +394,8 @@ func newClearWarnCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "pardon",
 		Description:      "Pardons a user. Opens a menu to clear a warn belonging to them.",
-		Triggers:         []string{"m?pardon", "m?clearwarn"},
-		Usage:            "m?pardon <user>",
+		Triggers:         []string{"pardon", "clearwarn"},
+		Usage:            "pardon <user>",
 		Cooldown:         time.Second * 3,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionBanMembers,
@@ -316,46 +433,22 @@ func (m *module) clearwarnCommand(msg *discord.DiscordMessage) {
 		sb.WriteString(fmt.Sprintf("`%2v.` | '%v' given %v\n", i+1, entry.Reason, humanize.Time(entry.GivenAt)))
 	}
 	sb.WriteString("\nType `cancel` to exit")
-	menu, err := msg.Reply(sb.String())
-	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
-		return
-	}
-
-	key := fmt.Sprintf("%v:%v", msg.ChannelID(), msg.AuthorID())
-	cb, err := m.Bot.Callbacks.Make(key)
-	if err != nil {
-		return
-	}
-
 	var n int
-	var reply *discord.DiscordMessage
-	// this needs a timeout
-	for {
-		select {
-		case reply = <-cb:
-		case <-time.After(time.Second * 30):
-			m.Bot.Callbacks.Delete(key)
-			_ = msg.Sess.ChannelMessageDelete(menu.ChannelID, menu.ID)
-			return
-		}
-
+	reply, err := m.Bot.Prompts.Prompt(msg, sb.String(), bot.PromptOptions{Timeout: time.Second * 30}, func(reply *discord.DiscordMessage) bool {
 		if strings.ToLower(reply.RawContent()) == "cancel" {
-			m.Bot.Callbacks.Delete(key)
-			_ = msg.Sess.ChannelMessageDelete(menu.ChannelID, menu.ID)
-			_ = msg.Sess.ChannelMessageDelete(reply.Message.ChannelID, reply.Message.ID)
-			return
-		}
-
-		n, err = strconv.Atoi(reply.RawContent())
-		if err == nil && n-1 >= 0 && n-1 < len(entries) {
-			break
+			return true
 		}
+		i, err := strconv.Atoi(reply.RawContent())
+		n = i
+		return err == nil && n-1 >= 0 && n-1 < len(entries)
+	})
+	if err != nil {
+		return
 	}
-
-	m.Bot.Callbacks.Delete(key)
-	_ = msg.Sess.ChannelMessageDelete(menu.ChannelID, menu.ID)
 	_ = msg.Sess.ChannelMessageDelete(reply.Message.ChannelID, reply.Message.ID)
+	if strings.ToLower(reply.RawContent()) == "cancel" {
+		return
+	}
 
 	selectedEntry := entries[n-1]
 	t := time.Now()
@@ -375,8 +468,8 @@ func newClearAllWarnsCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "pardonall",
 		Description:      "Pardons all active warns for a member",
-		Triggers:         []string{"m?pardonall", "m?clearallwarns"},
-		Usage:            "m?pardonall <user>",
+		Triggers:         []string{"pardonall", "clearallwarns"},
+		Usage:            "pardonall <user>",
 		Cooldown:         time.Second * 5,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    discordgo.PermissionBanMembers,
@@ -385,24 +478,22 @@ func newClearAllWarnsCommand(m *module) *bot.ModuleCommand {
 		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         false,
 		Enabled:          true,
-		Execute:          m.clearallwarnsCommand,
+		Run:              m.clearallwarnsCommand,
 	}
 }
-func (m *module) clearallwarnsCommand(msg *discord.DiscordMessage) {
+func (m *module) clearallwarnsCommand(msg *discord.DiscordMessage, _ *bot.CommandArgs) error {
 	if len(msg.Args()) < 2 {
-		return
+		return bot.UsageError("Whose warns should be cleared?")
 	}
 
 	targetMember, err := msg.GetMemberAtArg(1)
 	if err != nil {
-		_, _ = msg.Reply("Could not find that member")
-		return
+		return bot.NotFoundError("Could not find that member")
 	}
 
 	warns, err := m.db.GetMemberWarns(msg.GuildID(), targetMember.User.ID)
 	if err != nil {
-		_, _ = msg.Reply("There was an issue, please try again!")
-		return
+		return bot.InternalError(err)
 	}
 
 	t := time.Now()
@@ -415,4 +506,5 @@ func (m *module) clearallwarnsCommand(msg *discord.DiscordMessage) {
 		}
 	}
 	_, _ = msg.Reply(fmt.Sprintf("Cleared active warns issued to %v", targetMember.Mention()))
+	return nil
 }
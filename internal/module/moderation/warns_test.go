@@ -0,0 +1,166 @@
+package moderation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/internal/database/mocks"
+	"github.com/intrntsrfr/meido/internal/structs"
+	"github.com/intrntsrfr/meido/pkg/mio/bottest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moderationDB adds in memory filters and warns to the recording database.
+type moderationDB struct {
+	*mocks.DBMock
+	filters []*Filter
+	warns   []*Warn
+}
+
+func (db *moderationDB) CreateGuildFilter(guildID, phrase string) error {
+	db.Record("CreateGuildFilter", guildID, phrase)
+	db.filters = append(db.filters, &Filter{UID: len(db.filters) + 1, GuildID: guildID, Phrase: phrase})
+	return nil
+}
+
+func (db *moderationDB) GetGuildFilterByPhrase(guildID, phrase string) (*Filter, error) {
+	for _, f := range db.filters {
+		if f.GuildID == guildID && f.Phrase == phrase {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("no filter %q", phrase)
+}
+
+func (db *moderationDB) GetGuildFilters(guildID string) ([]*Filter, error) {
+	var filters []*Filter
+	for _, f := range db.filters {
+		if f.GuildID == guildID {
+			filters = append(filters, f)
+		}
+	}
+	return filters, nil
+}
+
+func (db *moderationDB) DeleteGuildFilter(filterID int) error {
+	db.Record("DeleteGuildFilter", filterID)
+	return nil
+}
+
+func (db *moderationDB) DeleteGuildFilters(guildID string) error {
+	db.Record("DeleteGuildFilters", guildID)
+	return nil
+}
+
+func (db *moderationDB) CreateMemberWarn(guildID, userID, reason, authorID string) error {
+	db.Record("CreateMemberWarn", guildID, userID, reason, authorID)
+	db.warns = append(db.warns, &Warn{UID: len(db.warns) + 1, GuildID: guildID, UserID: userID, Reason: reason,
+		GivenByID: authorID, GivenAt: time.Now(), IsValid: true})
+	return nil
+}
+
+func (db *moderationDB) GetGuildWarns(guildID string) ([]*Warn, error) {
+	return db.findWarns(func(w *Warn) bool { return w.GuildID == guildID }), nil
+}
+
+func (db *moderationDB) GetGuildWarnsIfActive(guildID string) ([]*Warn, error) {
+	return db.findWarns(func(w *Warn) bool { return w.GuildID == guildID && w.IsValid }), nil
+}
+
+func (db *moderationDB) ClearActiveUserWarns(guildID, userID, clearedByID string) error {
+	db.Record("ClearActiveUserWarns", guildID, userID, clearedByID)
+	return nil
+}
+
+func (db *moderationDB) GetMemberWarns(guildID, userID string) ([]*Warn, error) {
+	return db.findWarns(func(w *Warn) bool { return w.GuildID == guildID && w.UserID == userID }), nil
+}
+
+func (db *moderationDB) GetMemberWarnsIfActive(guildID, userID string) ([]*Warn, error) {
+	return db.findWarns(func(w *Warn) bool { return w.GuildID == guildID && w.UserID == userID && w.IsValid }), nil
+}
+
+func (db *moderationDB) UpdateMemberWarn(warn *Warn) error {
+	db.Record("UpdateMemberWarn", warn.UID, warn.IsValid)
+	for i, w := range db.warns {
+		if w.UID == warn.UID {
+			c := *warn
+			db.warns[i] = &c
+		}
+	}
+	return nil
+}
+
+func (db *moderationDB) findWarns(match func(w *Warn) bool) []*Warn {
+	var warns []*Warn
+	for _, w := range db.warns {
+		if match(w) {
+			c := *w
+			warns = append(warns, &c)
+		}
+	}
+	return warns
+}
+
+type testHarness struct {
+	*bottest.Harness
+	m   *module
+	db  *moderationDB
+	mod *discordgo.User
+}
+
+// newTestHarness registers the module in a harness where mod can ban and warn
+// members without roles, and warns are enabled with maxWarns.
+func newTestHarness(t *testing.T, maxWarns int) *testHarness {
+	h := bottest.New(t)
+	h.Session.AddRole(bottest.GuildID, "300", "Bot", 2, discordgo.PermissionBanMembers)
+	h.Session.AddRole(bottest.GuildID, "301", "Mod", 1, discordgo.PermissionBanMembers)
+	require.NoError(t, h.Session.GuildMemberRoleAdd(bottest.GuildID, bottest.BotUserID, "300"))
+
+	db := &moderationDB{DBMock: mocks.NewDB()}
+	require.NoError(t, db.UpdateGuild(&structs.Guild{GuildID: bottest.GuildID, UseWarns: true, MaxWarns: maxWarns}))
+	m := newModule(h.Bot, db, h.Bot.Logger)
+	h.Register(m)
+	return &testHarness{Harness: h, m: m, db: db, mod: h.Member("30", "mod", "301")}
+}
+
+func TestWarnCommand(t *testing.T) {
+	h := newTestHarness(t, 3)
+	target := h.Member("20", "spammer")
+
+	h.Dispatch(bottest.ChannelID, h.mod, "m?warn <@20> spam")
+	h.Wait()
+	assert.Contains(t, h.db.Calls(), "CreateMemberWarn(100, 20, spam, 30)")
+	assert.Equal(t, "<@20> has been warned\nThey now have 1/3 warnings", h.LastReply().Content)
+	require.Len(t, h.Session.DMs(target.ID), 1)
+	assert.Equal(t, "You have been warned in Test guild.\nYou were warned for: spam\nYou now have 1/3 warnings",
+		h.Session.DMs(target.ID)[0].Content)
+}
+
+func TestWarnCommand_Ban(t *testing.T) {
+	h := newTestHarness(t, 2)
+	h.Member("20", "spammer")
+	require.NoError(t, h.db.CreateMemberWarn(bottest.GuildID, "20", "spam", "30"))
+
+	h.Dispatch(bottest.ChannelID, h.mod, "m?warn <@20> more spam")
+	h.Wait()
+	assert.Contains(t, h.db.Calls(), "CreateMemberWarn(100, 20, more spam, 30)")
+	assert.Contains(t, h.db.Calls(), "UpdateMemberWarn(1, false)")
+	assert.Equal(t, "<@20> has been banned for acquiring 2 warnings", h.LastReply().Content)
+	bans := h.Session.Bans(bottest.GuildID)
+	require.Len(t, bans, 1)
+	assert.Equal(t, "Acquired 2 warnings.", bans[0].Reason)
+}
+
+func TestWarnCommand_Hierarchy(t *testing.T) {
+	h := newTestHarness(t, 3)
+	h.Member("20", "other mod", "301")
+
+	h.Dispatch(bottest.ChannelID, h.mod, "m?warn <@20> spam")
+	h.Wait()
+	assert.Equal(t, "no (you can only warn users who are below you and me in the role hierarchy)", h.LastReply().Content)
+	assert.NotContains(t, h.db.Calls(), "CreateMemberWarn(100, 20, spam, 30)")
+}
@@ -1,12 +1,14 @@
 package search
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/google/uuid"
 	"github.com/intrntsrfr/meido/internal/module/search/service"
 	iutils "github.com/intrntsrfr/meido/internal/utils"
 	"github.com/intrntsrfr/meido/pkg/mio"
@@ -17,28 +19,23 @@ import (
 
 type module struct {
 	*bot.ModuleBase
-	search     *service.Service
-	imageCache *service.ImageSearchCache
+	search *service.Service
+	images *imageResults
 }
 
-const (
-	imageSearchHandler string = "image_search"
-)
-
 func New(b *bot.Bot, logger mio.Logger) bot.Module {
 	logger = logger.Named("Search")
 	return &module{
 		ModuleBase: bot.NewModule(b, "Search", logger),
 		search:     service.NewService(b.Config.GetString("youtube_token"), b.Config.GetString("open_weather_key")),
-		imageCache: service.NewImageSearchCache(),
+		images:     newImageResults(),
 	}
 }
 
 func (m *module) Hook() error {
-	if err := m.RegisterMessageComponents(newImageComponentHandler(m)); err != nil {
+	if err := m.RegisterPages(newImagePages(m)); err != nil {
 		return err
 	}
-
 	if err := m.RegisterCommands(
 		newWeatherCommand(m),
 		newYouTubeCommand(m),
@@ -54,8 +51,8 @@ func newWeatherCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "weather",
 		Description:      "Finds the weather at a provided location",
-		Triggers:         []string{"m?weather"},
-		Usage:            "m?weather [city]",
+		Triggers:         []string{"weather"},
+		Usage:            "weather [city]",
 		Cooldown:         0,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -102,8 +99,8 @@ func newYouTubeCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "youtube",
 		Description:      "Search for a YouTube video",
-		Triggers:         []string{"m?youtube", "m?yt"},
-		Usage:            "m?yt [query]",
+		Triggers:         []string{"youtube", "yt"},
+		Usage:            "yt [query]",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -143,8 +140,8 @@ func newImageCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "image",
 		Description:      "Search for an image",
-		Triggers:         []string{"m?image", "m?img", "m?im"},
-		Usage:            "m?img [query]",
+		Triggers:         []string{"image", "img", "im"},
+		Usage:            "img [query]",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -168,110 +165,99 @@ func newImageCommand(m *module) *bot.ModuleCommand {
 				return
 			}
 
-			nextID := uuid.New().String()
-			prevID := uuid.New().String()
-			stopID := uuid.New().String()
-
-			embed := builders.NewEmbedBuilder().
-				WithTitle("Google Images search results").
-				WithOkColor().
-				WithImageUrl(links[0]).
-				WithFooter(fmt.Sprintf("Image [ %v / %v ]", 1, len(links)), "").
-				Build()
-
-			buttons := builders.NewActionRowBuilder().
-				AddButton("⬅️", discordgo.PrimaryButton, prevID).
-				AddButton("➡️", discordgo.PrimaryButton, nextID).
-				AddButton("⏹️", discordgo.PrimaryButton, stopID).
-				Build()
-
-			replyData := builders.NewMessageSendBuilder().
-				Embed(embed).
-				AddActionRow(buttons).
-				Build()
-
-			m.SetMessageComponentCallback(prevID, imageSearchHandler)
-			m.SetMessageComponentCallback(nextID, imageSearchHandler)
-			m.SetMessageComponentCallback(stopID, imageSearchHandler)
-
-			defer func() {
-				m.RemoveMessageComponentCallback(prevID)
-				m.RemoveMessageComponentCallback(nextID)
-				m.RemoveMessageComponentCallback(stopID)
-			}()
-
-			reply, err := msg.ReplyComplex(replyData)
+			key, err := m.images.add(links, imagePagesTimeout)
 			if err != nil {
+				_, _ = msg.Reply("There was an issue, please try again!")
 				return
 			}
-			searchData := service.NewImageSearch(msg.Message, reply, links, nextID, prevID, stopID)
-			m.imageCache.Set(searchData)
+			_ = bot.NewPaginator(m, "imagepages", imagePages(links)).Args(&imageArgs{Key: key}).Send(msg)
+		},
+	}
+}
 
-			defer func() {
-				m.imageCache.Delete(reply.ID)
-				reply.Components = nil
-				if len(reply.Embeds) > 0 {
-					_, _ = msg.Sess.ChannelMessageEditComplex(&discordgo.MessageEdit{
-						Components: []discordgo.MessageComponent{},
-						ID:         reply.ID,
-						Channel:    reply.ChannelID,
-						Embed:      reply.Embeds[0],
-					})
-				}
-			}()
+// imagePagesTimeout is how long the buttons of image results can be used.
+const imagePagesTimeout = time.Second * 30
 
-			for {
-				select {
-				case id := <-searchData.UpdateCh:
-					switch id {
-					case nextID:
-						emb := searchData.UpdateEmbed(1)
-						_, _ = msg.Sess.ChannelMessageEditEmbed(reply.ChannelID, reply.ID, emb)
-					case prevID:
-						emb := searchData.UpdateEmbed(-1)
-						_, _ = msg.Sess.ChannelMessageEditEmbed(reply.ChannelID, reply.ID, emb)
-					case stopID:
-						_ = msg.Sess.ChannelMessageDelete(reply.ChannelID, reply.ID)
-						_ = msg.Sess.ChannelMessageDelete(msg.ChannelID(), msg.Message.ID)
-						m.imageCache.Delete(reply.ID)
-						return
-					}
-				case <-time.After(time.Second * 15):
-					return
-				}
+// imageArgs is the key the links of an image search are kept under.
+type imageArgs struct {
+	Key string
+}
+
+func newImagePages(m *module) *bot.ModulePages {
+	return &bot.ModulePages{
+		Mod:     m,
+		Name:    "imagepages",
+		Timeout: imagePagesTimeout,
+		Pages: func(_ *discord.DiscordInteraction, args *bot.ComponentArgs) (bot.PageSource, error) {
+			var ia imageArgs
+			if err := args.Decode(&ia); err != nil {
+				return nil, err
 			}
+			// results that are gone, like after a restart, have no pages, which
+			// removes the buttons
+			links, _ := m.images.get(ia.Key)
+			return imagePages(links), nil
 		},
 	}
 }
 
-func newImageComponentHandler(m *module) *bot.ModuleMessageComponent {
-	return &bot.ModuleMessageComponent{
-		Mod:           m,
-		Name:          imageSearchHandler,
-		Cooldown:      0,
-		CooldownScope: bot.CooldownScopeChannel,
-		Permissions:   0,
-		UserType:      bot.UserTypeAny,
-		CheckBotPerms: false,
-		Enabled:       true,
-		Execute: func(dmc *discord.DiscordMessageComponent) {
-			msg, ok := m.imageCache.Get(dmc.Interaction.Message.ID)
-			if !ok {
-				return
-			}
-			resp := &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseUpdateMessage,
-				Data: nil,
-			}
-			dmc.Sess.InteractionRespond(dmc.Interaction, resp)
-
-			if dmc.Interaction.GuildID != "" && dmc.Interaction.Member.User.ID != msg.AuthorID() {
-				return
-			}
-			if dmc.Interaction.GuildID == "" && dmc.Interaction.User.ID != msg.AuthorID() {
-				return
-			}
-			msg.UpdateCh <- dmc.Interaction.MessageComponentData().CustomID
+func imagePages(links []string) bot.PageSource {
+	return bot.PageFunc{
+		Count: len(links),
+		Render: func(index int) (*discordgo.MessageEmbed, error) {
+			return builders.NewEmbedBuilder().
+				WithTitle("Google Images search results").
+				WithOkColor().
+				WithImageUrl(links[index]).
+				WithFooter(fmt.Sprintf("Image [ %v / %v ]", index+1, len(links)), "").
+				Build(), nil
 		},
 	}
 }
+
+// imageResults keeps the links of image searches for as long as their buttons can
+// be used, so paging does not search again, and every page is from the same results.
+type imageResults struct {
+	mu      sync.Mutex
+	results map[string]*imageResult
+}
+
+type imageResult struct {
+	links   []string
+	expires time.Time
+}
+
+func newImageResults() *imageResults {
+	return &imageResults{results: make(map[string]*imageResult)}
+}
+
+// add keeps links for ttl, and returns the key they are kept under. Keys are
+// random, so a key from before a restart never finds the links of another search.
+func (r *imageResults) add(links []string, ttl time.Duration) (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	key := hex.EncodeToString(b)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for k, res := range r.results {
+		if now.After(res.expires) {
+			delete(r.results, k)
+		}
+	}
+	r.results[key] = &imageResult{links: links, expires: now.Add(ttl)}
+	return key, nil
+}
+
+func (r *imageResults) get(key string) ([]string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.results[key]
+	if !ok || time.Now().After(res.expires) {
+		return nil, false
+	}
+	return res.links, true
+}
@@ -3,15 +3,12 @@ package service
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"github.com/bwmarrin/discordgo"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 )
 
 type Service struct {
@@ -129,89 +126,3 @@ func (s *Service) SearchYoutube(query string) ([]string, error) {
 	}
 	return ids, nil
 }
-
-type ImageSearchCache struct {
-	sync.Mutex
-	images map[string]*ImageSearch
-}
-
-func NewImageSearchCache() *ImageSearchCache {
-	return &ImageSearchCache{images: make(map[string]*ImageSearch)}
-}
-
-func (c *ImageSearchCache) Set(i *ImageSearch) {
-	c.Lock()
-	defer c.Unlock()
-	c.images[i.BotMsgID()] = i
-}
-func (c *ImageSearchCache) Get(id string) (*ImageSearch, bool) {
-	c.Lock()
-	defer c.Unlock()
-	if img, ok := c.images[id]; ok {
-		return img, true
-	}
-	return nil, false
-}
-func (c *ImageSearchCache) Delete(id string) {
-	c.Lock()
-	defer c.Unlock()
-	delete(c.images, id)
-}
-
-type ImageSearch struct {
-	sync.Mutex
-	AuthorMsg  *discordgo.Message
-	BotMsg     *discordgo.Message
-	ImageLinks []string
-	ImageIndex int
-	NextID     string
-	PrevID     string
-	StopID     string
-	UpdateCh   chan string
-}
-
-func NewImageSearch(a, b *discordgo.Message, links []string, nID, pID, sID string) *ImageSearch {
-	return &ImageSearch{
-		AuthorMsg:  a,
-		BotMsg:     b,
-		ImageLinks: links,
-		ImageIndex: 0,
-		NextID:     nID,
-		PrevID:     pID,
-		StopID:     sID,
-		UpdateCh:   make(chan string),
-	}
-}
-
-func (i *ImageSearch) AuthorID() string {
-	if i.AuthorMsg == nil || i.AuthorMsg.Author == nil {
-		return ""
-	}
-	return i.AuthorMsg.Author.ID
-}
-func (i *ImageSearch) AuthorMsgID() string {
-	if i.AuthorMsg == nil {
-		return ""
-	}
-	return i.AuthorMsg.ID
-}
-func (i *ImageSearch) BotMsgID() string {
-	if i.BotMsg == nil {
-		return ""
-	}
-	return i.BotMsg.ID
-}
-
-func (i *ImageSearch) UpdateEmbed(delta int) *discordgo.MessageEmbed {
-	newIndex := i.ImageIndex + delta
-	if newIndex > len(i.ImageLinks)-1 {
-		newIndex = 0
-	} else if newIndex < 0 {
-		newIndex = len(i.ImageLinks) - 1
-	}
-	emb := i.BotMsg.Embeds[0]
-	emb.Image.URL = i.ImageLinks[newIndex]
-	emb.Footer.Text = fmt.Sprintf("Image [ %v / %v ]", newIndex+1, len(i.ImageLinks))
-	i.ImageIndex = newIndex
-	return emb
-}
@@ -38,8 +38,8 @@ func newTestCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "test",
 		Description:      "This is an incredible test command",
-		Triggers:         []string{"m?test"},
-		Usage:            "m?test",
+		Triggers:         []string{"test"},
+		Usage:            "test",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -55,76 +55,68 @@ func newTestCommand(m *module) *bot.ModuleCommand {
 }
 
 func newSlashCommand(m *module) *bot.ModuleApplicationCommand {
-	bld := bot.NewModuleApplicationCommandBuilder(m, "permissions").
+	return bot.NewModuleApplicationCommandBuilder(m, "permissions").
 		Type(discordgo.ChatApplicationCommand).
 		Description("Get or edit permissions for a user or a role").
+		Guilds(m.Bot.Config.GetStringSlice("dev_guild_ids")...).
 		AddSubcommandGroup(
-			builders.NewSubCommandGroupBuilder("user", "Get or edit permissions for a user").
-				AddSubCommand(builders.NewSubCommandBuilder("get", "Get permissions for a user").
+			bot.NewSubcommandGroup("user", "Get or edit permissions for a user").
+				AddSubcommand(builders.NewSubCommandBuilder("get", "Get permissions for a user").
 					AddOption(&discordgo.ApplicationCommandOption{
 						Name:        "user",
 						Description: "The user to get",
 						Type:        discordgo.ApplicationCommandOptionUser,
 						Required:    true,
 					}).Build(),
+					userPermissions("get"),
 				).
-				AddSubCommand(builders.NewSubCommandBuilder("edit", "Edit permissions for a user").
+				AddSubcommand(builders.NewSubCommandBuilder("edit", "Edit permissions for a user").
 					AddOption(&discordgo.ApplicationCommandOption{
 						Name:        "user",
 						Description: "The user to edit",
 						Type:        discordgo.ApplicationCommandOptionUser,
 						Required:    true,
 					}).Build(),
-				).Build(),
+					userPermissions("edit"),
+				),
 		).
 		AddSubcommandGroup(
-			builders.NewSubCommandGroupBuilder("role", "Get or edit permissions for a role").
-				AddSubCommand(builders.NewSubCommandBuilder("get", "Get permissions for a role").
+			bot.NewSubcommandGroup("role", "Get or edit permissions for a role").
+				AddSubcommand(builders.NewSubCommandBuilder("get", "Get permissions for a role").
 					AddOption(&discordgo.ApplicationCommandOption{
 						Name:        "role",
 						Description: "The role to get",
 						Type:        discordgo.ApplicationCommandOptionRole,
 						Required:    true,
 					}).Build(),
+					rolePermissions("get"),
 				).
-				AddSubCommand(builders.NewSubCommandBuilder("edit", "Edit permissions for a role").
+				AddSubcommand(builders.NewSubCommandBuilder("edit", "Edit permissions for a role").
 					AddOption(&discordgo.ApplicationCommandOption{
-						Name:        "user",
+						Name:        "role",
 						Description: "The role to edit",
 						Type:        discordgo.ApplicationCommandOptionRole,
 						Required:    true,
 					}).Build(),
-				).Build(),
+					rolePermissions("edit"),
+				),
 		).
-		NoDM()
+		NoDM().
+		Build()
+}
 
-	exec := func(dac *discord.DiscordApplicationCommand) {
-		if _, ok := dac.Options("user"); ok {
-			if _, ok := dac.Options("user:get"); ok {
-				userOpt, _ := dac.Options("user:get:user")
-				user := userOpt.UserValue(dac.Sess.Real())
-				dac.Respond(fmt.Sprintf("get %s", user.Mention()))
-			} else if _, ok := dac.Options("user:edit"); ok {
-				userOpt, _ := dac.Options("user:edit:user")
-				user := userOpt.UserValue(dac.Sess.Real())
-				dac.Respond(fmt.Sprintf("edit %s", user.Mention()))
-			}
-		} else if _, ok := dac.Options("role"); ok {
-			if _, ok := dac.Options("role:get"); ok {
-				roleOpt, _ := dac.Options("role:get:role")
-				role := roleOpt.RoleValue(dac.Sess.Real(), dac.GuildID())
-				dac.Respond(fmt.Sprintf("get %s", role.Mention()))
-			} else if _, ok := dac.Options("role:edit"); ok {
-				roleOpt, _ := dac.Options("role:edit:role")
-				role := roleOpt.RoleValue(dac.Sess.Real(), dac.GuildID())
-				dac.Respond(fmt.Sprintf("edit %s", role.Mention()))
-			}
-		} else {
-			dac.RespondEphemeral("Something went wrong.")
-		}
+func userPermissions(action string) bot.SubcommandHandler {
+	return func(sub *discord.DiscordSubcommand) error {
+		user, _ := sub.User("user")
+		return sub.Respond(fmt.Sprintf("%s %s", action, user.Mention()))
 	}
+}
 
-	return bld.Execute(exec).Build()
+func rolePermissions(action string) bot.SubcommandHandler {
+	return func(sub *discord.DiscordSubcommand) error {
+		role, _ := sub.Role("role")
+		return sub.Respond(fmt.Sprintf("%s %s", action, role.Mention()))
+	}
 }
 
 func newMonkeyCommand(m *module) *bot.ModuleCommand {
@@ -132,8 +124,8 @@ func newMonkeyCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "monkey",
 		Description:      "Monkey",
-		Triggers:         []string{"m?monkey", "m?monke", "m?monki", "m?monky"},
-		Usage:            "m?monkey",
+		Triggers:         []string{"monkey", "monke", "monki", "monky"},
+		Usage:            "monkey",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeUser,
 		RequiredPerms:    0,
@@ -0,0 +1,186 @@
+package utility
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/intrntsrfr/meido/pkg/utils"
+	"github.com/intrntsrfr/meido/pkg/utils/builders"
+	"go.uber.org/zap"
+)
+
+func newPermissionSettingsCommand(m *module) *bot.ModuleCommand {
+	return &bot.ModuleCommand{
+		Mod:  m,
+		Name: "permissionsettings",
+		Description: "Lists the permission rules of the server, or allows or denies a role, channel or user the use of a command. " +
+			"Channel rules go first, then user rules, then role rules. Use 'everyone' as the role to target everyone.",
+		Triggers: []string{"settings permissions", "settings perms"},
+		Usage: "settings permissions\nsettings permissions allow fish channel #bot-commands\n" +
+			"settings permissions deny fish role everyone\nsettings permissions remove fish channel #bot-commands\n" +
+			"settings permissions clear fish",
+		Cooldown:         time.Second * 2,
+		CooldownScope:    bot.CooldownScopeChannel,
+		RequiredPerms:    discordgo.PermissionAdministrator,
+		CheckBotPerms:    false,
+		RequiresUserType: bot.UserTypeAny,
+		AllowedTypes:     discord.MessageTypeCreate,
+		AllowDMs:         false,
+		Enabled:          true,
+		Arguments: []*bot.CommandArgument{
+			{Name: "action", Type: bot.ArgumentEnum, Choices: []string{"allow", "deny", "remove", "clear"}},
+			{Name: "command", Type: bot.ArgumentString},
+			{Name: "target", Type: bot.ArgumentEnum, Choices: []string{"role", "channel", "user"}},
+			{Name: "value", Type: bot.ArgumentRest},
+		},
+		ExecuteArgs: func(msg *discord.DiscordMessage, args *bot.CommandArgs) {
+			if !args.Has("action") {
+				m.listPermissionRules(msg)
+				return
+			}
+			if !args.Has("command") {
+				_, _ = msg.Reply("Please specify a command")
+				return
+			}
+			command, err := m.findToggleName(bot.ToggleCommand, args.String("command"))
+			if err != nil {
+				_, _ = msg.Reply("Could not find that command")
+				return
+			}
+			if command == "permissionsettings" {
+				_, _ = msg.Reply("The permissions of that command cannot be changed")
+				return
+			}
+
+			action := strings.ToLower(args.String("action"))
+			if action == "clear" {
+				if err := m.Bot.Permissions.ClearRules(msg.GuildID(), command); err != nil {
+					m.Logger.Error("Clearing permission rules failed", zap.Error(err), zap.String("guildID", msg.GuildID()))
+					_, _ = msg.Reply("There was an issue, please try again!")
+					return
+				}
+				_, _ = msg.Reply(fmt.Sprintf("Cleared the permission rules of `%v`", command))
+				return
+			}
+
+			if !args.Has("target") || !args.Has("value") {
+				_, _ = msg.Reply("Please specify a role, channel or user")
+				return
+			}
+			target, _ := bot.ParsePermissionTarget(args.String("target"))
+			targetID, err := resolvePermissionTarget(msg, target, args.String("value"))
+			if err != nil {
+				_, _ = msg.Reply(fmt.Sprintf("Could not find that %v", target))
+				return
+			}
+
+			if action == "remove" {
+				err = m.Bot.Permissions.RemoveRule(msg.GuildID(), command, target, targetID)
+			} else {
+				err = m.Bot.Permissions.SetRule(&bot.PermissionRule{
+					GuildID:  msg.GuildID(),
+					Command:  command,
+					Target:   target,
+					TargetID: targetID,
+					Allow:    action == "allow",
+				})
+			}
+			if err != nil {
+				m.Logger.Error("Setting permission rule failed", zap.Error(err), zap.String("guildID", msg.GuildID()))
+				_, _ = msg.Reply("There was an issue, please try again!")
+				return
+			}
+
+			var desc string
+			switch action {
+			case "remove":
+				desc = fmt.Sprintf("Removed the rule of `%v` for %v", command, permissionTargetMention(target, targetID, msg.GuildID()))
+			case "allow":
+				desc = fmt.Sprintf("Allowed %v to use `%v`", permissionTargetMention(target, targetID, msg.GuildID()), command)
+			default:
+				desc = fmt.Sprintf("Denied %v the use of `%v`", permissionTargetMention(target, targetID, msg.GuildID()), command)
+			}
+			_, _ = msg.ReplyEmbed(builders.NewEmbedBuilder().
+				WithOkColor().
+				WithDescription(desc).
+				Build())
+		},
+	}
+}
+
+func (m *module) listPermissionRules(msg *discord.DiscordMessage) {
+	rules, err := m.Bot.Permissions.GuildRules(msg.GuildID())
+	if err != nil {
+		m.Logger.Error("Getting permission rules failed", zap.Error(err), zap.String("guildID", msg.GuildID()))
+		_, _ = msg.Reply("There was an issue, please try again!")
+		return
+	}
+	if len(rules) == 0 {
+		_, _ = msg.Reply("There are no permission rules in this server")
+		return
+	}
+
+	// embeds are used so that mentions don't ping anyone
+	var sb strings.Builder
+	for _, r := range rules {
+		action := "deny"
+		if r.Allow {
+			action = "allow"
+		}
+		sb.WriteString(fmt.Sprintf("`%v` %v %v\n", r.Command, action, permissionTargetMention(r.Target, r.TargetID, msg.GuildID())))
+	}
+	_, _ = msg.ReplyEmbed(builders.NewEmbedBuilder().
+		WithOkColor().
+		WithTitle("Permission rules").
+		WithDescription(sb.String()).
+		Build())
+}
+
+// resolvePermissionTarget returns the ID of the role, channel or user value refers to.
+func resolvePermissionTarget(msg *discord.DiscordMessage, target bot.PermissionTarget, value string) (string, error) {
+	switch target {
+	case bot.PermissionTargetRole:
+		if strings.EqualFold(value, "everyone") || value == "@everyone" {
+			return msg.GuildID(), nil
+		}
+		role, err := msg.Discord.GuildRoleByNameOrID(msg.GuildID(), value, utils.TrimRoleID(value))
+		if err != nil {
+			return "", err
+		}
+		return role.ID, nil
+	case bot.PermissionTargetChannel:
+		channel, err := msg.Discord.Channel(utils.TrimChannelID(value))
+		if err != nil {
+			return "", err
+		}
+		if channel.GuildID != msg.GuildID() {
+			return "", errors.New("channel is not in this guild")
+		}
+		return channel.ID, nil
+	default:
+		userID := utils.TrimUserID(value)
+		if !utils.IsNumber(userID) {
+			return "", errors.New("not a user mention or ID")
+		}
+		return userID, nil
+	}
+}
+
+func permissionTargetMention(target bot.PermissionTarget, targetID, guildID string) string {
+	switch target {
+	case bot.PermissionTargetRole:
+		if targetID == guildID {
+			return "@everyone"
+		}
+		return fmt.Sprintf("<@&%v>", targetID)
+	case bot.PermissionTargetChannel:
+		return fmt.Sprintf("<#%v>", targetID)
+	default:
+		return fmt.Sprintf("<@%v>", targetID)
+	}
+}
@@ -16,8 +16,8 @@ func newServerCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "server",
 		Description:      "Displays information about the server",
-		Triggers:         []string{"m?server"},
-		Usage:            "m?server",
+		Triggers:         []string{"server"},
+		Usage:            "server",
 		Cooldown:         time.Second * 5,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -85,8 +85,8 @@ func newServerSplashCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "serversplash",
 		Description:      "Displays server splash if one exists",
-		Triggers:         []string{"m?serversplash"},
-		Usage:            "m?serversplash",
+		Triggers:         []string{"serversplash"},
+		Usage:            "serversplash",
 		Cooldown:         time.Second * 5,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -124,8 +124,8 @@ func newServerIconCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "servericon",
 		Description:      "Displays server icon, if one exists",
-		Triggers:         []string{"m?servericon", "m?si", ">si"},
-		Usage:            "m?servericon",
+		Triggers:         []string{"servericon", "si"},
+		Usage:            "servericon",
 		Cooldown:         time.Second * 5,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -162,8 +162,8 @@ func newServerBannerCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "serverbanner",
 		Description:      "Displays server banner if one exists",
-		Triggers:         []string{"m?serverbanner"},
-		Usage:            "m?serverbanner",
+		Triggers:         []string{"serverbanner"},
+		Usage:            "serverbanner",
 		Cooldown:         time.Second * 5,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
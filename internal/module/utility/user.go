@@ -17,8 +17,8 @@ func newAvatarCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "avatar",
 		Description:      "Displays a users profile picture. User can be specified. Author is default.",
-		Triggers:         []string{"m?avatar", "m?av", ">av"},
-		Usage:            ">av <user>",
+		Triggers:         []string{"avatar", "av"},
+		Usage:            "av <user>",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -54,8 +54,8 @@ func newBannerCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "banner",
 		Description:      "Displays a users banner. User can be specified. Author is default.",
-		Triggers:         []string{"m?banner", ">banner"},
-		Usage:            ">banner <user>",
+		Triggers:         []string{"banner"},
+		Usage:            "banner <user>",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -105,8 +105,8 @@ func newMemberAvatarCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "memberavatar",
 		Description:      "Displays a members profile picture. User can be specified. Author is default.",
-		Triggers:         []string{"m?memberavatar", "m?mav", ">mav"},
-		Usage:            ">av <user>",
+		Triggers:         []string{"memberavatar", "mav"},
+		Usage:            "mav <user>",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -153,8 +153,8 @@ func newUserInfoCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "userinfo",
 		Description:      "Displays information about a user",
-		Triggers:         []string{"m?userinfo"},
-		Usage:            "m?userinfo <user>",
+		Triggers:         []string{"userinfo"},
+		Usage:            "userinfo <user>",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -20,6 +20,7 @@ import (
 	"github.com/intrntsrfr/meido/pkg/mio/discord"
 	"github.com/intrntsrfr/meido/pkg/utils"
 	"github.com/intrntsrfr/meido/pkg/utils/builders"
+	"go.uber.org/zap"
 )
 
 type module struct {
@@ -39,10 +40,7 @@ func New(b *bot.Bot, db database.DB, logger mio.Logger) bot.Module {
 
 func (m *module) Hook() error {
 	if err := m.RegisterApplicationCommands(
-		newColorSlash(m),
 		newUserInfoUserCommand(m),
-		newHelpSlash(m),
-		newPingSlash(m),
 		newStatsSlash(m),
 	); err != nil {
 		return err
@@ -57,8 +55,15 @@ func (m *module) Hook() error {
 		return err
 	}
 
-	if err := m.RegisterCommands(
+	if err := m.RegisterHybridCommands(
 		newPingCommand(m),
+		newColorCommand(m),
+		newHelpCommand(m),
+	); err != nil {
+		return err
+	}
+
+	if err := m.RegisterCommands(
 		newAvatarCommand(m),
 		newBannerCommand(m),
 		newMemberAvatarCommand(m),
@@ -66,11 +71,12 @@ func (m *module) Hook() error {
 		newServerIconCommand(m),
 		newServerBannerCommand(m),
 		newServerSplashCommand(m),
-		newColorCommand(m),
 		newIdTimestampCmd(m),
 		newInviteCommand(m),
 		newUserInfoCommand(m),
-		newHelpCommand(m),
+		newPrefixSettingsCommand(m),
+		newToggleSettingsCommand(m),
+		newPermissionSettingsCommand(m),
 	); err != nil {
 		return err
 	}
@@ -83,8 +89,8 @@ func NewConvertCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "convert",
 		Description:      "Converts between units",
-		Triggers:         []string{"m?convert"},
-		Usage:            "m?convert kg lb 50",
+		Triggers:         []string{"convert"},
+		Usage:            "convert kg lb 50",
 		Cooldown:         0,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -102,54 +108,30 @@ func NewConvertCommand(m *module) *bot.ModuleCommand {
 }
 
 // newPingCommand returns a new ping command.
-func newPingCommand(m *module) *bot.ModuleCommand {
-	return &bot.ModuleCommand{
+func newPingCommand(m *module) *bot.ModuleHybridCommand {
+	return &bot.ModuleHybridCommand{
 		Mod:              m,
 		Name:             "ping",
 		Description:      "Checks how fast the bot can respond to a command",
-		Triggers:         []string{"m?ping"},
-		Usage:            "m?ping",
+		Triggers:         []string{"ping"},
+		Usage:            "ping",
 		Cooldown:         time.Second * 2,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
 		CheckBotPerms:    false,
 		RequiresUserType: bot.UserTypeAny,
-		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         true,
 		Enabled:          true,
-		Execute: func(msg *discord.DiscordMessage) {
-			if len(msg.Args()) < 1 {
-				return
-			}
+		Execute: func(ctx bot.CommandContext) {
 			startTime := time.Now()
-			first, err := msg.Reply("Ping")
-			if err != nil {
+			if err := ctx.Reply("Ping"); err != nil {
 				return
 			}
-			_, _ = msg.Sess.ChannelMessageEdit(msg.Message.ChannelID, first.ID,
-				fmt.Sprintf("Pong!\nDelay: %s", time.Since(startTime)))
+			_ = ctx.EditReply(fmt.Sprintf("Pong!\nDelay: %s", time.Since(startTime)))
 		},
 	}
 }
 
-func newPingSlash(m *module) *bot.ModuleApplicationCommand {
-	bld := bot.NewModuleApplicationCommandBuilder(m, "ping").
-		Type(discordgo.ChatApplicationCommand).
-		Description("Checks how fast the bot can respond to a command")
-
-	run := func(dac *discord.DiscordApplicationCommand) {
-		startTime := time.Now()
-		dac.Respond("Pong")
-		resp := fmt.Sprintf("Pong!\nDelay: %s", time.Since(startTime))
-		respData := &discordgo.WebhookEdit{
-			Content: &resp,
-		}
-		dac.Sess.Real().InteractionResponseEdit(dac.Interaction, respData)
-	}
-
-	return bld.Execute(run).Build()
-}
-
 func newStatsSlash(m *module) *bot.ModuleApplicationCommand {
 	bld := bot.NewModuleApplicationCommandBuilder(m, "stats").
 		Type(discordgo.ChatApplicationCommand).
@@ -170,6 +152,7 @@ func newStatsSlash(m *module) *bot.ModuleApplicationCommand {
 			AddField("🧑‍🤝‍🧑 Users", fmt.Sprintf("Total: %v\nHumans: %v\nBots: %v", total, humans, bots), true).
 			AddField("💾 Memory usage", fmt.Sprintf("%v/%v", humanize.Bytes(memory.Alloc), humanize.Bytes(memory.Sys)), true).
 			AddField("🗑️ Garbage collected", humanize.Bytes(memory.TotalAlloc-memory.Alloc), true).
+			AddField("🧵 Workers", getWorkerString(m), true).
 			AddField("👑 Owner IDs", strings.Join(m.Bot.Config.GetStringSlice("owner_ids"), ", "), true).
 			AddField("🐞 Bugs", fmt.Sprint(bugs), true)
 		dac.RespondEmbed(embed.Build())
@@ -199,6 +182,20 @@ func getGuildCounts(dac *discord.DiscordApplicationCommand) ([]*discordgo.Guild,
 	return guilds, totalBots, totalHumans, totalUsers
 }
 
+func getWorkerString(m *module) string {
+	var (
+		busy, workers, queued int
+		dropped               int64
+	)
+	for _, stats := range m.Bot.Workers.Stats() {
+		busy += stats.Busy
+		workers += stats.Workers
+		queued += stats.QueueDepth
+		dropped += stats.Dropped
+	}
+	return fmt.Sprintf("Busy: %v/%v\nQueued: %v\nDropped: %v", busy, workers, queued, dropped)
+}
+
 func getCommandCountString(m *module) string {
 	countStr := "Not available"
 	count, err := m.db.GetCommandCount()
@@ -208,78 +205,38 @@ func getCommandCountString(m *module) string {
 	return countStr
 }
 
-func newColorCommand(m *module) *bot.ModuleCommand {
-	return &bot.ModuleCommand{
+func newColorCommand(m *module) *bot.ModuleHybridCommand {
+	return &bot.ModuleHybridCommand{
 		Mod:              m,
 		Name:             "color",
 		Description:      "Displays a small image of a provided color hex",
-		Triggers:         []string{"m?color"},
-		Usage:            "m?color [color hex]",
+		Triggers:         []string{"color"},
+		Usage:            "color [color hex]",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
 		CheckBotPerms:    false,
 		RequiresUserType: bot.UserTypeAny,
-		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         true,
 		Enabled:          true,
-		Execute: func(msg *discord.DiscordMessage) {
-			if len(msg.Args()) < 2 {
-				return
+		Arguments: []*bot.CommandArgument{
+			{Name: "hex", Description: "The hex string of the desired color", Type: bot.ArgumentString, Required: true},
+		},
+		Execute: func(ctx bot.CommandContext) {
+			clrStr := strings.TrimSpace(ctx.Args().String("hex"))
+			if !strings.HasPrefix(clrStr, "#") {
+				clrStr = "#" + clrStr
 			}
-
-			clrStr := msg.Args()[1]
-			clrStr = strings.TrimPrefix(clrStr, "#")
 			buf, err := generateColorPNG(clrStr)
 			if err != nil {
-				_, _ = msg.Reply("Invalid hex code")
+				_ = ctx.Reply("Invalid hex code")
 				return
 			}
-			_, _ = msg.ReplyComplex(&discordgo.MessageSend{File: &discordgo.File{Name: "color.png", Reader: buf}})
+			_ = ctx.ReplyFile(fmt.Sprintf("Color hex: `%v`", strings.ToUpper(clrStr)), "color.png", buf)
 		},
 	}
 }
 
-func newColorSlash(m *module) *bot.ModuleApplicationCommand {
-	cmd := bot.NewModuleApplicationCommandBuilder(m, "color").
-		Type(discordgo.ChatApplicationCommand).
-		Description("Show the color of a provided hex").
-		Cooldown(time.Second, bot.CooldownScopeChannel).
-		AddOption(&discordgo.ApplicationCommandOption{
-			Name:        "hex",
-			Description: "The hex string of the desired color",
-			Required:    true,
-			Type:        discordgo.ApplicationCommandOptionString,
-		})
-
-	run := func(d *discord.DiscordApplicationCommand) {
-		if len(d.Data.Options) < 1 {
-			return
-		}
-
-		clrStrOpt, ok := d.Options("hex")
-		if !ok {
-			return
-		}
-		clrStr := clrStrOpt.StringValue()
-		if !strings.HasPrefix(clrStr, "#") {
-			clrStr = "#" + strings.TrimSpace(clrStr)
-		}
-		buf, err := generateColorPNG(clrStr)
-		if err != nil {
-			d.RespondEphemeral("Invalid hex code")
-			return
-		}
-		d.RespondFile(
-			fmt.Sprintf("Color hex: `%v`", strings.ToUpper(clrStr)),
-			"color.png",
-			buf,
-		)
-	}
-
-	return cmd.Execute(run).Build()
-}
-
 func generateColorPNG(clrStr string) (*bytes.Buffer, error) {
 	clr, err := hexcolor.Parse(clrStr)
 	if err != nil {
@@ -298,8 +255,8 @@ func newIdTimestampCmd(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "idtimestamp",
 		Description:      "Converts a Discord ID to a timestamp",
-		Triggers:         []string{"m?idt", "m?idts", "m?ts", "m?idtimestamp"},
-		Usage:            "m?idt [ID]",
+		Triggers:         []string{"idt", "idts", "ts", "idtimestamp"},
+		Usage:            "idt [ID]",
 		Cooldown:         0,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -324,8 +281,8 @@ func newInviteCommand(m *module) *bot.ModuleCommand {
 		Mod:              m,
 		Name:             "invite",
 		Description:      "Sends a bot invite link and support server invite link",
-		Triggers:         []string{"m?invite"},
-		Usage:            "m?invite",
+		Triggers:         []string{"invite"},
+		Usage:            "invite",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
@@ -349,46 +306,158 @@ const (
 	helpCommandBack   = "help_command_back"
 )
 
-func newHelpSlash(m *module) *bot.ModuleApplicationCommand {
-	cmd := bot.NewModuleApplicationCommandBuilder(m, "help").
-		Type(discordgo.ChatApplicationCommand).
-		Description("Displays helpful things").
-		Cooldown(time.Second, bot.CooldownScopeChannel).
-		AddOption(&discordgo.ApplicationCommandOption{
-			Name:        "command",
-			Description: "The command to show help for",
-			Required:    false,
-			Type:        discordgo.ApplicationCommandOptionString,
-		})
+const maxPrefixLength = 10
 
-	run := func(d *discord.DiscordApplicationCommand) {
-		// if no options are provided, show the help menu
-		if len(d.Data.Options) < 1 {
-			menu := createHelpMenu(m, d.Sess, d.AuthorID())
-			_ = d.RespondComplex(menu, discordgo.InteractionResponseChannelMessageWithSource)
-			return
-		}
+func newPrefixSettingsCommand(m *module) *bot.ModuleCommand {
+	return &bot.ModuleCommand{
+		Mod:              m,
+		Name:             "prefixsettings",
+		Description:      "Shows or changes the command prefix of the server. Use 'reset' to go back to the default prefix.",
+		Triggers:         []string{"settings prefix"},
+		Usage:            "settings prefix\nsettings prefix !\nsettings prefix reset",
+		Cooldown:         time.Second * 2,
+		CooldownScope:    bot.CooldownScopeChannel,
+		RequiredPerms:    discordgo.PermissionAdministrator,
+		CheckBotPerms:    false,
+		RequiresUserType: bot.UserTypeAny,
+		AllowedTypes:     discord.MessageTypeCreate,
+		AllowDMs:         false,
+		Enabled:          true,
+		Execute: func(msg *discord.DiscordMessage) {
+			before := m.Bot.Prefixes.Prefix(msg.GuildID())
+			if len(msg.Args()) < 3 {
+				_, _ = msg.Reply(fmt.Sprintf("The prefix for this server is `%v`", before))
+				return
+			}
 
-		// otherwise, show the help for the provided command
-		cmdOpt, ok := d.Options("command")
-		if !ok {
-			d.RespondEphemeral("Command not found")
-			return
-		}
+			prefix := msg.RawArgs()[2]
+			if strings.EqualFold(prefix, "reset") {
+				prefix = m.Bot.Prefixes.DefaultPrefix()
+			}
+			if len(prefix) > maxPrefixLength {
+				_, _ = msg.Reply(fmt.Sprintf("The prefix can be at most %v characters long", maxPrefixLength))
+				return
+			}
 
-		inp := cmdOpt.StringValue()
-		if cmd, err := m.Bot.FindCommand(inp); err == nil {
-			showCommandHelp(m, d, cmd)
-			return
+			if err := m.Bot.Prefixes.SetPrefix(msg.GuildID(), prefix); err != nil {
+				m.Logger.Error("Setting prefix failed", zap.Error(err), zap.String("guildID", msg.GuildID()))
+				_, _ = msg.Reply("There was an issue, please try again!")
+				return
+			}
+			_, _ = msg.Reply(fmt.Sprintf("Prefix: `%v` -> `%v`", before, prefix))
+		},
+	}
+}
+
+func newToggleSettingsCommand(m *module) *bot.ModuleCommand {
+	return &bot.ModuleCommand{
+		Mod:              m,
+		Name:             "togglesettings",
+		Description:      "Lists what is disabled in the server, or enables or disables a module, command or passive.",
+		Triggers:         []string{"settings toggles", "settings toggle"},
+		Usage:            "settings toggles\nsettings toggles module fun\nsettings toggles command ping\nsettings toggles passive forwarddms",
+		Cooldown:         time.Second * 2,
+		CooldownScope:    bot.CooldownScopeChannel,
+		RequiredPerms:    discordgo.PermissionAdministrator,
+		CheckBotPerms:    false,
+		RequiresUserType: bot.UserTypeAny,
+		AllowedTypes:     discord.MessageTypeCreate,
+		AllowDMs:         false,
+		Enabled:          true,
+		Execute: func(msg *discord.DiscordMessage) {
+			if len(msg.Args()) < 4 {
+				toggles, err := m.Bot.Toggles.Disabled(msg.GuildID())
+				if err != nil {
+					m.Logger.Error("Getting toggles failed", zap.Error(err), zap.String("guildID", msg.GuildID()))
+					_, _ = msg.Reply("There was an issue, please try again!")
+					return
+				}
+				if len(toggles) == 0 {
+					_, _ = msg.Reply("Nothing is disabled in this server")
+					return
+				}
+				var sb strings.Builder
+				sb.WriteString("Disabled in this server:\n```\n")
+				for _, t := range toggles {
+					sb.WriteString(fmt.Sprintf("%-8v %v\n", t.Kind, t.Name))
+				}
+				sb.WriteString("```")
+				_, _ = msg.Reply(sb.String())
+				return
+			}
+
+			kind, err := bot.ParseToggleKind(msg.Args()[2])
+			if err != nil {
+				_, _ = msg.Reply("The type must be either module, command or passive")
+				return
+			}
+			name, err := m.findToggleName(kind, strings.Join(msg.Args()[3:], " "))
+			if err != nil {
+				_, _ = msg.Reply(fmt.Sprintf("Could not find that %v", kind))
+				return
+			}
+			if (kind == bot.ToggleModule && strings.EqualFold(name, m.Name())) ||
+				(kind == bot.ToggleCommand && name == "togglesettings") {
+				_, _ = msg.Reply(fmt.Sprintf("That %v cannot be disabled", kind))
+				return
+			}
+
+			disabled := !m.Bot.Toggles.IsDisabled(msg.GuildID(), kind, name)
+			if err := m.Bot.Toggles.SetDisabled(msg.GuildID(), kind, name, disabled); err != nil {
+				m.Logger.Error("Setting toggle failed", zap.Error(err), zap.String("guildID", msg.GuildID()))
+				_, _ = msg.Reply("There was an issue, please try again!")
+				return
+			}
+			if disabled {
+				_, _ = msg.Reply(fmt.Sprintf("Disabled %v `%v`", kind, name))
+				return
+			}
+			_, _ = msg.Reply(fmt.Sprintf("Enabled %v `%v`", kind, name))
+		},
+	}
+}
+
+// findToggleName returns the name a module, command or passive is toggled by.
+// Commands can also be found by their triggers.
+func (m *module) findToggleName(kind bot.ToggleKind, query string) (string, error) {
+	switch kind {
+	case bot.ToggleModule:
+		mod, err := m.Bot.FindModule(query)
+		if err != nil {
+			return "", err
+		}
+		return mod.Name(), nil
+	case bot.ToggleCommand:
+		if cmd, err := m.Bot.FindCommand(query); err == nil {
+			return cmd.Name, nil
 		}
-		d.RespondEphemeral("Command not found")
+		cmd, err := m.Bot.FindApplicationCommand(query)
+		if err != nil {
+			return "", err
+		}
+		return cmd.Name, nil
+	default:
+		pas, err := m.Bot.FindPassive(query)
+		if err != nil {
+			return "", err
+		}
+		return pas.Name, nil
 	}
+}
+
+// helpMenuArgs is the payload of the help menu components.
+type helpMenuArgs struct {
+	UserID string
+}
 
-	return cmd.Execute(run).Build()
+// helpCommandBackArgs is the payload of the back button on a command help page.
+type helpCommandBackArgs struct {
+	Module string
+	UserID string
 }
 
 // createHelpMenu creates a help menu with a select menu for selecting a module.
-func createHelpMenu(m *module, sess discord.DiscordSession, userID string) *discordgo.InteractionResponseData {
+func createHelpMenu(m *module, sess discord.DiscordSession, userID string) (*discordgo.InteractionResponseData, error) {
 	var (
 		options  = []discordgo.SelectMenuOption{}
 		modNames = []string{}
@@ -402,6 +471,11 @@ func createHelpMenu(m *module, sess discord.DiscordSession, userID string) *disc
 		modNames = append(modNames, fmt.Sprintf("`%v`", mod.Name()))
 	}
 
+	selectID, err := m.ComponentCustomID(helpModuleSelect, helpMenuArgs{userID})
+	if err != nil {
+		return nil, err
+	}
+
 	return &discordgo.InteractionResponseData{
 		Embeds: []*discordgo.MessageEmbed{
 			builders.NewEmbedBuilder().
@@ -413,21 +487,21 @@ func createHelpMenu(m *module, sess discord.DiscordSession, userID string) *disc
 		},
 		Components: []discordgo.MessageComponent{
 			builders.NewActionRowBuilder().
-				AddSelectMenu("Select a module", options, utils.JoinStrings(":", helpModuleSelect, userID)).
+				AddSelectMenu("Select a module", options, selectID).
 				Build(),
 		},
-	}
+	}, nil
 }
 
 // createHelpModuleMenu creates a help menu with a select menu for selecting a command from a module.
 // It also includes a back button to go back to the module selection menu.
-func createHelpModuleMenu(m bot.Module, sess discord.DiscordSession, userID string) *discordgo.InteractionResponseData {
+func createHelpModuleMenu(m *module, mod bot.Module, sess discord.DiscordSession, userID string) (*discordgo.InteractionResponseData, error) {
 	var (
 		options  = []discordgo.SelectMenuOption{}
 		cmdNames = []string{}
 	)
 
-	for _, cmd := range m.Commands() {
+	for _, cmd := range mod.Commands() {
 		options = append(options, discordgo.SelectMenuOption{
 			Label: cmd.Name,
 			Value: cmd.Name,
@@ -435,7 +509,11 @@ func createHelpModuleMenu(m bot.Module, sess discord.DiscordSession, userID stri
 		cmdNames = append(cmdNames, fmt.Sprintf("`%v`", cmd.Name))
 	}
 
-	for _, cmd := range m.ApplicationCommands() {
+	for _, cmd := range mod.ApplicationCommands() {
+		// hybrid commands are already listed as text commands
+		if _, ok := mod.Commands()[cmd.Name]; ok {
+			continue
+		}
 		options = append(options, discordgo.SelectMenuOption{
 			Label: "/" + cmd.Name,
 			Value: "/" + cmd.Name,
@@ -443,10 +521,19 @@ func createHelpModuleMenu(m bot.Module, sess discord.DiscordSession, userID stri
 		cmdNames = append(cmdNames, fmt.Sprintf("`/%v`", cmd.Name))
 	}
 
+	selectID, err := m.ComponentCustomID(helpCommandSelect, helpMenuArgs{userID})
+	if err != nil {
+		return nil, err
+	}
+	backID, err := m.ComponentCustomID(helpModuleBack, helpMenuArgs{userID})
+	if err != nil {
+		return nil, err
+	}
+
 	return &discordgo.InteractionResponseData{
 		Embeds: []*discordgo.MessageEmbed{
 			builders.NewEmbedBuilder().
-				WithTitle(fmt.Sprintf("Meido Help - %v", m.Name())).
+				WithTitle(fmt.Sprintf("Meido Help - %v", mod.Name())).
 				WithDescription(fmt.Sprintf("Select a command to see its info.\n\nCommands: %v", strings.Join(cmdNames, ", "))).
 				WithThumbnail(sess.State().User.AvatarURL("256")).
 				WithOkColor().
@@ -454,13 +541,24 @@ func createHelpModuleMenu(m bot.Module, sess discord.DiscordSession, userID stri
 		},
 		Components: []discordgo.MessageComponent{
 			builders.NewActionRowBuilder().
-				AddSelectMenu("Select a command", options, utils.JoinStrings(":", helpCommandSelect, userID)).
+				AddSelectMenu("Select a command", options, selectID).
 				Build(),
 			builders.NewActionRowBuilder().
-				AddButton("Back", discordgo.DangerButton, utils.JoinStrings(":", helpModuleBack, userID)).
+				AddButton("Back", discordgo.DangerButton, backID).
 				Build(),
 		},
+	}, nil
+}
+
+// helpMenuUser returns whether the help menu component was used by the user it
+// was created for.
+func helpMenuUser(dmc *discord.DiscordMessageComponent, args *bot.ComponentArgs) bool {
+	var payload helpMenuArgs
+	if err := args.Decode(&payload); err != nil || payload.UserID != dmc.AuthorID() {
+		_ = dmc.RespondEmpty()
+		return false
 	}
+	return true
 }
 
 func newHelpModuleSelectHandler(m *module) *bot.ModuleMessageComponent {
@@ -473,10 +571,9 @@ func newHelpModuleSelectHandler(m *module) *bot.ModuleMessageComponent {
 		UserType:      bot.UserTypeAny,
 		CheckBotPerms: false,
 		Enabled:       true,
-		Execute: func(dmc *discord.DiscordMessageComponent) {
-			parts := strings.Split(dmc.Data.CustomID, ":")
-			if len(parts) < 2 || parts[1] != dmc.AuthorID() {
-				dmc.RespondEmpty()
+		Command:       "help",
+		ExecuteArgs: func(dmc *discord.DiscordMessageComponent, args *bot.ComponentArgs) {
+			if !helpMenuUser(dmc, args) {
 				return
 			}
 
@@ -494,7 +591,11 @@ func newHelpModuleSelectHandler(m *module) *bot.ModuleMessageComponent {
 				return
 			}
 
-			menu := createHelpModuleMenu(mod, dmc.Sess, dmc.AuthorID())
+			menu, err := createHelpModuleMenu(m, mod, dmc.Sess, dmc.AuthorID())
+			if err != nil {
+				_ = dmc.RespondEphemeral("There was an issue, please try again!")
+				return
+			}
 			_ = dmc.RespondComplex(menu, discordgo.InteractionResponseUpdateMessage)
 		},
 	}
@@ -510,14 +611,17 @@ func newHelpModuleBackHandler(m *module) *bot.ModuleMessageComponent {
 		UserType:      bot.UserTypeAny,
 		CheckBotPerms: false,
 		Enabled:       true,
-		Execute: func(dmc *discord.DiscordMessageComponent) {
-			parts := strings.Split(dmc.Data.CustomID, ":")
-			if len(parts) < 2 || parts[1] != dmc.AuthorID() {
-				dmc.RespondEmpty()
+		Command:       "help",
+		ExecuteArgs: func(dmc *discord.DiscordMessageComponent, args *bot.ComponentArgs) {
+			if !helpMenuUser(dmc, args) {
 				return
 			}
 
-			menu := createHelpMenu(m, dmc.Sess, dmc.AuthorID())
+			menu, err := createHelpMenu(m, dmc.Sess, dmc.AuthorID())
+			if err != nil {
+				_ = dmc.RespondEphemeral("There was an issue, please try again!")
+				return
+			}
 			_ = dmc.RespondComplex(menu, discordgo.InteractionResponseUpdateMessage)
 		},
 	}
@@ -533,10 +637,9 @@ func newHelpCommandSelectHandler(m *module) *bot.ModuleMessageComponent {
 		UserType:      bot.UserTypeAny,
 		CheckBotPerms: false,
 		Enabled:       true,
-		Execute: func(dmc *discord.DiscordMessageComponent) {
-			parts := strings.Split(dmc.Data.CustomID, ":")
-			if len(parts) < 2 || parts[1] != dmc.AuthorID() {
-				dmc.RespondEmpty()
+		Command:       "help",
+		ExecuteArgs: func(dmc *discord.DiscordMessageComponent, args *bot.ComponentArgs) {
+			if !helpMenuUser(dmc, args) {
 				return
 			}
 
@@ -560,15 +663,20 @@ func newHelpCommandSelectHandler(m *module) *bot.ModuleMessageComponent {
 					dmc.UpdateRespose("You are using an old version of the help menu, please try again.")
 					return
 				}
-				embed = getCommandEmbed(cmd, dmc.Sess.State().User.AvatarURL("256"))
+				embed = getCommandEmbed(cmd, m.Bot.Prefixes.Prefix(dmc.GuildID()), dmc.Sess.State().User.AvatarURL("256"))
 				mod = cmd.Mod
 			}
 
+			backID, err := m.ComponentCustomID(helpCommandBack, helpCommandBackArgs{mod.Name(), dmc.AuthorID()})
+			if err != nil {
+				_ = dmc.RespondEphemeral("There was an issue, please try again!")
+				return
+			}
 			resp := &discordgo.InteractionResponseData{
 				Embeds: []*discordgo.MessageEmbed{embed},
 				Components: []discordgo.MessageComponent{
 					builders.NewActionRowBuilder().
-						AddButton("Back", discordgo.DangerButton, utils.JoinStrings(":", helpCommandBack, mod.Name(), dmc.AuthorID())).
+						AddButton("Back", discordgo.DangerButton, backID).
 						Build(),
 				},
 			}
@@ -587,34 +695,34 @@ func newHelpCommandBackHandler(m *module) *bot.ModuleMessageComponent {
 		UserType:      bot.UserTypeAny,
 		CheckBotPerms: false,
 		Enabled:       true,
-		Execute: func(dmc *discord.DiscordMessageComponent) {
-			parts := strings.Split(dmc.Data.CustomID, ":")
-			if len(parts) < 3 || parts[2] != dmc.AuthorID() {
-				dmc.RespondEmpty()
+		Command:       "help",
+		ExecuteArgs: func(dmc *discord.DiscordMessageComponent, args *bot.ComponentArgs) {
+			var payload helpCommandBackArgs
+			if err := args.Decode(&payload); err != nil || payload.UserID != dmc.AuthorID() {
+				_ = dmc.RespondEmpty()
 				return
 			}
 
-			mod, err := m.Bot.FindModule(parts[1])
+			mod, err := m.Bot.FindModule(payload.Module)
 			if err != nil {
 				dmc.UpdateRespose("You are using an old version of the help menu, please try again.")
 				return
 			}
 
-			menu := createHelpModuleMenu(mod, dmc.Sess, dmc.AuthorID())
+			menu, err := createHelpModuleMenu(m, mod, dmc.Sess, dmc.AuthorID())
+			if err != nil {
+				_ = dmc.RespondEphemeral("There was an issue, please try again!")
+				return
+			}
 			_ = dmc.RespondComplex(menu, discordgo.InteractionResponseUpdateMessage)
 		},
 	}
 }
 
-func showCommandHelp(_ *module, d *discord.DiscordApplicationCommand, cmd *bot.ModuleCommand) {
-	embed := getCommandEmbed(cmd, d.Sess.State().User.AvatarURL("256"))
-	_ = d.RespondEmbed(embed)
-}
-
-func getCommandEmbed(cmd *bot.ModuleCommand, avatarUrl string) *discordgo.MessageEmbed {
+func getCommandEmbed(cmd *bot.ModuleCommand, prefix, avatarUrl string) *discordgo.MessageEmbed {
 	text := strings.Builder{}
 	text.WriteString(fmt.Sprintf("%v\n", cmd.Description))
-	text.WriteString(fmt.Sprintf("\n**Usage**: %v", cmd.Usage))
+	text.WriteString(fmt.Sprintf("\n**Usage**: %v", bot.FormatUsage(cmd.Usage, prefix)))
 	text.WriteString(fmt.Sprintf("\n**Aliases**: %v", strings.Join(cmd.Triggers, ", ")))
 	if cmd.Cooldown > 0 {
 		text.WriteString(fmt.Sprintf("\n**Cooldown**: %v", cmd.Cooldown))
@@ -668,99 +776,70 @@ func getApplicationCommandEmbed(cmd *bot.ModuleApplicationCommand, avatarUrl str
 	return embed
 }
 
-func newHelpCommand(m *module) *bot.ModuleCommand {
-	return &bot.ModuleCommand{
+func newHelpCommand(m *module) *bot.ModuleHybridCommand {
+	return &bot.ModuleHybridCommand{
 		Mod:              m,
 		Name:             "help",
 		Description:      "Displays helpful things",
-		Triggers:         []string{"m?help", "m?h"},
-		Usage:            "m?help <module | command | passive>",
+		Triggers:         []string{"help", "h"},
+		Usage:            "help <module | command | passive>",
 		Cooldown:         time.Second * 1,
 		CooldownScope:    bot.CooldownScopeChannel,
 		RequiredPerms:    0,
 		CheckBotPerms:    false,
 		RequiresUserType: bot.UserTypeAny,
-		AllowedTypes:     discord.MessageTypeCreate,
 		AllowDMs:         true,
 		Enabled:          true,
-		Execute:          m.helpCommand,
+		Arguments: []*bot.CommandArgument{
+			{Name: "query", Description: "The module, command or passive to show help for", Type: bot.ArgumentRest, Autocomplete: m.helpAutocomplete},
+		},
+		Execute: m.helpCommand,
 	}
 }
 
-func (m *module) helpCommand(msg *discord.DiscordMessage) {
-	embed := builders.NewEmbedBuilder().
-		WithOkColor().
-		WithFooter("Use m?help [module] to see module commands.\nUse m?help [command] to see command info.\nArguments in [square brackets] are required, while arguments in <angle brackets> are optional.", "").
-		WithThumbnail(msg.Sess.State().User.AvatarURL("256"))
+// helpAutocomplete suggests the names of registered commands.
+func (m *module) helpAutocomplete(it *discord.DiscordAutocomplete) {
+	_ = it.RespondStringChoices(m.Bot.CommandNames(it.Query())...)
+}
 
-	if len(msg.Args()) == 1 {
-		desc := strings.Builder{}
-		for _, mod := range m.Bot.Modules {
-			desc.WriteString(fmt.Sprintf("- %v\n", mod.Name()))
-		}
-		embed.WithTitle("Modules")
-		embed.WithDescription(desc.String())
-		_, _ = msg.ReplyEmbed(embed.Build())
-		return
-	}
+func (m *module) helpCommand(ctx bot.CommandContext) {
+	sess := ctx.Discord().Sess
 
-	// if only m?help
-	if len(msg.Args()) < 2 {
+	// if no query is provided, show the help menu
+	if !ctx.Args().Has("query") {
+		menu, err := createHelpMenu(m, sess, ctx.AuthorID())
+		if err != nil {
+			_ = ctx.Reply("There was an issue, please try again!")
+			return
+		}
+		_ = ctx.ReplyComplex(&discordgo.MessageSend{Embeds: menu.Embeds, Components: menu.Components})
 		return
 	}
 
-	inp := strings.Join(msg.Args()[1:], "")
+	inp := ctx.Args().String("query")
 	if mod, err := m.Bot.FindModule(inp); err == nil {
-		// this can maybe be replaced by making a helptext method for every mod, so they have more control
-		// over what they want to display, if they even want to display anything.
-		list := strings.Builder{}
-		if len(mod.Passives()) > 0 {
-			list.WriteString("\nPassives:\n")
-			for _, pas := range mod.Passives() {
-				list.WriteString(fmt.Sprintf("- `%v`\n", pas.Name))
-			}
-		}
-
-		if len(mod.Commands()) > 0 {
-			list.WriteString("\nCommands:\n")
-			for _, cmd := range mod.Commands() {
-				list.WriteString(fmt.Sprintf("- `%v`\n", cmd.Name))
-			}
-		}
-
-		if !mod.AllowDMs() {
-			list.WriteString("\nCannot be used in DMs")
+		menu, err := createHelpModuleMenu(m, mod, sess, ctx.AuthorID())
+		if err != nil {
+			_ = ctx.Reply("There was an issue, please try again!")
+			return
 		}
-
-		embed.WithTitle(fmt.Sprintf("%v module", mod.Name()))
-		embed.WithDescription(list.String())
-		_, _ = msg.ReplyEmbed(embed.Build())
+		_ = ctx.ReplyComplex(&discordgo.MessageSend{Embeds: menu.Embeds, Components: menu.Components})
 		return
 	}
 
 	if pas, err := m.Bot.FindPassive(inp); err == nil {
-		embed.WithTitle(fmt.Sprintf("Passive - %v", pas.Name))
-		embed.WithDescription(fmt.Sprintf("%v\n", pas.Description))
-		_, _ = msg.ReplyEmbed(embed.Build())
+		embed := builders.NewEmbedBuilder().
+			WithTitle(fmt.Sprintf("Passive - %v", pas.Name)).
+			WithDescription(fmt.Sprintf("%v\n", pas.Description)).
+			WithOkColor().
+			WithThumbnail(sess.State().User.AvatarURL("256"))
+		_ = ctx.ReplyEmbed(embed.Build())
 		return
 	}
 
 	if cmd, err := m.Bot.FindCommand(inp); err == nil {
-		info := strings.Builder{}
-		info.WriteString(fmt.Sprintf("%v\n", cmd.Description))
-		info.WriteString(fmt.Sprintf("\n**Usage**: %v", cmd.Usage))
-		info.WriteString(fmt.Sprintf("\n**Aliases**: %v", strings.Join(cmd.Triggers, ", ")))
-		if cmd.Cooldown > 0 {
-			info.WriteString(fmt.Sprintf("\n**Cooldown**: %v second(s)", cmd.Cooldown))
-		}
-		info.WriteString(fmt.Sprintf("\n**Required permissions**: %v", discord.PermMap[cmd.RequiredPerms]))
-		if !cmd.AllowDMs {
-			info.WriteString(fmt.Sprintf("\n%v", "Cannot be used in DMs"))
-		}
-
-		embed.WithTitle(fmt.Sprintf("Command - %v", cmd.Name))
-		embed.WithDescription(info.String())
-		_, _ = msg.ReplyEmbed(embed.Build())
+		_ = ctx.ReplyEmbed(getCommandEmbed(cmd, m.Bot.Prefixes.Prefix(ctx.GuildID()), sess.State().User.AvatarURL("256")))
 		return
 	}
+	_ = ctx.Reply("I could not find a module, command or passive with that name")
 }
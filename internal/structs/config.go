@@ -23,6 +23,21 @@ type jsonConfig struct {
 	YouTubeToken     string   `json:"youtube_key"`
 	OpenWeatherKey   string   `json:"open_weather_api_key"`
 	ExcludedModules  []string `json:"excluded_modules"`
+	Prefix           string   `json:"prefix"`
+	// CustomIDSecret signs the custom IDs of message components.
+	CustomIDSecret string `json:"custom_id_secret"`
+	// ShutdownGracePeriod is in seconds.
+	ShutdownGracePeriod int `json:"shutdown_grace_period"`
+	// DevGuildIDs are where development commands are registered.
+	DevGuildIDs []string `json:"dev_guild_ids"`
+	// CommandSyncDryRun logs application command changes instead of making them.
+	CommandSyncDryRun bool `json:"command_sync_dry_run"`
+	// EventExportFile is a JSON lines file bot events are exported to.
+	EventExportFile string `json:"event_export_file"`
+	// EventExportWebhook is a URL bot events are exported to, with
+	// EventExportWebhookToken as its bearer token.
+	EventExportWebhook      string `json:"event_export_webhook"`
+	EventExportWebhookToken string `json:"event_export_webhook_token"`
 }
 
 func LoadConfig(cfg *utils.Config) error {
@@ -55,6 +70,14 @@ func loadJson(cfg *utils.Config) error {
 	cfg.Set("youtube_token", jsonCfg.YouTubeToken)
 	cfg.Set("open_weather_key", jsonCfg.OpenWeatherKey)
 	cfg.Set("excluded_modules", jsonCfg.ExcludedModules)
+	cfg.Set("prefix", jsonCfg.Prefix)
+	cfg.Set("custom_id_secret", jsonCfg.CustomIDSecret)
+	cfg.Set("shutdown_grace_period", jsonCfg.ShutdownGracePeriod)
+	cfg.Set("dev_guild_ids", jsonCfg.DevGuildIDs)
+	cfg.Set("command_sync_dry_run", jsonCfg.CommandSyncDryRun)
+	cfg.Set("event_export_file", jsonCfg.EventExportFile)
+	cfg.Set("event_export_webhook", jsonCfg.EventExportWebhook)
+	cfg.Set("event_export_webhook_token", jsonCfg.EventExportWebhookToken)
 	return nil
 }
 
@@ -63,6 +86,14 @@ func loadEnvs(cfg *utils.Config) {
 		cfg.Set("token", os.Getenv("DISCORD_TOKEN"))
 	}
 
+	if e := os.Getenv("CUSTOM_ID_SECRET"); e != "" {
+		cfg.Set("custom_id_secret", e)
+	}
+
+	if e := os.Getenv("EVENT_EXPORT_WEBHOOK_TOKEN"); e != "" {
+		cfg.Set("event_export_webhook_token", e)
+	}
+
 	dbHost := os.Getenv("DB_HOST")
 	dbPort := os.Getenv("DB_PORT")
 	dbName := os.Getenv("DB_NAME")
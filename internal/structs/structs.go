@@ -26,4 +26,45 @@ type Guild struct {
 	AutomodLogChannelID string `db:"automod_log_channel_id"`
 	FishingChannelID    string `db:"fishing_channel_id"`
 	AutoRoleID          string `db:"auto_role_id"`
+
+	// empty means the bot default prefix is used
+	Prefix string `db:"prefix"`
+}
+
+// GuildToggle represents a module, command or passive that is disabled in a guild.
+type GuildToggle struct {
+	GuildID string `db:"guild_id"`
+	Kind    string `db:"kind"`
+	Name    string `db:"name"`
+}
+
+// CommandPermission represents a rule that allows or denies a role, channel or
+// user the use of a command in a guild.
+type CommandPermission struct {
+	GuildID  string `db:"guild_id"`
+	Command  string `db:"command"`
+	Target   string `db:"target"`
+	TargetID string `db:"target_id"`
+	Allow    bool   `db:"allow"`
+}
+
+// Cooldown represents the state of a cooldown, encoded as JSON.
+type Cooldown struct {
+	Key       string    `db:"cooldown_key"`
+	State     []byte    `db:"state"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+// ScheduledJob represents a one-shot job of the scheduler, with its payload
+// encoded as JSON.
+type ScheduledJob struct {
+	UID          int64      `db:"uid"`
+	Kind         string     `db:"kind"`
+	Payload      []byte     `db:"payload"`
+	RunAt        time.Time  `db:"run_at"`
+	Attempts     int        `db:"attempts"`
+	LastError    string     `db:"last_error"`
+	Failed       bool       `db:"failed"`
+	ClaimedBy    string     `db:"claimed_by"`
+	ClaimedUntil *time.Time `db:"claimed_until"`
 }
@@ -0,0 +1,315 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/intrntsrfr/meido/pkg/utils"
+)
+
+// ArgumentType decides how a CommandArgument is parsed.
+type ArgumentType int
+
+const (
+	ArgumentString ArgumentType = iota
+	ArgumentInt
+	ArgumentDuration
+	ArgumentEnum
+	ArgumentMember
+	ArgumentUser
+	ArgumentRole
+	ArgumentChannel
+	// ArgumentRest consumes the rest of the message, and must be the last argument.
+	ArgumentRest
+)
+
+// CommandArgument describes a single positional argument of a ModuleCommand.
+type CommandArgument struct {
+	Name string
+	// Description is shown for the option when the argument belongs to a hybrid command.
+	Description string
+	Type        ArgumentType
+	Required    bool
+
+	// Min and Max bound ArgumentInt values, when HasMin and HasMax are set.
+	Min    int
+	Max    int
+	HasMin bool
+	HasMax bool
+
+	// MinDuration and MaxDuration bound ArgumentDuration values. A bound of 0 is ignored.
+	MinDuration time.Duration
+	MaxDuration time.Duration
+
+	// Choices are the allowed values of an ArgumentEnum. They are matched case-insensitively.
+	Choices []string
+
+	// Autocomplete suggests values for the option when the argument belongs to a
+	// hybrid command. It is not used for ArgumentEnum, which has its choices listed.
+	Autocomplete AutocompleteHandler
+}
+
+// usage returns the argument as it is shown in usage texts. Required arguments
+// are wrapped in [square brackets], while optional ones are in <angle brackets>.
+func (a *CommandArgument) usage() string {
+	name := a.Name
+	if a.Type == ArgumentEnum && len(a.Choices) > 0 {
+		name = strings.Join(a.Choices, " / ")
+	}
+	if a.Type == ArgumentRest {
+		name += "..."
+	}
+	if a.Required {
+		return "[" + name + "]"
+	}
+	return "<" + name + ">"
+}
+
+// ArgumentError is returned when a command argument is missing or invalid.
+type ArgumentError struct {
+	Arg    *CommandArgument
+	Value  string
+	Reason string
+}
+
+func (e *ArgumentError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("Missing argument `%v`", e.Arg.Name)
+	}
+	return fmt.Sprintf("Invalid argument `%v`: %v", e.Arg.Name, e.Reason)
+}
+
+// CommandArgs holds the parsed arguments of a command invocation. Getters return
+// the zero value if an optional argument was not provided.
+type CommandArgs struct {
+	values map[string]interface{}
+}
+
+func newCommandArgs() *CommandArgs {
+	return &CommandArgs{values: make(map[string]interface{})}
+}
+
+// Has returns whether an argument was provided.
+func (a *CommandArgs) Has(name string) bool {
+	_, ok := a.values[name]
+	return ok
+}
+
+// String returns the value of an ArgumentString, ArgumentEnum or ArgumentRest argument.
+func (a *CommandArgs) String(name string) string {
+	s, _ := a.values[name].(string)
+	return s
+}
+
+func (a *CommandArgs) Int(name string) int {
+	n, _ := a.values[name].(int)
+	return n
+}
+
+func (a *CommandArgs) Duration(name string) time.Duration {
+	d, _ := a.values[name].(time.Duration)
+	return d
+}
+
+func (a *CommandArgs) Member(name string) *discordgo.Member {
+	m, _ := a.values[name].(*discordgo.Member)
+	return m
+}
+
+func (a *CommandArgs) User(name string) *discordgo.User {
+	u, _ := a.values[name].(*discordgo.User)
+	return u
+}
+
+func (a *CommandArgs) Role(name string) *discordgo.Role {
+	r, _ := a.values[name].(*discordgo.Role)
+	return r
+}
+
+func (a *CommandArgs) Channel(name string) *discordgo.Channel {
+	c, _ := a.values[name].(*discordgo.Channel)
+	return c
+}
+
+// ArgumentUsage generates a prefix-less usage text from the argument spec of the command.
+func (cmd *ModuleCommand) ArgumentUsage() string {
+	var parts []string
+	if len(cmd.Triggers) > 0 {
+		parts = append(parts, cmd.Triggers[0])
+	}
+	for _, arg := range cmd.Arguments {
+		parts = append(parts, arg.usage())
+	}
+	return strings.Join(parts, " ")
+}
+
+// triggerLength returns the amount of words the trigger matching content consists of.
+func (cmd *ModuleCommand) triggerLength(content string) int {
+	fields := strings.Fields(content)
+	longest := 0
+	for _, trig := range cmd.Triggers {
+		splitTrig := strings.Fields(trig)
+		if len(fields) < len(splitTrig) || len(splitTrig) <= longest {
+			continue
+		}
+		if strings.EqualFold(strings.Join(fields[:len(splitTrig)], " "), trig) {
+			longest = len(splitTrig)
+		}
+	}
+	return longest
+}
+
+// parseArguments parses the arguments of msg according to the argument spec
+// of the command.
+func (cmd *ModuleCommand) parseArguments(msg *discord.DiscordMessage) (*CommandArgs, error) {
+	args := newCommandArgs()
+	if len(cmd.Arguments) == 0 {
+		return args, nil
+	}
+
+	content := msg.CommandContent()
+	skip := cmd.triggerLength(content)
+	fields := strings.Fields(content)[skip:]
+	for i, arg := range cmd.Arguments {
+		if arg.Type == ArgumentRest {
+			// the rest is cut from the content, so newlines, spacing and code
+			// blocks are kept as they were written
+			rest := strings.TrimSpace(content[fieldOffset(content, skip+i):])
+			if rest == "" {
+				if arg.Required {
+					return nil, &ArgumentError{Arg: arg}
+				}
+				break
+			}
+			args.values[arg.Name] = rest
+			break
+		}
+
+		if i >= len(fields) {
+			if arg.Required {
+				return nil, &ArgumentError{Arg: arg}
+			}
+			break
+		}
+
+		v, err := parseArgument(arg, fields[i], msg)
+		if err != nil {
+			return nil, &ArgumentError{Arg: arg, Value: fields[i], Reason: err.Error()}
+		}
+		args.values[arg.Name] = v
+	}
+	return args, nil
+}
+
+func parseArgument(arg *CommandArgument, value string, msg *discord.DiscordMessage) (interface{}, error) {
+	switch arg.Type {
+	case ArgumentMember:
+		userID := utils.TrimUserID(value)
+		if !utils.IsNumber(userID) {
+			return nil, errors.New("not a user mention or ID")
+		}
+		member, err := msg.Discord.Member(msg.GuildID(), userID)
+		if err != nil {
+			return nil, errors.New("member not found")
+		}
+		return member, nil
+	case ArgumentUser:
+		userID := utils.TrimUserID(value)
+		if !utils.IsNumber(userID) {
+			return nil, errors.New("not a user mention or ID")
+		}
+		if member, err := msg.Discord.Member(msg.GuildID(), userID); err == nil {
+			return member.User, nil
+		}
+		user, err := msg.Sess.User(userID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		return user, nil
+	case ArgumentRole:
+		role, err := msg.Discord.GuildRoleByNameOrID(msg.GuildID(), value, utils.TrimRoleID(value))
+		if err != nil {
+			return nil, errors.New("role not found")
+		}
+		return role, nil
+	case ArgumentChannel:
+		channelID := utils.TrimChannelID(value)
+		if !utils.IsNumber(channelID) {
+			return nil, errors.New("not a channel mention or ID")
+		}
+		channel, err := msg.Discord.Channel(channelID)
+		if err != nil || channel.GuildID != msg.GuildID() {
+			return nil, errors.New("channel not found")
+		}
+		return channel, nil
+	}
+	return parseValue(arg, value)
+}
+
+// parseValue parses arguments that do not need to be looked up through Discord.
+func parseValue(arg *CommandArgument, value string) (interface{}, error) {
+	switch arg.Type {
+	case ArgumentString, ArgumentRest:
+		return value, nil
+	case ArgumentInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.New("not a number")
+		}
+		switch {
+		case arg.HasMin && arg.HasMax && (n < arg.Min || n > arg.Max):
+			return nil, fmt.Errorf("must be between %v and %v", arg.Min, arg.Max)
+		case arg.HasMin && n < arg.Min:
+			return nil, fmt.Errorf("must be at least %v", arg.Min)
+		case arg.HasMax && n > arg.Max:
+			return nil, fmt.Errorf("must be at most %v", arg.Max)
+		}
+		return n, nil
+	case ArgumentDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, errors.New("not a duration, example: 1h30m")
+		}
+		if arg.MinDuration != 0 && d < arg.MinDuration {
+			return nil, fmt.Errorf("must be at least %v", arg.MinDuration)
+		}
+		if arg.MaxDuration != 0 && d > arg.MaxDuration {
+			return nil, fmt.Errorf("must be at most %v", arg.MaxDuration)
+		}
+		return d, nil
+	case ArgumentEnum:
+		for _, choice := range arg.Choices {
+			if strings.EqualFold(choice, value) {
+				return choice, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %v", strings.Join(arg.Choices, ", "))
+	}
+	return nil, errors.New("unknown argument type")
+}
+
+// fieldOffset returns the byte offset in s where field n starts, with fields split
+// like strings.Fields does. It returns len(s) if s has n fields or fewer.
+func fieldOffset(s string, n int) int {
+	inField := false
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			inField = false
+			continue
+		}
+		if !inField {
+			if n == 0 {
+				return i
+			}
+			n--
+			inField = true
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio"
+)
+
+func newTestArgsCommand(args ...*CommandArgument) *ModuleCommand {
+	return &ModuleCommand{
+		Name:      "test",
+		Triggers:  []string{"test", "settings test"},
+		Arguments: args,
+	}
+}
+
+func TestModuleCommand_ArgumentUsage(t *testing.T) {
+	cmd := newTestArgsCommand(
+		&CommandArgument{Name: "user", Type: ArgumentMember, Required: true},
+		&CommandArgument{Name: "mode", Type: ArgumentEnum, Choices: []string{"on", "off"}},
+		&CommandArgument{Name: "reason", Type: ArgumentRest},
+	)
+	want := "test [user] <on / off> <reason...>"
+	if got := cmd.ArgumentUsage(); got != want {
+		t.Errorf("ModuleCommand.ArgumentUsage() = %v, want %v", got, want)
+	}
+}
+
+func TestModuleCommand_parseArguments(t *testing.T) {
+	bot := NewTestBot()
+	cmd := newTestArgsCommand(
+		&CommandArgument{Name: "amount", Type: ArgumentInt, Required: true, Min: 1, Max: 10, HasMin: true, HasMax: true},
+		&CommandArgument{Name: "duration", Type: ArgumentDuration, MinDuration: time.Minute},
+		&CommandArgument{Name: "mode", Type: ArgumentEnum, Choices: []string{"on", "off"}},
+		&CommandArgument{Name: "reason", Type: ArgumentRest},
+	)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+		check   func(*CommandArgs) bool
+	}{
+		{"all", "m?test 5 1h ON some reason", false, func(a *CommandArgs) bool {
+			return a.Int("amount") == 5 && a.Duration("duration") == time.Hour &&
+				a.String("mode") == "on" && a.String("reason") == "some reason"
+		}},
+		{"multi word trigger", "m?settings test 5", false, func(a *CommandArgs) bool {
+			return a.Int("amount") == 5 && !a.Has("duration") && !a.Has("reason")
+		}},
+		{"rest keeps formatting", "m?test 5 1h on  line one\n\n```\nx  :=  1\n```", false, func(a *CommandArgs) bool {
+			return a.String("reason") == "line one\n\n```\nx  :=  1\n```"
+		}},
+		{"missing required", "m?test", true, nil},
+		{"int out of range", "m?test 11", true, nil},
+		{"not an int", "m?test abc", true, nil},
+		{"duration too short", "m?test 5 10s", true, nil},
+		{"invalid enum", "m?test 5 1h maybe", true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := NewTestMessage(bot, "1")
+			msg.Message.Content = tt.content
+			args, err := cmd.parseArguments(msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ModuleCommand.parseArguments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var argErr *ArgumentError
+				if !errors.As(err, &argErr) {
+					t.Errorf("ModuleCommand.parseArguments() error is not an ArgumentError")
+				}
+				return
+			}
+			if !tt.check(args) {
+				t.Errorf("ModuleCommand.parseArguments() parsed wrong values: %v", args.values)
+			}
+		})
+	}
+}
+
+func TestModuleCommand_parseArguments_Discord(t *testing.T) {
+	bot := NewTestBot()
+	state := bot.Discord.Sess.State()
+	_ = state.GuildAdd(&discordgo.Guild{
+		ID:       "1",
+		Roles:    []*discordgo.Role{{ID: "10", Name: "mods"}},
+		Channels: []*discordgo.Channel{{ID: "20", GuildID: "1"}},
+		Members:  []*discordgo.Member{{GuildID: "1", User: &discordgo.User{ID: "30"}}},
+	})
+
+	cmd := newTestArgsCommand(
+		&CommandArgument{Name: "member", Type: ArgumentMember, Required: true},
+		&CommandArgument{Name: "user", Type: ArgumentUser, Required: true},
+		&CommandArgument{Name: "role", Type: ArgumentRole, Required: true},
+		&CommandArgument{Name: "channel", Type: ArgumentChannel, Required: true},
+	)
+	msg := NewTestMessage(bot, "1")
+	msg.Message.Content = "m?test <@30> 30 <@&10> <#20>"
+	args, err := cmd.parseArguments(msg)
+	if err != nil {
+		t.Fatalf("ModuleCommand.parseArguments() error = %v", err)
+	}
+	if args.Member("member").User.ID != "30" || args.User("user").ID != "30" ||
+		args.Role("role").ID != "10" || args.Channel("channel").ID != "20" {
+		t.Errorf("ModuleCommand.parseArguments() parsed wrong values: %v", args.values)
+	}
+
+	msg.Message.Content = "m?test <@30> 30 mods <#21>"
+	if _, err := cmd.parseArguments(msg); err == nil {
+		t.Errorf("ModuleCommand.parseArguments() did not error on unknown channel")
+	}
+}
+
+func TestModuleBase_RegisterCommands_ArgumentUsage(t *testing.T) {
+	base := NewModule(nil, "testing", mio.NewDiscardLogger())
+	cmd := newTestArgsCommand(&CommandArgument{Name: "amount", Type: ArgumentInt, Required: true})
+	_ = base.RegisterCommands(cmd)
+	if want := "test [amount]"; cmd.Usage != want {
+		t.Errorf("ModuleBase.RegisterCommands() usage = %v, want %v", cmd.Usage, want)
+	}
+}
+
+func Test_parseValue_IntBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     *CommandArgument
+		value   string
+		wantErr bool
+	}{
+		{"no bounds", &CommandArgument{Type: ArgumentInt}, "-5", false},
+		{"min only, above", &CommandArgument{Type: ArgumentInt, Min: 1, HasMin: true}, "500", false},
+		{"min only, below", &CommandArgument{Type: ArgumentInt, Min: 1, HasMin: true}, "0", true},
+		{"max only, below", &CommandArgument{Type: ArgumentInt, Max: 10, HasMax: true}, "-3", false},
+		{"max only, above", &CommandArgument{Type: ArgumentInt, Max: 10, HasMax: true}, "11", true},
+		{"zero max", &CommandArgument{Type: ArgumentInt, Min: -10, Max: 0, HasMin: true, HasMax: true}, "1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseValue(tt.arg, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package bot
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/intrntsrfr/meido/pkg/mio"
@@ -19,45 +20,162 @@ type Bot struct {
 	*ModuleManager
 	EventHandler *EventHandler
 	Callbacks    *mutils.CallbackManager
-	Cooldowns    *mutils.CooldownManager
+	Prompts      *Prompts
+	Cooldowns    CooldownStore
+	Workers      *WorkerPools
+	Prefixes     PrefixResolver
+	Toggles      ToggleStore
+	Permissions  PermissionStore
+	CustomIDs    *CustomIDCodec
+	Scheduler    *Scheduler
 	*mio.EventBus
 
 	Logger mio.Logger
+
+	middleware []Middleware
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	// GracePeriod is how long Close waits for running handlers to finish.
+	GracePeriod time.Duration
+	// CommandSync configures how Run syncs application commands.
+	CommandSync CommandSyncConfig
+	handlersMu  sync.Mutex
+	closing     bool
+	// running is the amount of handlers that have not returned yet, and idle is
+	// closed once it reaches 0.
+	running int
+	idle    chan struct{}
 }
 
+// DefaultGracePeriod is the grace period of bots that do not set one.
+const DefaultGracePeriod = time.Second * 10
+
+// eventFlushTimeout is how long Close waits for pending EventBus handlers.
+const eventFlushTimeout = time.Second * 5
+
 func (b *Bot) Run(ctx context.Context) error {
 	b.Logger.Info("Starting up...")
+	b.Lock()
+	b.ctx, b.cancel = context.WithCancel(ctx)
+	ctx = b.ctx
+	b.Unlock()
 	go b.EventHandler.Listen(ctx)
+	b.Scheduler.Start(ctx)
 	if err := b.Discord.Run(); err != nil {
 		return err
 	}
-	if err := b.setApplicationCommands(); err != nil {
+	if _, err := b.SyncApplicationCommands(); err != nil {
 		return err
 	}
 	b.Logger.Info("Running")
 	return nil
 }
 
+// Context returns the context the bot was started with. Handler contexts are
+// derived from it, so they are cancelled when it is.
+func (b *Bot) Context() context.Context {
+	b.Lock()
+	defer b.Unlock()
+	if b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
+// Close shuts the bot down gracefully. It stops accepting new events, waits up to
+// GracePeriod for running handlers to finish before cancelling them, stops the
+// scheduler and the worker pools, flushes pending EventBus handlers, and then
+// closes the Discord sessions.
 func (b *Bot) Close() {
 	b.Logger.Info("Shutting down")
+	b.handlersMu.Lock()
+	b.closing = true
+	b.handlersMu.Unlock()
+
+	graceCtx, graceCancel := context.WithTimeout(context.Background(), b.GracePeriod)
+	if err := b.waitHandlers(graceCtx); err != nil {
+		b.Logger.Warn("Grace period exceeded, cancelling running handlers", "grace period", b.GracePeriod.String())
+	}
+	graceCancel()
+
+	b.Lock()
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.Unlock()
+	b.Scheduler.Stop()
+	b.Workers.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), eventFlushTimeout)
+	defer cancel()
+	if err := b.EventBus.Wait(ctx); err != nil {
+		b.Logger.Warn("Could not flush events", "error", err)
+	}
 	b.Discord.Close()
+	b.Logger.Info("Shut down")
 }
 
-func (b *Bot) setApplicationCommands() error {
-	var allCommands []*discordgo.ApplicationCommand
-	for _, m := range b.Modules {
-		allCommands = append(allCommands, m.ApplicationCommandStructs()...)
+// startHandler registers a running handler, so Close can wait for it. It returns
+// false if the bot is shutting down, in which case the handler must not run.
+func (b *Bot) startHandler() bool {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+	if b.closing {
+		return false
+	}
+	if b.running == 0 {
+		b.idle = make(chan struct{})
 	}
+	b.running++
+	return true
+}
+
+func (b *Bot) handlerDone() {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+	b.running--
+	if b.running == 0 {
+		close(b.idle)
+	}
+}
 
-	created, err := b.Discord.Sess.ApplicationCommandBulkOverwrite(b.Discord.Sess.State().User.ID, "", allCommands)
-	if err != nil {
-		b.logger.Error("could not overwrite commands", "error", err)
+// waitHandlers blocks until no handlers are running, or until ctx is done.
+func (b *Bot) waitHandlers(ctx context.Context) error {
+	for {
+		b.handlersMu.Lock()
+		if b.running == 0 {
+			b.handlersMu.Unlock()
+			return nil
+		}
+		idle := b.idle
+		b.handlersMu.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// WaitIdle waits until no handlers are running, and the EventBus handlers they
+// started have returned. It is meant for tests, which can then assert on what the
+// handlers did.
+func (b *Bot) WaitIdle(ctx context.Context) error {
+	if err := b.waitHandlers(ctx); err != nil {
 		return err
 	}
-	for _, c := range created {
-		b.logger.Info("Created/updated command", "name", c.Name, "type", uint8(c.Type))
+	return b.EventBus.Wait(ctx)
+}
+
+// dispatch runs a handler on the worker pool of shard. If the queue of the pool is full,
+// the handler runs in the calling goroutine instead, which is a worker itself when
+// the event came through the EventHandler.
+func (b *Bot) dispatch(shard int, task func()) {
+	if !b.Workers.Pool(shard).TrySubmit(task) {
+		task()
 	}
-	return nil
 }
 
 func (b *Bot) IsOwner(userID string) bool {
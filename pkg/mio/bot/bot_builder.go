@@ -1,6 +1,8 @@
 package bot
 
 import (
+	"time"
+
 	"github.com/intrntsrfr/meido/pkg/mio"
 	"github.com/intrntsrfr/meido/pkg/mio/discord"
 	mutils "github.com/intrntsrfr/meido/pkg/mio/utils"
@@ -11,9 +13,20 @@ type BotBuilder struct {
 	discord      *discord.Discord
 	modules      *ModuleManager
 	callbacks    *mutils.CallbackManager
-	cooldowns    *mutils.CooldownManager
+	prompts      *Prompts
+	cooldowns    CooldownStore
+	prefixes     PrefixResolver
+	toggles      ToggleStore
+	permissions  PermissionStore
+	customIDs    *CustomIDCodec
+	jobs         JobStore
 	eventHandler *EventHandler
 	eventBus     *mio.EventBus
+	middleware   []Middleware
+	gracePeriod  time.Duration
+	commandSync  CommandSyncConfig
+	workers      *WorkerPools
+	workerConfig WorkerPoolConfig
 
 	config *utils.Config
 	logger mio.Logger
@@ -38,6 +51,67 @@ func (b *BotBuilder) WithLogger(log mio.Logger) *BotBuilder {
 	return b
 }
 
+func (b *BotBuilder) WithPrefixResolver(p PrefixResolver) *BotBuilder {
+	b.prefixes = p
+	return b
+}
+
+// WithToggleStore sets where the bot keeps track of what is disabled in each guild.
+func (b *BotBuilder) WithToggleStore(t ToggleStore) *BotBuilder {
+	b.toggles = t
+	return b
+}
+
+// WithPermissionStore sets where the bot keeps the permission rules of commands.
+func (b *BotBuilder) WithPermissionStore(p PermissionStore) *BotBuilder {
+	b.permissions = p
+	return b
+}
+
+// WithCustomIDCodec sets how the bot signs the custom IDs of message components.
+// By default, the "custom_id_secret" config value is the secret.
+func (b *BotBuilder) WithCustomIDCodec(c *CustomIDCodec) *BotBuilder {
+	b.customIDs = c
+	return b
+}
+
+// WithCooldownStore sets where the bot keeps track of cooldowns.
+func (b *BotBuilder) WithCooldownStore(c CooldownStore) *BotBuilder {
+	b.cooldowns = c
+	return b
+}
+
+// WithJobStore sets where the scheduler of the bot keeps one-shot jobs.
+func (b *BotBuilder) WithJobStore(j JobStore) *BotBuilder {
+	b.jobs = j
+	return b
+}
+
+// WithMiddleware adds global middleware to the bot. See Bot.Use.
+func (b *BotBuilder) WithMiddleware(mws ...Middleware) *BotBuilder {
+	b.middleware = append(b.middleware, mws...)
+	return b
+}
+
+// WithGracePeriod sets how long the bot waits for running handlers when it closes.
+func (b *BotBuilder) WithGracePeriod(d time.Duration) *BotBuilder {
+	b.gracePeriod = d
+	return b
+}
+
+// WithCommandSync configures how the bot syncs its application commands with
+// Discord when it starts.
+func (b *BotBuilder) WithCommandSync(cfg CommandSyncConfig) *BotBuilder {
+	b.commandSync = cfg
+	return b
+}
+
+// WithWorkerPool configures the worker pools events and handlers run on.
+func (b *BotBuilder) WithWorkerPool(cfg WorkerPoolConfig) *BotBuilder {
+	b.workerConfig = cfg
+	return b
+}
+
 func (b *BotBuilder) WithDefaultHandlers() *BotBuilder {
 	b.useDefaultHandlers = true
 	return b
@@ -53,15 +127,45 @@ func (b *BotBuilder) Build() *Bot {
 	if b.callbacks == nil {
 		b.callbacks = mutils.NewCallbackManager()
 	}
+	if b.prompts == nil {
+		b.prompts = NewPrompts()
+	}
 	if b.cooldowns == nil {
-		b.cooldowns = mutils.NewCooldownManager()
+		b.cooldowns = NewMemoryCooldownStore()
+	}
+	if b.prefixes == nil {
+		b.prefixes = NewMemoryPrefixResolver(b.config.GetString("prefix"))
+	}
+	if b.toggles == nil {
+		b.toggles = NewMemoryToggleStore()
+	}
+	if b.permissions == nil {
+		b.permissions = NewMemoryPermissionStore()
+	}
+	if b.customIDs == nil {
+		if secret := b.config.GetString("custom_id_secret"); secret != "" {
+			b.customIDs = NewCustomIDCodec([]byte(secret))
+		} else {
+			b.logger.Warn("No custom_id_secret set, component custom IDs will not survive restarts")
+			b.customIDs = NewRandomCustomIDCodec()
+		}
+	}
+	if b.jobs == nil {
+		b.jobs = NewMemoryJobStore()
 	}
 	if b.eventBus == nil {
-		b.eventBus = mio.NewEventBus()
+		b.eventBus = mio.NewEventBus(b.logger)
+	}
+	if b.workers == nil {
+		b.workers = NewWorkerPools(b.workerConfig, b.config.GetInt("shards"))
 	}
 	if b.eventHandler == nil {
-		b.eventHandler = NewEventHandler(b.discord, b.modules, b.callbacks, b.eventBus, b.logger)
+		b.eventHandler = NewEventHandler(b.discord, b.modules, b.callbacks, b.prompts, b.prefixes, b.toggles, b.eventBus, b.workers, b.logger)
+	}
+	if b.gracePeriod <= 0 {
+		b.gracePeriod = DefaultGracePeriod
 	}
+	b.discord.AddEventHandler(reactionAddHandler(b.prompts))
 	if b.useDefaultHandlers {
 		b.discord.AddEventHandler(readyHandler(b.logger))
 		b.discord.AddEventHandler(guildJoinHandler(b.logger))
@@ -73,10 +177,20 @@ func (b *BotBuilder) Build() *Bot {
 		Discord:       b.discord,
 		ModuleManager: b.modules,
 		Callbacks:     b.callbacks,
+		Prompts:       b.prompts,
 		Cooldowns:     b.cooldowns,
+		Workers:       b.workers,
+		Prefixes:      b.prefixes,
+		Toggles:       b.toggles,
+		Permissions:   b.permissions,
+		CustomIDs:     b.customIDs,
+		Scheduler:     NewScheduler(b.jobs, b.logger),
 		EventHandler:  b.eventHandler,
 		EventBus:      b.eventBus,
 		Config:        b.config,
 		Logger:        b.logger,
+		middleware:    b.middleware,
+		GracePeriod:   b.gracePeriod,
+		CommandSync:   b.commandSync,
 	}
 }
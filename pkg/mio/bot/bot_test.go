@@ -3,6 +3,7 @@ package bot
 import (
 	"bytes"
 	"context"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -170,7 +171,7 @@ func TestBot_MessageWrongTypeGetsIgnored(t *testing.T) {
 
 		called := make(chan bool)
 		cmd := NewModuleCommandBuilder(mod, "test").
-			Triggers(".test").
+			Triggers("test").
 			AllowedTypes(discord.MessageTypeCreate).
 			Execute(func(dm *discord.DiscordMessage) {
 				called <- true
@@ -275,7 +276,7 @@ func TestBot_MessageGetsCallback(t *testing.T) {
 		MessageType:  discord.MessageTypeCreate,
 		TimeReceived: time.Now(),
 		Message: &discordgo.Message{
-			Content: ".test hello",
+			Content: "m?test hello",
 			GuildID: "1",
 			Author: &discordgo.User{
 				Username: "jeff",
@@ -308,3 +309,102 @@ func TestBot_MessageGetsCallback(t *testing.T) {
 		t.Error("Command callback was not called. Timed out")
 	}
 }
+
+func TestBot_Close(t *testing.T) {
+	t.Run("waits for running handlers", func(t *testing.T) {
+		bot, _, _ := setupTestBot()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		started := make(chan bool)
+		finished := make(chan bool, 1)
+		cmd := NewTestCommand(mod)
+		cmd.Execute = func(msg *discord.DiscordMessage) {
+			started <- true
+			time.Sleep(time.Millisecond * 50)
+			finished <- msg.Context().Err() == nil
+		}
+		mod.RegisterCommands(cmd)
+		mod.HandleMessage(NewTestMessage(bot, "1"))
+		<-started
+
+		bot.Close()
+		select {
+		case ok := <-finished:
+			if !ok {
+				t.Errorf("Command context was cancelled before the grace period ended")
+			}
+		default:
+			t.Errorf("Close returned before the command finished")
+		}
+	})
+
+	t.Run("cancels handlers after the grace period", func(t *testing.T) {
+		bot, _, _ := setupTestBot()
+		bot.GracePeriod = time.Millisecond * 10
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cancelled := make(chan bool, 1)
+		cmd := NewTestCommand(mod)
+		cmd.Execute = func(msg *discord.DiscordMessage) {
+			<-msg.Context().Done()
+			cancelled <- true
+		}
+		mod.RegisterCommands(cmd)
+		bot.Run(context.Background())
+		mod.HandleMessage(NewTestMessage(bot, "1"))
+
+		bot.Close()
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Errorf("Command was not cancelled")
+		}
+	})
+
+	t.Run("new handlers do not run when closing", func(t *testing.T) {
+		bot, _, _ := setupTestBot()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmdCalled := make(chan bool, 1)
+		cmd := NewTestCommand(mod)
+		cmd.Execute = func(*discord.DiscordMessage) {
+			cmdCalled <- true
+		}
+		mod.RegisterCommands(cmd)
+		bot.Close()
+		mod.HandleMessage(NewTestMessage(bot, "1"))
+
+		select {
+		case <-cmdCalled:
+			t.Errorf("Command was not expected to be called")
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
+}
+
+func TestBot_WaitIdle(t *testing.T) {
+	bot, _, _ := setupTestBot()
+	mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+	release := make(chan struct{})
+	cmd := NewTestCommand(mod)
+	cmd.Execute = func(*discord.DiscordMessage) { <-release }
+	mod.RegisterCommands(cmd)
+	mod.HandleMessage(NewTestMessage(bot, "1"))
+
+	// waits that time out do not leave anything behind
+	before := runtime.NumGoroutine()
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		if err := bot.WaitIdle(ctx); err == nil {
+			t.Fatalf("Expected WaitIdle to time out while a handler runs")
+		}
+		cancel()
+	}
+	if after := runtime.NumGoroutine(); after > before+5 {
+		t.Errorf("Expected WaitIdle to not leak goroutines, went from %v to %v", before, after)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := bot.WaitIdle(ctx); err != nil {
+		t.Errorf("WaitIdle() error = %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+)
+
+// ErrorKind is the kind of failure a CommandError describes, which decides how
+// it is shown to the user and how it is logged.
+type ErrorKind int
+
+const (
+	// ErrorInternal is a failure that is not the fault of the user, like a
+	// failed database query. Errors that are not a CommandError are internal.
+	ErrorInternal ErrorKind = iota
+	// ErrorUsage is a command used the wrong way.
+	ErrorUsage
+	// ErrorPermission is a user, or the bot, missing the rights to do something.
+	ErrorPermission
+	// ErrorNotFound is something the user referred to that does not exist.
+	ErrorNotFound
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorInternal:
+		return "internal"
+	case ErrorUsage:
+		return "usage"
+	case ErrorPermission:
+		return "permission"
+	case ErrorNotFound:
+		return "not_found"
+	}
+	return "unknown"
+}
+
+// CommandError is an error returned from a command handler. Message is shown to
+// the user, while Err is only logged.
+type CommandError struct {
+	Kind    ErrorKind
+	Message string
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%v: %v", e.Kind, e.Message)
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%v: %v: %v", e.Kind, e.Message, e.Err)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// UserMessage returns what the user is told about the error.
+func (e *CommandError) UserMessage() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	switch e.Kind {
+	case ErrorUsage:
+		return "That is not how this command is used"
+	case ErrorPermission:
+		return "You do not have permission to do that"
+	case ErrorNotFound:
+		return "Could not find that"
+	}
+	return "There was an issue, please try again!"
+}
+
+// UsageError returns an error telling the user they used a command wrong.
+func UsageError(format string, a ...any) error {
+	return &CommandError{Kind: ErrorUsage, Message: fmt.Sprintf(format, a...)}
+}
+
+// PermissionError returns an error telling the user they can not do something.
+func PermissionError(format string, a ...any) error {
+	return &CommandError{Kind: ErrorPermission, Message: fmt.Sprintf(format, a...)}
+}
+
+// NotFoundError returns an error telling the user something could not be found.
+func NotFoundError(format string, a ...any) error {
+	return &CommandError{Kind: ErrorNotFound, Message: fmt.Sprintf(format, a...)}
+}
+
+// InternalError wraps err, so it gets logged while the user gets a generic message.
+// Returning err as is has the same effect.
+func InternalError(err error) error {
+	return &CommandError{Kind: ErrorInternal, Err: err}
+}
+
+// AsCommandError returns err as a CommandError. Errors that are not one are
+// treated as internal errors.
+func AsCommandError(err error) *CommandError {
+	var cerr *CommandError
+	if errors.As(err, &cerr) {
+		return cerr
+	}
+	return &CommandError{Kind: ErrorInternal, Err: err}
+}
+
+// UserMessage returns what the user is told about err. It is meant for handlers
+// that do not return errors, like message components, so they word failures the
+// same way commands do.
+func UserMessage(err error) string {
+	return AsCommandError(err).UserMessage()
+}
+
+// commandFailed reports the error a command handler returned. The user gets told
+// about it, it is logged and a CommandFailed event is emitted.
+func (m *ModuleBase) commandFailed(inv *Invocation, err error) {
+	cerr := AsCommandError(err)
+	m.Bot.Emit(&CommandFailed{inv, cerr})
+
+	fields := []any{"kind", cerr.Kind.String(), "type", inv.Type.String(), "name", inv.Name,
+		"guild", inv.GuildID(), "channel", inv.ChannelID(), "user", inv.AuthorID(), "error", err}
+	if cerr.Kind == ErrorInternal {
+		m.Logger.Error("Command failed", fields...)
+	} else {
+		m.Logger.Debug("Command failed", fields...)
+	}
+
+	switch inv.Type {
+	case InvocationCommand:
+		_, _ = inv.Message.Reply(commandErrorReply(inv.Command, cerr, inv.Message.Prefix))
+	case InvocationApplicationCommand:
+		respondError(inv.Interaction, cerr.UserMessage())
+	}
+}
+
+// commandErrorReply returns the reply to a failed text command. Usage errors come
+// with the usage of the command.
+func commandErrorReply(cmd *ModuleCommand, cerr *CommandError, prefix string) string {
+	reply := cerr.UserMessage()
+	if cerr.Kind != ErrorUsage {
+		return reply
+	}
+	usage := cmd.Usage
+	if len(cmd.Arguments) > 0 {
+		usage = cmd.ArgumentUsage()
+	}
+	if usage == "" {
+		return reply
+	}
+	return fmt.Sprintf("%v\nUsage: `%v`", reply, FormatUsage(usage, prefix))
+}
+
+// respondError tells the user of an interaction about a failure. Interactions the
+// handler already responded to get an ephemeral follow-up instead.
+func respondError(it *discord.DiscordInteraction, text string) {
+	if err := it.RespondEphemeral(text); errors.Is(err, discord.ErrInteractionAcknowledged) {
+		_, _ = it.FollowUp(&discordgo.WebhookParams{Content: text, Flags: discordgo.MessageFlagsEphemeral})
+	}
+}
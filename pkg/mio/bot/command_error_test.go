@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsCommandError(t *testing.T) {
+	cerr := AsCommandError(errors.New("connection refused"))
+	assert.Equal(t, ErrorInternal, cerr.Kind)
+	assert.Equal(t, "There was an issue, please try again!", cerr.UserMessage())
+
+	cerr = AsCommandError(fmt.Errorf("warn: %w", NotFoundError("Could not find %v", "jeff")))
+	assert.Equal(t, ErrorNotFound, cerr.Kind)
+	assert.Equal(t, "Could not find jeff", cerr.UserMessage())
+
+	dbErr := errors.New("no rows")
+	err := InternalError(dbErr)
+	assert.ErrorIs(t, err, dbErr)
+	assert.Equal(t, "There was an issue, please try again!", UserMessage(err))
+}
+
+func TestCommandErrorReply(t *testing.T) {
+	cmd := &ModuleCommand{Triggers: []string{"warn"}, Usage: "warn [user] <reason>"}
+	assert.Equal(t, "Missing user\nUsage: `m?warn [user] <reason>`",
+		commandErrorReply(cmd, AsCommandError(UsageError("Missing user")), "m?"))
+	assert.Equal(t, "You can not warn yourself",
+		commandErrorReply(cmd, AsCommandError(PermissionError("You can not warn yourself")), "m?"))
+
+	cmd.Usage = ""
+	assert.Equal(t, "Missing user", commandErrorReply(cmd, AsCommandError(UsageError("Missing user")), "m?"))
+}
+
+func TestModuleBase_CommandFailed(t *testing.T) {
+	bot, mod, sess := newRecordingTestBot()
+	failed := make(chan *CommandFailed, 1)
+	bot.AddHandler(func(evt *CommandFailed) { failed <- evt })
+
+	cmd := NewTestApplicationCommand(mod)
+	cmd.Execute = nil
+	cmd.Run = func(it *discord.DiscordApplicationCommand) error {
+		return NotFoundError("Could not find that user!")
+	}
+	require.NoError(t, mod.RegisterApplicationCommands(cmd))
+	mod.HandleInteraction(NewTestApplicationCommandInteraction(bot, "1"))
+
+	resp := awaitResponse(t, sess)
+	assert.Equal(t, "Could not find that user!", resp.Data.Content)
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, resp.Data.Flags)
+
+	select {
+	case evt := <-failed:
+		assert.Equal(t, ErrorNotFound, evt.Err.Kind)
+		assert.Equal(t, InvocationApplicationCommand, evt.Invocation.Type)
+		assert.Equal(t, "test", evt.Invocation.Name)
+	case <-time.After(time.Second):
+		t.Fatal("Expected a CommandFailed event")
+	}
+}
+
+func TestModuleBase_SubcommandFailed(t *testing.T) {
+	bot, mod, sess := newRecordingTestBot()
+	failed := make(chan *CommandFailed, 1)
+	bot.AddHandler(func(evt *CommandFailed) { failed <- evt })
+
+	cmd := NewModuleApplicationCommandBuilder(mod, "test").
+		Type(discordgo.ChatApplicationCommand).
+		Description("testing").
+		AddSubcommand(&discordgo.ApplicationCommandOption{Name: "get", Description: "get"}, func(sub *discord.DiscordSubcommand) error {
+			return PermissionError("You can not do that!")
+		}).
+		Build()
+	require.NoError(t, mod.RegisterApplicationCommands(cmd))
+	it := NewTestApplicationCommandInteraction(bot, "1")
+	it.Interaction.Data = discordgo.ApplicationCommandInteractionData{
+		Name:        "test",
+		CommandType: discordgo.ChatApplicationCommand,
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: "get", Type: discordgo.ApplicationCommandOptionSubCommand},
+		},
+	}
+	mod.HandleInteraction(it)
+
+	resp := awaitResponse(t, sess)
+	assert.Equal(t, "You can not do that!", resp.Data.Content)
+
+	select {
+	case evt := <-failed:
+		assert.Equal(t, ErrorPermission, evt.Err.Kind)
+	case <-time.After(time.Second):
+		t.Fatal("Expected a CommandFailed event")
+	}
+}
@@ -0,0 +1,230 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandSyncConfig configures how the bot syncs its application commands with
+// Discord when it starts.
+type CommandSyncConfig struct {
+	// DryRun logs the changes a sync would make, without making them.
+	DryRun bool
+	// GuildIDs are synced along with the global commands and the guilds commands
+	// are scoped to, so commands that are no longer scoped to one of them get
+	// deleted from it.
+	GuildIDs []string
+}
+
+// CommandChangeType is what a sync does to a command.
+type CommandChangeType int
+
+const (
+	CommandCreate CommandChangeType = iota
+	CommandEdit
+	CommandDelete
+)
+
+func (t CommandChangeType) String() string {
+	switch t {
+	case CommandCreate:
+		return "create"
+	case CommandEdit:
+		return "edit"
+	case CommandDelete:
+		return "delete"
+	}
+	return "unknown"
+}
+
+// CommandChange is a change a sync makes to the commands of a guild, or to the
+// global commands if GuildID is empty.
+type CommandChange struct {
+	Type    CommandChangeType
+	GuildID string
+	// ID is the ID of the existing command, for edits and deletions.
+	ID string
+	// Command is the declared command, or the existing one for deletions.
+	Command *discordgo.ApplicationCommand
+}
+
+// PlanCommandSync returns the changes that turn the existing commands of a scope
+// into the desired ones. Commands are matched by name and type, and a matched
+// command is only edited if it differs in a way Discord cares about.
+func PlanCommandSync(guildID string, existing, desired []*discordgo.ApplicationCommand) []CommandChange {
+	found := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, c := range existing {
+		found[commandKey(c)] = c
+	}
+
+	var changes []CommandChange
+	for _, c := range desired {
+		key := commandKey(c)
+		old, ok := found[key]
+		if !ok {
+			changes = append(changes, CommandChange{Type: CommandCreate, GuildID: guildID, Command: c})
+			continue
+		}
+		delete(found, key)
+		if !commandsEqual(guildID, old, c) {
+			changes = append(changes, CommandChange{Type: CommandEdit, GuildID: guildID, ID: old.ID, Command: c})
+		}
+	}
+	for _, c := range existing {
+		if _, ok := found[commandKey(c)]; ok {
+			changes = append(changes, CommandChange{Type: CommandDelete, GuildID: guildID, ID: c.ID, Command: c})
+		}
+	}
+	return changes
+}
+
+// SyncApplicationCommands makes the commands Discord has match the commands of the
+// registered modules, and returns the changes it made. In dry run, it only
+// returns and logs the changes it would make. Guilds whose commands can not be
+// fetched are logged and skipped.
+func (b *Bot) SyncApplicationCommands() ([]CommandChange, error) {
+	appID := b.Discord.Sess.State().User.ID
+	desired := b.scopedApplicationCommands()
+
+	var changes []CommandChange
+	for _, guildID := range sortedScopes(desired) {
+		existing, err := b.Discord.Sess.ApplicationCommands(appID, guildID)
+		if err != nil && guildID != "" {
+			// one guild the bot can not reach should not keep the rest from syncing
+			b.Logger.Error("Could not fetch commands, skipping guild", "guild", guildID, "error", err)
+			continue
+		}
+		if err != nil {
+			b.Logger.Error("Could not fetch commands", "guild", guildID, "error", err)
+			return changes, err
+		}
+		changes = append(changes, PlanCommandSync(guildID, existing, desired[guildID])...)
+	}
+
+	for _, c := range changes {
+		fields := []any{"action", c.Type.String(), "name", c.Command.Name, "type", uint8(c.Command.Type), "guild", c.GuildID}
+		if b.CommandSync.DryRun {
+			b.Logger.Info("Planned command change", fields...)
+			continue
+		}
+		if err := b.applyCommandChange(appID, c); err != nil {
+			b.Logger.Error("Could not change command", append(fields, "error", err)...)
+			return changes, err
+		}
+		b.Logger.Info("Changed command", fields...)
+	}
+	if len(changes) == 0 {
+		b.Logger.Info("Commands are up to date")
+	}
+	return changes, nil
+}
+
+func (b *Bot) applyCommandChange(appID string, c CommandChange) error {
+	var err error
+	switch c.Type {
+	case CommandCreate:
+		_, err = b.Discord.Sess.ApplicationCommandCreate(appID, c.GuildID, c.Command)
+	case CommandEdit:
+		_, err = b.Discord.Sess.ApplicationCommandEdit(appID, c.GuildID, c.ID, c.Command)
+	case CommandDelete:
+		err = b.Discord.Sess.ApplicationCommandDelete(appID, c.GuildID, c.ID)
+	}
+	return err
+}
+
+// scopedApplicationCommands returns the commands of the registered modules, keyed
+// by the guild they are scoped to, with global commands under an empty key.
+func (b *Bot) scopedApplicationCommands() map[string][]*discordgo.ApplicationCommand {
+	scopes := map[string][]*discordgo.ApplicationCommand{"": nil}
+	for _, id := range b.CommandSync.GuildIDs {
+		scopes[id] = nil
+	}
+	for _, mod := range b.Modules {
+		for _, cmd := range mod.ApplicationCommands() {
+			if len(cmd.GuildIDs) == 0 {
+				scopes[""] = append(scopes[""], cmd.ApplicationCommand)
+				continue
+			}
+			for _, id := range cmd.GuildIDs {
+				scopes[id] = append(scopes[id], cmd.ApplicationCommand)
+			}
+		}
+	}
+	for _, cmds := range scopes {
+		sort.Slice(cmds, func(i, j int) bool { return commandKey(cmds[i]) < commandKey(cmds[j]) })
+	}
+	return scopes
+}
+
+func sortedScopes(scopes map[string][]*discordgo.ApplicationCommand) []string {
+	ids := make([]string, 0, len(scopes))
+	for id := range scopes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func commandKey(c *discordgo.ApplicationCommand) string {
+	return strconv.Itoa(int(commandType(c))) + ":" + c.Name
+}
+
+func commandType(c *discordgo.ApplicationCommand) discordgo.ApplicationCommandType {
+	if c.Type == 0 {
+		return discordgo.ChatApplicationCommand
+	}
+	return c.Type
+}
+
+// commandsEqual returns whether a and b are the same command to Discord. They are
+// compared as Discord sees them, after filling in the defaults it fills in.
+func commandsEqual(guildID string, a, b *discordgo.ApplicationCommand) bool {
+	ja, errA := json.Marshal(normalizeCommand(guildID, a))
+	jb, errB := json.Marshal(normalizeCommand(guildID, b))
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+func normalizeCommand(guildID string, c *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+	n := *c
+	n.ID, n.ApplicationID, n.GuildID, n.Version = "", "", "", ""
+	n.Type = commandType(c)
+	n.DefaultPermission = nil
+	if guildID != "" {
+		// guild commands can not be used in DMs anyway
+		n.DMPermission = nil
+	} else if n.DMPermission == nil {
+		dm := true
+		n.DMPermission = &dm
+	}
+	if n.NSFW == nil {
+		nsfw := false
+		n.NSFW = &nsfw
+	}
+	n.Options = normalizeOptions(c.Options)
+	return &n
+}
+
+// normalizeOptions copies options with empty lists set to nil, as Discord leaves
+// out the lists a command does not use.
+func normalizeOptions(options []*discordgo.ApplicationCommandOption) []*discordgo.ApplicationCommandOption {
+	if len(options) == 0 {
+		return nil
+	}
+	normalized := make([]*discordgo.ApplicationCommandOption, len(options))
+	for i, o := range options {
+		n := *o
+		n.Options = normalizeOptions(o.Options)
+		if len(n.Choices) == 0 {
+			n.Choices = nil
+		}
+		if len(n.ChannelTypes) == 0 {
+			n.ChannelTypes = nil
+		}
+		normalized[i] = &n
+	}
+	return normalized
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/intrntsrfr/meido/pkg/mio/discord/mocks"
+	"github.com/intrntsrfr/meido/pkg/mio/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanCommandSync(t *testing.T) {
+	dm := true
+	existing := []*discordgo.ApplicationCommand{
+		{ID: "1", Name: "same", Type: discordgo.ChatApplicationCommand, Description: "same", DMPermission: &dm, Version: "5",
+			Options: []*discordgo.ApplicationCommandOption{{Name: "n", Description: "n", Type: discordgo.ApplicationCommandOptionInteger,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{{Name: "one", Value: float64(1)}}}}},
+		{ID: "2", Name: "changed", Type: discordgo.ChatApplicationCommand, Description: "old"},
+		{ID: "3", Name: "removed", Type: discordgo.ChatApplicationCommand, Description: "removed"},
+		{ID: "4", Name: "info", Type: discordgo.UserApplicationCommand},
+	}
+	desired := []*discordgo.ApplicationCommand{
+		{Name: "same", Description: "same",
+			Options: []*discordgo.ApplicationCommandOption{{Name: "n", Description: "n", Type: discordgo.ApplicationCommandOptionInteger,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{{Name: "one", Value: 1}}}}},
+		{Name: "changed", Type: discordgo.ChatApplicationCommand, Description: "new"},
+		{Name: "info", Type: discordgo.UserApplicationCommand},
+		{Name: "info", Type: discordgo.ChatApplicationCommand, Description: "info"},
+	}
+
+	changes := PlanCommandSync("", existing, desired)
+	assert.Equal(t, []CommandChange{
+		{Type: CommandEdit, ID: "2", Command: desired[1]},
+		{Type: CommandCreate, Command: desired[3]},
+		{Type: CommandDelete, ID: "3", Command: existing[2]},
+	}, changes)
+}
+
+func TestPlanCommandSync_GuildIgnoresDMPermission(t *testing.T) {
+	dm := false
+	existing := []*discordgo.ApplicationCommand{{ID: "1", Name: "cmd", Type: discordgo.ChatApplicationCommand, Description: "cmd"}}
+	desired := []*discordgo.ApplicationCommand{{Name: "cmd", Type: discordgo.ChatApplicationCommand, Description: "cmd", DMPermission: &dm}}
+	assert.Empty(t, PlanCommandSync("123", existing, desired))
+	assert.Len(t, PlanCommandSync("", existing, desired), 1)
+}
+
+func TestPlanCommandSync_EmptyLists(t *testing.T) {
+	existing := []*discordgo.ApplicationCommand{{ID: "1", Name: "cmd", Type: discordgo.ChatApplicationCommand, Description: "cmd",
+		Options: []*discordgo.ApplicationCommandOption{{Name: "n", Description: "n", Type: discordgo.ApplicationCommandOptionString}}}}
+	desired := []*discordgo.ApplicationCommand{{Name: "cmd", Type: discordgo.ChatApplicationCommand, Description: "cmd",
+		Options: []*discordgo.ApplicationCommandOption{{Name: "n", Description: "n", Type: discordgo.ApplicationCommandOptionString,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{}, Options: []*discordgo.ApplicationCommandOption{}}}}}
+	assert.Empty(t, PlanCommandSync("", existing, desired))
+
+	existing[0].Options = nil
+	desired[0].Options = []*discordgo.ApplicationCommandOption{}
+	assert.Empty(t, PlanCommandSync("", existing, desired))
+}
+
+func newCommandSyncTestBot(cfg CommandSyncConfig) (*Bot, *testModule, *mocks.DiscordSessionMock) {
+	conf := test.NewTestConfig()
+	sess := mocks.NewDiscordSession(conf.GetString("token"), conf.GetInt("shards"))
+	_ = sess.Open()
+	bot := NewBotBuilder(conf).
+		WithDiscord(discord.NewTestDiscord(conf, sess, nil)).
+		WithLogger(mio.NewDiscardLogger()).
+		WithCommandSync(cfg).
+		Build()
+	mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+	bot.RegisterModule(mod)
+	return bot, mod, sess
+}
+
+func TestBot_SyncApplicationCommands(t *testing.T) {
+	bot, mod, sess := newCommandSyncTestBot(CommandSyncConfig{GuildIDs: []string{"old"}})
+	global := NewTestApplicationCommand(mod)
+	dev := NewModuleApplicationCommandBuilder(mod, "dev").
+		Type(discordgo.ChatApplicationCommand).
+		Description("dev").
+		Guilds("dev").
+		Execute(testApplicationCommandRun).
+		Build()
+	assert.NoError(t, mod.RegisterApplicationCommands(global, dev))
+	_, _ = sess.ApplicationCommandCreate("1", "old", &discordgo.ApplicationCommand{Name: "dev", Description: "dev"})
+
+	changes, err := bot.SyncApplicationCommands()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 3)
+
+	cmds, _ := sess.ApplicationCommands("1", "")
+	assert.Len(t, cmds, 1)
+	assert.Equal(t, "test", cmds[0].Name)
+	cmds, _ = sess.ApplicationCommands("1", "dev")
+	assert.Len(t, cmds, 1)
+	cmds, _ = sess.ApplicationCommands("1", "old")
+	assert.Empty(t, cmds)
+
+	changes, err = bot.SyncApplicationCommands()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	global.Description = "changed"
+	changes, err = bot.SyncApplicationCommands()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, CommandEdit, changes[0].Type)
+	cmds, _ = sess.ApplicationCommands("1", "")
+	assert.Equal(t, "changed", cmds[0].Description)
+}
+
+func TestBot_SyncApplicationCommands_DryRun(t *testing.T) {
+	bot, mod, sess := newCommandSyncTestBot(CommandSyncConfig{DryRun: true})
+	assert.NoError(t, mod.RegisterApplicationCommands(NewTestApplicationCommand(mod)))
+
+	changes, err := bot.SyncApplicationCommands()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, CommandCreate, changes[0].Type)
+
+	cmds, _ := sess.ApplicationCommands("1", "")
+	assert.Empty(t, cmds)
+}
+
+// unreachableGuildSession fails to fetch the commands of one guild.
+type unreachableGuildSession struct {
+	*mocks.DiscordSessionMock
+	guildID string
+}
+
+func (s *unreachableGuildSession) ApplicationCommands(appID, guildID string, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	if guildID == s.guildID {
+		return nil, errors.New("missing access")
+	}
+	return s.DiscordSessionMock.ApplicationCommands(appID, guildID, options...)
+}
+
+func TestBot_SyncApplicationCommands_SkipsUnreachableGuild(t *testing.T) {
+	conf := test.NewTestConfig()
+	sess := &unreachableGuildSession{DiscordSessionMock: mocks.NewDiscordSession(conf.GetString("token"), conf.GetInt("shards")), guildID: "gone"}
+	_ = sess.Open()
+	bot := NewBotBuilder(conf).
+		WithDiscord(discord.NewTestDiscord(conf, sess, nil)).
+		WithLogger(mio.NewDiscardLogger()).
+		WithCommandSync(CommandSyncConfig{GuildIDs: []string{"gone"}}).
+		Build()
+	mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+	bot.RegisterModule(mod)
+	assert.NoError(t, mod.RegisterApplicationCommands(NewTestApplicationCommand(mod)))
+
+	changes, err := bot.SyncApplicationCommands()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	cmds, _ := sess.ApplicationCommands("1", "")
+	assert.Len(t, cmds, 1)
+}
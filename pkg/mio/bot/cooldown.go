@@ -0,0 +1,179 @@
+package bot
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// CooldownMode decides how uses of a command are limited over its cooldown.
+type CooldownMode int
+
+const (
+	// CooldownModeFixed locks a command for the whole cooldown after each use.
+	CooldownModeFixed CooldownMode = iota
+	// CooldownModeSlidingWindow allows a number of uses within any window as long
+	// as the cooldown.
+	CooldownModeSlidingWindow
+	// CooldownModeTokenBucket allows bursts of a number of uses, and gives back
+	// uses evenly over the cooldown.
+	CooldownModeTokenBucket
+)
+
+// CooldownLimit limits a command to Uses uses per Period. Uses is ignored by
+// CooldownModeFixed, and defaults to 1 in the other modes.
+type CooldownLimit struct {
+	Mode   CooldownMode
+	Uses   int
+	Period time.Duration
+}
+
+// CooldownState is what a CooldownStore keeps per key. It is JSON encodable, so
+// stores can persist it.
+type CooldownState struct {
+	// Hits are the uses within the current window, oldest first.
+	Hits []time.Time `json:"hits,omitempty"`
+	// Tokens are the uses left in the bucket as of Updated.
+	Tokens  float64   `json:"tokens,omitempty"`
+	Updated time.Time `json:"updated,omitempty"`
+}
+
+func (l CooldownLimit) uses() int {
+	if l.Mode == CooldownModeFixed || l.Uses < 1 {
+		return 1
+	}
+	return l.Uses
+}
+
+// Take uses the limit once at now, updating state. If the limit is used up, no use
+// is recorded, and the time until the next use is allowed is returned.
+func (l CooldownLimit) Take(state *CooldownState, now time.Time) (time.Duration, bool) {
+	if l.Period <= 0 {
+		return 0, true
+	}
+	if l.Mode == CooldownModeTokenBucket {
+		return l.takeToken(state, now)
+	}
+
+	start := now.Add(-l.Period)
+	hits := state.Hits[:0]
+	for _, h := range state.Hits {
+		if h.After(start) {
+			hits = append(hits, h)
+		}
+	}
+	state.Hits = hits
+	if len(hits) >= l.uses() {
+		return hits[len(hits)-l.uses()].Sub(start), false
+	}
+	state.Hits = append(state.Hits, now)
+	return 0, true
+}
+
+func (l CooldownLimit) takeToken(state *CooldownState, now time.Time) (time.Duration, bool) {
+	uses := float64(l.uses())
+	perToken := float64(l.Period) / uses
+	tokens := uses
+	if !state.Updated.IsZero() {
+		tokens = math.Min(uses, state.Tokens+float64(now.Sub(state.Updated))/perToken)
+	}
+	if tokens < 1 {
+		return time.Duration(math.Ceil((1 - tokens) * perToken)), false
+	}
+	state.Tokens = tokens - 1
+	state.Updated = now
+	return 0, true
+}
+
+// Expires returns when state no longer limits anything, after which it can be forgotten.
+func (l CooldownLimit) Expires(state *CooldownState) time.Time {
+	if l.Mode == CooldownModeTokenBucket {
+		missing := float64(l.uses()) - state.Tokens
+		return state.Updated.Add(time.Duration(missing * float64(l.Period) / float64(l.uses())))
+	}
+	if len(state.Hits) == 0 {
+		return time.Time{}
+	}
+	return state.Hits[len(state.Hits)-1].Add(l.Period)
+}
+
+// CooldownStore keeps track of cooldowns by key.
+type CooldownStore interface {
+	// Take uses limit once for key. If it is used up, the time until key can be
+	// used again is returned, and ok is false.
+	Take(key string, limit CooldownLimit) (retryAfter time.Duration, ok bool, err error)
+	// Reset removes the cooldown of key.
+	Reset(key string) error
+}
+
+// MemoryCooldownStore is a CooldownStore that keeps cooldowns in memory.
+type MemoryCooldownStore struct {
+	sync.Mutex
+	entries   map[string]*cooldownEntry
+	lastSweep time.Time
+}
+
+type cooldownEntry struct {
+	state   CooldownState
+	expires time.Time
+}
+
+// cooldownSweepInterval is how often expired cooldowns are removed from a MemoryCooldownStore.
+const cooldownSweepInterval = time.Minute
+
+func NewMemoryCooldownStore() *MemoryCooldownStore {
+	return &MemoryCooldownStore{
+		entries:   make(map[string]*cooldownEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryCooldownStore) Take(key string, limit CooldownLimit) (time.Duration, bool, error) {
+	if limit.Period <= 0 {
+		return 0, true, nil
+	}
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= cooldownSweepInterval {
+		s.sweep(now)
+	}
+
+	e, ok := s.entries[key]
+	if !ok || !now.Before(e.expires) {
+		e = &cooldownEntry{}
+		s.entries[key] = e
+	}
+	wait, ok := limit.Take(&e.state, now)
+	e.expires = limit.Expires(&e.state)
+	return wait, ok, nil
+}
+
+func (s *MemoryCooldownStore) Reset(key string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryCooldownStore) sweep(now time.Time) {
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// takeCooldown uses the cooldown of key. Cooldowns are not enforced if the store fails.
+func (m *ModuleBase) takeCooldown(key string, limit CooldownLimit) (time.Duration, bool) {
+	if key == "" || limit.Period <= 0 {
+		return 0, true
+	}
+	wait, ok, err := m.Bot.Cooldowns.Take(key, limit)
+	if err != nil {
+		m.Logger.Error("Could not take cooldown", "key", key, "error", err)
+		return 0, true
+	}
+	return wait, ok
+}
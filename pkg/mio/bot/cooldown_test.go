@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCooldownLimit_Take(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	t.Run("fixed", func(t *testing.T) {
+		limit := CooldownLimit{Mode: CooldownModeFixed, Uses: 3, Period: time.Minute}
+		var state CooldownState
+		_, ok := limit.Take(&state, at(0))
+		assert.True(t, ok)
+		wait, ok := limit.Take(&state, at(time.Second*20))
+		assert.False(t, ok)
+		assert.Equal(t, time.Second*40, wait)
+		_, ok = limit.Take(&state, at(time.Minute))
+		assert.True(t, ok)
+	})
+
+	t.Run("sliding window", func(t *testing.T) {
+		limit := CooldownLimit{Mode: CooldownModeSlidingWindow, Uses: 3, Period: time.Minute}
+		var state CooldownState
+		for _, d := range []time.Duration{0, time.Second * 10, time.Second * 20} {
+			_, ok := limit.Take(&state, at(d))
+			assert.True(t, ok)
+		}
+		wait, ok := limit.Take(&state, at(time.Second*30))
+		assert.False(t, ok)
+		assert.Equal(t, time.Second*30, wait)
+		_, ok = limit.Take(&state, at(time.Second*61))
+		assert.True(t, ok)
+		_, ok = limit.Take(&state, at(time.Second*62))
+		assert.False(t, ok)
+	})
+
+	t.Run("token bucket", func(t *testing.T) {
+		limit := CooldownLimit{Mode: CooldownModeTokenBucket, Uses: 3, Period: time.Minute}
+		var state CooldownState
+		for i := 0; i < 3; i++ {
+			_, ok := limit.Take(&state, at(0))
+			assert.True(t, ok)
+		}
+		wait, ok := limit.Take(&state, at(time.Second*5))
+		assert.False(t, ok)
+		assert.Equal(t, time.Second*15, wait)
+		_, ok = limit.Take(&state, at(time.Second*20))
+		assert.True(t, ok)
+		_, ok = limit.Take(&state, at(time.Second*21))
+		assert.False(t, ok)
+		assert.Equal(t, at(time.Second*80), limit.Expires(&state))
+	})
+
+	t.Run("no period", func(t *testing.T) {
+		var state CooldownState
+		_, ok := CooldownLimit{}.Take(&state, at(0))
+		assert.True(t, ok)
+		_, ok = CooldownLimit{}.Take(&state, at(0))
+		assert.True(t, ok)
+	})
+}
+
+func TestMemoryCooldownStore(t *testing.T) {
+	s := NewMemoryCooldownStore()
+	limit := CooldownLimit{Mode: CooldownModeSlidingWindow, Uses: 2, Period: time.Millisecond * 50}
+
+	for i := 0; i < 2; i++ {
+		_, ok, err := s.Take("key", limit)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	_, ok, _ := s.Take("key", limit)
+	assert.False(t, ok)
+	_, ok, _ = s.Take("other", limit)
+	assert.True(t, ok)
+
+	time.Sleep(time.Millisecond * 60)
+	_, ok, _ = s.Take("key", limit)
+	assert.True(t, ok)
+
+	_, _, _ = s.Take("key", limit)
+	_ = s.Reset("key")
+	_, ok, _ = s.Take("key", limit)
+	assert.True(t, ok)
+}
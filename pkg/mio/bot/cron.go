@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a recurring job runs.
+type Schedule interface {
+	// Next returns the first time after t the job should run.
+	Next(t time.Time) time.Time
+	String() string
+}
+
+type everySchedule struct {
+	d time.Duration
+}
+
+// Every returns a Schedule that runs every d, counted from when the last run was due.
+// The Scheduler counts the first run from a multiple of d, so schedulers in
+// different processes agree on when runs are due.
+func Every(d time.Duration) Schedule {
+	return &everySchedule{d}
+}
+
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.d)
+}
+
+func (s *everySchedule) String() string {
+	return "every " + s.d.String()
+}
+
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// cronSchedule is a parsed cron expression. Each field is a bitmask of the values
+// it matches.
+type cronSchedule struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are set when the day fields are *. If neither is, a day
+	// matches if either field matches, like in cron.
+	domStar, dowStar bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":  "0 0 1 1 *",
+	"@monthly": "0 0 1 * *",
+	"@weekly":  "0 0 * * 0",
+	"@daily":   "0 0 * * *",
+	"@hourly":  "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseCron parses a cron expression with the fields minute, hour, day of month,
+// month and day of week. Fields can be *, a value, a range like 1-5, a list like
+// 1,3,5 and have a step like */15. Sunday is both 0 and 7 in the day of week
+// field. @yearly, @monthly, @weekly, @daily and @hourly can be used as well.
+// Times are matched in the location of the time passed to Next.
+func ParseCron(expr string) (Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if d, ok := cronDescriptors[spec]; ok {
+		spec = d
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("%w: %q should have %v fields", ErrInvalidCron, expr, len(cronFields))
+	}
+
+	masks := make([]uint64, len(parts))
+	for i, part := range parts {
+		mask, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidCron, expr, err)
+		}
+		masks[i] = mask
+	}
+	// sunday is both 0 and 7
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+	return &cronSchedule{
+		expr:    expr,
+		minute:  masks[0],
+		hour:    masks[1],
+		dom:     masks[2],
+		month:   masks[3],
+		dow:     masks[4],
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}, nil
+}
+
+// MustParseCron is like ParseCron, but panics if expr is invalid.
+func MustParseCron(expr string) Schedule {
+	s, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func parseCronField(field string, f cronField) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return 0, fmt.Errorf("bad step %q in %v", stepStr, f.name)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			loStr, hiStr, _ := strings.Cut(rng, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(loStr)
+			hi, err2 = strconv.Atoi(hiStr)
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, fmt.Errorf("bad range %q in %v", rng, f.name)
+			}
+		default:
+			v, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("bad value %q in %v", rng, f.name)
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		if lo < f.min || hi > f.max {
+			return 0, fmt.Errorf("%q is out of range for %v, which is %v-%v", rng, f.name, f.min, f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// every valid expression matches at least once within a few years
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func (s *cronSchedule) String() string {
+	return s.expr
+}
@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "*/15 0-6,18 1 */2 1-5", "0 0 * * 7", "@hourly", "5/10 * * * *"} {
+		_, err := ParseCron(expr)
+		assert.NoError(t, err, expr)
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		_, err := ParseCron(expr)
+		assert.ErrorIs(t, err, ErrInvalidCron, expr)
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// a wednesday
+	start := time.Date(2024, 1, 3, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 3, 10, 31, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2024, 1, 3, 10, 40, 0, 0, time.UTC)},
+		{"15 9 * * *", time.Date(2024, 1, 4, 9, 15, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 * *", time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		// either day field matches when both are set
+		{"0 0 10 * 5", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"30 10 3 1 *", time.Date(2025, 1, 3, 10, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, s.Next(start), tt.expr)
+	}
+
+	s := MustParseCron("0 0 30 2 *")
+	assert.True(t, s.Next(start).IsZero(), "Schedules that never match should return the zero time")
+}
+
+func TestEvery(t *testing.T) {
+	s := Every(time.Hour)
+	start := time.Date(2024, 1, 3, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, start.Add(time.Hour), s.Next(start))
+	assert.Equal(t, "every 1h0m0s", s.String())
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MaxCustomIDLength is the longest custom ID Discord accepts.
+const MaxCustomIDLength = 100
+
+// customIDSignatureSize is how many bytes of the HMAC are kept in custom IDs.
+const customIDSignatureSize = 8
+
+var (
+	ErrCustomIDTooLong      = errors.New("custom id is too long")
+	ErrCustomIDMalformed    = errors.New("custom id is malformed")
+	ErrCustomIDSignature    = errors.New("custom id signature is invalid")
+	ErrCustomIDName         = errors.New("custom id name must not be empty or contain ':'")
+	ErrCustomIDPayloadType  = errors.New("custom id payload must be a struct or a pointer to a struct")
+	ErrCustomIDPayloadCount = errors.New("custom id payload does not match the target")
+)
+
+// CustomIDCodec encodes component names and payloads into signed custom IDs. The
+// state a component needs lives in its custom ID, so it keeps working after a
+// restart, and the signature keeps users from forging it.
+//
+// Custom IDs look like name:version:signature:payload, where the payload is a JSON
+// array of the exported fields of a struct, in order.
+type CustomIDCodec struct {
+	key []byte
+}
+
+// NewCustomIDCodec returns a codec that signs custom IDs with secret. Custom IDs
+// only decode with the secret they were encoded with.
+func NewCustomIDCodec(secret []byte) *CustomIDCodec {
+	return &CustomIDCodec{key: secret}
+}
+
+// NewRandomCustomIDCodec returns a codec with a random secret. Its custom IDs stop
+// decoding when the process exits.
+func NewRandomCustomIDCodec() *CustomIDCodec {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	return NewCustomIDCodec(secret)
+}
+
+// Encode returns the custom ID of the component with name and version, carrying
+// payload. payload is a struct, a pointer to a struct, or nil.
+func (c *CustomIDCodec) Encode(name string, version int, payload any) (string, error) {
+	if name == "" || strings.Contains(name, ":") {
+		return "", ErrCustomIDName
+	}
+	data := ""
+	if payload != nil {
+		values, err := payloadValues(payload)
+		if err != nil {
+			return "", err
+		}
+		b, err := json.Marshal(values)
+		if err != nil {
+			return "", err
+		}
+		data = string(b)
+	}
+	head := name + ":" + strconv.Itoa(version)
+	id := head + ":" + c.sign(head, data) + ":" + data
+	if len(id) > MaxCustomIDLength {
+		return "", ErrCustomIDTooLong
+	}
+	return id, nil
+}
+
+// Decode verifies customID and returns what it carries.
+func (c *CustomIDCodec) Decode(customID string) (*ComponentArgs, error) {
+	parts := strings.SplitN(customID, ":", 4)
+	if len(parts) != 4 || parts[0] == "" {
+		return nil, ErrCustomIDMalformed
+	}
+	version, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, ErrCustomIDMalformed
+	}
+	head := parts[0] + ":" + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(c.sign(head, parts[3]))) {
+		return nil, ErrCustomIDSignature
+	}
+	args := &ComponentArgs{Name: parts[0], Version: version}
+	if parts[3] != "" {
+		if err := json.Unmarshal([]byte(parts[3]), &args.values); err != nil {
+			return nil, ErrCustomIDMalformed
+		}
+	}
+	return args, nil
+}
+
+func (c *CustomIDCodec) sign(head, data string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(head + ":" + data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:customIDSignatureSize])
+}
+
+// ComponentArgs is what a signed custom ID carries.
+type ComponentArgs struct {
+	Name    string
+	Version int
+
+	values []json.RawMessage
+}
+
+// Decode decodes the payload into v, which must be a pointer to a struct of the
+// same shape as the one that was encoded.
+func (a *ComponentArgs) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return ErrCustomIDPayloadType
+	}
+	fields := exportedFields(rv.Elem())
+	if len(fields) != len(a.values) {
+		return ErrCustomIDPayloadCount
+	}
+	for i, f := range fields {
+		if err := json.Unmarshal(a.values[i], f.Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func payloadValues(payload any) ([]any, error) {
+	rv := reflect.ValueOf(payload)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrCustomIDPayloadType
+	}
+	fields := exportedFields(rv)
+	values := make([]any, len(fields))
+	for i, f := range fields {
+		values[i] = f.Interface()
+	}
+	return values, nil
+}
+
+// rawPayload returns the values of payload encoded as JSON, the way ComponentArgs
+// keeps them.
+func rawPayload(payload any) ([]json.RawMessage, error) {
+	values, err := payloadValues(payload)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]json.RawMessage, len(values))
+	for i, v := range values {
+		if raw[i], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return raw, nil
+}
+
+func exportedFields(rv reflect.Value) []reflect.Value {
+	var fields []reflect.Value
+	for i := 0; i < rv.NumField(); i++ {
+		if rv.Type().Field(i).IsExported() {
+			fields = append(fields, rv.Field(i))
+		}
+	}
+	return fields
+}
@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testComponentPayload struct {
+	UserID string
+	Page   int
+	Sorted bool
+	hidden string
+}
+
+func TestCustomIDCodec_RoundTrip(t *testing.T) {
+	codec := NewCustomIDCodec([]byte("secret"))
+	id, err := codec.Encode("pager", 3, &testComponentPayload{UserID: "123", Page: 4, Sorted: true, hidden: "x"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(id, "pager:3:"))
+	assert.LessOrEqual(t, len(id), MaxCustomIDLength)
+
+	args, err := codec.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "pager", args.Name)
+	assert.Equal(t, 3, args.Version)
+
+	var got testComponentPayload
+	assert.NoError(t, args.Decode(&got))
+	assert.Equal(t, testComponentPayload{UserID: "123", Page: 4, Sorted: true}, got)
+
+	var other struct{ UserID string }
+	assert.ErrorIs(t, args.Decode(&other), ErrCustomIDPayloadCount)
+	assert.ErrorIs(t, args.Decode(got), ErrCustomIDPayloadType)
+}
+
+func TestCustomIDCodec_NoPayload(t *testing.T) {
+	codec := NewCustomIDCodec([]byte("secret"))
+	id, err := codec.Encode("stop", 0, nil)
+	assert.NoError(t, err)
+	args, err := codec.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "stop", args.Name)
+}
+
+func TestCustomIDCodec_Rejects(t *testing.T) {
+	codec := NewCustomIDCodec([]byte("secret"))
+	id, err := codec.Encode("pager", 1, testComponentPayload{UserID: "123"})
+	assert.NoError(t, err)
+
+	_, err = NewCustomIDCodec([]byte("other")).Decode(id)
+	assert.ErrorIs(t, err, ErrCustomIDSignature)
+
+	tampered := strings.Replace(id, "123", "456", 1)
+	_, err = codec.Decode(tampered)
+	assert.ErrorIs(t, err, ErrCustomIDSignature)
+
+	for _, id := range []string{"pager", "pager:key", ":1:sig:", "pager:x:sig:"} {
+		_, err = codec.Decode(id)
+		assert.ErrorIs(t, err, ErrCustomIDMalformed, id)
+	}
+}
+
+func TestCustomIDCodec_EncodeErrors(t *testing.T) {
+	codec := NewCustomIDCodec([]byte("secret"))
+	_, err := codec.Encode("a:b", 1, nil)
+	assert.ErrorIs(t, err, ErrCustomIDName)
+	_, err = codec.Encode("pager", 1, "not a struct")
+	assert.ErrorIs(t, err, ErrCustomIDPayloadType)
+	_, err = codec.Encode("pager", 1, struct{ Text string }{strings.Repeat("a", MaxCustomIDLength)})
+	assert.ErrorIs(t, err, ErrCustomIDTooLong)
+}
@@ -12,20 +12,33 @@ type EventHandler struct {
 	discord   *discord.Discord
 	modules   *ModuleManager
 	callbacks *utils.CallbackManager
+	prompts   *Prompts
+	prefixes  PrefixResolver
+	toggles   ToggleStore
 	logger    mio.Logger
 	emitter   *mio.EventBus
+	workers   *WorkerPools
 }
 
-func NewEventHandler(d *discord.Discord, m *ModuleManager, c *utils.CallbackManager, bus *mio.EventBus, logger mio.Logger) *EventHandler {
+func NewEventHandler(d *discord.Discord, m *ModuleManager, c *utils.CallbackManager, pr *Prompts, p PrefixResolver, t ToggleStore, bus *mio.EventBus, workers *WorkerPools, logger mio.Logger) *EventHandler {
 	return &EventHandler{
 		discord:   d,
 		modules:   m,
 		callbacks: c,
+		prompts:   pr,
+		prefixes:  p,
+		toggles:   t,
 		emitter:   bus,
+		workers:   workers,
 		logger:    logger.Named("EventHandler"),
 	}
 }
 
+// Listen dispatches messages and interactions to the worker pools until ctx is done.
+// Events are dropped or wait for room when a pool is full, depending on its OverflowPolicy.
+//
+// Prompts and callbacks get their events in the listener rather than on a pool, as the
+// handlers waiting for them hold a worker, and could otherwise use up the pool they wait on.
 func (mp *EventHandler) Listen(ctx context.Context) {
 	mp.logger.Info("Started listener")
 	for {
@@ -34,39 +47,105 @@ func (mp *EventHandler) Listen(ctx context.Context) {
 			if !ok {
 				continue
 			}
-			go mp.DeliverCallbacks(msg)
-			go mp.HandleMessage(msg)
-			go mp.emitter.Emit(&MessageProcessed{})
+			mp.DeliverCallbacks(msg)
+			queued := mp.workers.Pool(msg.Shard).Submit(func() {
+				// the prefix can take a DB lookup, so it is not resolved in the listener
+				mp.SetPrefix(msg)
+				mp.HandleMessage(msg)
+			})
+			if queued {
+				mp.emitter.Emit(&MessageProcessed{})
+			}
 		case it, ok := <-mp.discord.Interactions():
 			if !ok {
 				continue
 			}
-			go mp.HandleInteraction(it)
-			go mp.emitter.Emit(&InteractionProcessed{})
+			if mp.prompts.deliverComponent(it) {
+				mp.emitter.Emit(&InteractionProcessed{})
+				continue
+			}
+			if mp.workers.Pool(it.Shard).Submit(func() { mp.handleModuleInteraction(it) }) {
+				mp.emitter.Emit(&InteractionProcessed{})
+			}
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// HandleMessage runs the passives of every module, and the command the message
+// triggers. If several commands match, the one with the longest trigger runs.
+// Modules and commands that are disabled in the guild are skipped.
 func (mp *EventHandler) HandleMessage(msg *discord.DiscordMessage) {
+	guildID := msg.GuildID()
 	for _, mod := range mp.modules.Modules {
-		mod.HandleMessage(msg)
+		if !mp.isDisabled(guildID, ToggleModule, mod.Name()) {
+			mod.HandlePassives(msg)
+		}
 	}
+
+	if msg.Prefix == "" || len(msg.Args()) <= 0 {
+		return
+	}
+	mod, cmd, err := mp.modules.MatchCommand(msg.CommandContent())
+	if err != nil {
+		return
+	}
+	if mp.isDisabled(guildID, ToggleModule, mod.Name()) || mp.isDisabled(guildID, ToggleCommand, cmd.Name) {
+		return
+	}
+	mod.HandleCommand(cmd, msg)
 }
 
+func (mp *EventHandler) isDisabled(guildID string, kind ToggleKind, name string) bool {
+	if guildID == "" || mp.toggles == nil {
+		return false
+	}
+	return mp.toggles.IsDisabled(guildID, kind, name)
+}
+
+// SetPrefix finds which prefix, if any, a message was invoked with and
+// stores it on the message.
+func (mp *EventHandler) SetPrefix(msg *discord.DiscordMessage) {
+	if msg.Message == nil || msg.Type() == discord.MessageTypeDelete {
+		return
+	}
+	botID := ""
+	if u := mp.discord.Sess.State().User; u != nil {
+		botID = u.ID
+	}
+	msg.Prefix, _ = matchPrefix(msg.RawContent(), mp.prefixes.Prefix(msg.GuildID()), botID)
+}
+
+// HandleInteraction passes an interaction to every module that is not disabled
+// in the guild. Component clicks that a prompt is waiting for only go to the prompt.
 func (mp *EventHandler) HandleInteraction(it *discord.DiscordInteraction) {
+	if mp.prompts.deliverComponent(it) {
+		return
+	}
+	mp.handleModuleInteraction(it)
+}
+
+func (mp *EventHandler) handleModuleInteraction(it *discord.DiscordInteraction) {
+	guildID := it.GuildID()
 	for _, mod := range mp.modules.Modules {
+		if mp.isDisabled(guildID, ToggleModule, mod.Name()) {
+			continue
+		}
 		mod.HandleInteraction(it)
 	}
 }
 
+// DeliverCallbacks hands new messages to waiting prompts and callbacks. It does
+// not block.
 func (mp *EventHandler) DeliverCallbacks(msg *discord.DiscordMessage) {
 	if msg.Type() != discord.MessageTypeCreate {
 		return
 	}
+	mp.prompts.messages.Deliver(msg)
 
 	if ch, err := mp.callbacks.Get(msg.CallbackKey()); err == nil {
-		ch <- msg
+		// the channel is unbuffered, and this runs in the listener
+		go func() { ch <- msg }()
 	}
 }
@@ -2,6 +2,7 @@ package bot
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/intrntsrfr/meido/pkg/mio/discord"
 )
@@ -21,6 +22,8 @@ const (
 	BotEventModalSubmitPanicked
 	BotEventMessageProcessed
 	BotEventInteractionProcessed
+	BotEventCommandTimedOut
+	BotEventCommandFailed
 )
 
 func (b BotEvent) String() string {
@@ -49,6 +52,10 @@ func (b BotEvent) String() string {
 		return "message_processed"
 	case BotEventInteractionProcessed:
 		return "interaction_processed"
+	case BotEventCommandTimedOut:
+		return "command_timed_out"
+	case BotEventCommandFailed:
+		return "command_failed"
 	default:
 		return fmt.Sprintf("Unknown: BotEvent(%d)", b)
 	}
@@ -113,6 +120,20 @@ type MessageComponentPanicked struct {
 	Reason           any
 }
 
+// CommandTimedOut is emitted when a command, passive or interaction handler
+// is still running when its deadline passes.
+type CommandTimedOut struct {
+	Invocation *Invocation
+	Timeout    time.Duration
+}
+
+// CommandFailed is emitted when a command or application command handler returns
+// an error. Errors that are not a CommandError are wrapped as internal errors.
+type CommandFailed struct {
+	Invocation *Invocation
+	Err        *CommandError
+}
+
 type MessageProcessed struct{}
 
 type InteractionProcessed struct{}
@@ -0,0 +1,378 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+)
+
+// CommandContext is what a ModuleHybridCommand gets when it runs, regardless of
+// whether it was invoked through a text trigger or an application command.
+type CommandContext interface {
+	// Context returns the context the command runs with.
+	Context() context.Context
+	Discord() *discord.Discord
+	Author() *discordgo.User
+	AuthorID() string
+	GuildID() string
+	ChannelID() string
+	IsDM() bool
+	// Args returns the parsed arguments of the command.
+	Args() *CommandArgs
+
+	Reply(text string) error
+	ReplyEmbed(embed *discordgo.MessageEmbed) error
+	ReplyFile(text, name string, reader io.Reader) error
+	ReplyComplex(data *discordgo.MessageSend) error
+	// EditReply edits the content of the first reply.
+	EditReply(text string) error
+}
+
+// ModuleHybridCommand is a command that is registered both as a ModuleCommand and as
+// a ModuleApplicationCommand, so it can be used through text triggers and slash commands.
+type ModuleHybridCommand struct {
+	Mod              Module
+	Name             string
+	Description      string
+	Triggers         []string
+	Usage            string
+	Arguments        []*CommandArgument
+	Cooldown         time.Duration
+	CooldownScope    CooldownScope
+	CooldownMode     CooldownMode
+	CooldownUses     int
+	RequiredPerms    int64
+	RequiresUserType UserType
+	CheckBotPerms    bool
+	AllowDMs         bool
+	Enabled          bool
+	Timeout          time.Duration
+	Execute          func(CommandContext) `json:"-"`
+	// Run is used instead of Execute if set, and reports failures the way
+	// ModuleCommand.Run does.
+	Run func(CommandContext) error `json:"-"`
+}
+
+func (c *ModuleHybridCommand) run(ctx CommandContext) error {
+	if c.Run != nil {
+		return c.Run(ctx)
+	}
+	c.Execute(ctx)
+	return nil
+}
+
+// TextCommand returns the ModuleCommand variant of the command.
+func (c *ModuleHybridCommand) TextCommand() *ModuleCommand {
+	return &ModuleCommand{
+		Mod:              c.Mod,
+		Name:             c.Name,
+		Description:      c.Description,
+		Triggers:         c.Triggers,
+		Usage:            c.Usage,
+		Cooldown:         c.Cooldown,
+		CooldownScope:    c.CooldownScope,
+		CooldownMode:     c.CooldownMode,
+		CooldownUses:     c.CooldownUses,
+		RequiredPerms:    c.RequiredPerms,
+		RequiresUserType: c.RequiresUserType,
+		CheckBotPerms:    c.CheckBotPerms,
+		AllowedTypes:     discord.MessageTypeCreate,
+		AllowDMs:         c.AllowDMs,
+		Enabled:          c.Enabled,
+		Timeout:          c.Timeout,
+		Arguments:        c.Arguments,
+		Run: func(msg *discord.DiscordMessage, args *CommandArgs) error {
+			return c.run(&messageContext{msg: msg, args: args})
+		},
+	}
+}
+
+// ApplicationCommand returns the ModuleApplicationCommand variant of the command.
+func (c *ModuleHybridCommand) ApplicationCommand() *ModuleApplicationCommand {
+	appCmd := &discordgo.ApplicationCommand{
+		Name:        c.Name,
+		Description: c.Description,
+		Type:        discordgo.ChatApplicationCommand,
+	}
+	if c.RequiredPerms != 0 {
+		perms := c.RequiredPerms
+		appCmd.DefaultMemberPermissions = &perms
+	}
+	if !c.AllowDMs {
+		dmPerms := false
+		appCmd.DMPermission = &dmPerms
+	}
+	autocomplete := make(map[string]AutocompleteHandler)
+	for _, arg := range c.Arguments {
+		opt := arg.applicationCommandOption()
+		if opt.Autocomplete {
+			autocomplete[arg.Name] = arg.Autocomplete
+		}
+		appCmd.Options = append(appCmd.Options, opt)
+	}
+
+	return &ModuleApplicationCommand{
+		ApplicationCommand: appCmd,
+		Mod:                c.Mod,
+		Cooldown:           c.Cooldown,
+		CooldownScope:      c.CooldownScope,
+		CooldownMode:       c.CooldownMode,
+		CooldownUses:       c.CooldownUses,
+		UserType:           c.RequiresUserType,
+		CheckBotPerms:      c.CheckBotPerms,
+		Enabled:            c.Enabled,
+		Timeout:            c.Timeout,
+		Autocomplete:       autocomplete,
+		Run: func(dac *discord.DiscordApplicationCommand) error {
+			args, err := parseApplicationCommandArguments(c.Arguments, dac)
+			if err != nil {
+				return UsageError("%v", err)
+			}
+			return c.run(&interactionContext{it: dac, args: args})
+		},
+	}
+}
+
+// applicationCommandOption converts the argument to an application command option.
+func (a *CommandArgument) applicationCommandOption() *discordgo.ApplicationCommandOption {
+	opt := &discordgo.ApplicationCommandOption{
+		Name:        a.Name,
+		Description: a.Description,
+		Required:    a.Required,
+		Type:        discordgo.ApplicationCommandOptionString,
+	}
+	if opt.Description == "" {
+		opt.Description = a.Name
+	}
+
+	switch a.Type {
+	case ArgumentInt:
+		opt.Type = discordgo.ApplicationCommandOptionInteger
+		if a.HasMin {
+			minValue := float64(a.Min)
+			opt.MinValue = &minValue
+		}
+		if a.HasMax {
+			opt.MaxValue = float64(a.Max)
+		}
+	case ArgumentEnum:
+		for _, choice := range a.Choices {
+			opt.Choices = append(opt.Choices, &discordgo.ApplicationCommandOptionChoice{Name: choice, Value: choice})
+		}
+	case ArgumentMember, ArgumentUser:
+		opt.Type = discordgo.ApplicationCommandOptionUser
+	case ArgumentRole:
+		opt.Type = discordgo.ApplicationCommandOptionRole
+	case ArgumentChannel:
+		opt.Type = discordgo.ApplicationCommandOptionChannel
+	}
+	if a.Autocomplete != nil && len(opt.Choices) == 0 &&
+		(opt.Type == discordgo.ApplicationCommandOptionString || opt.Type == discordgo.ApplicationCommandOptionInteger) {
+		opt.Autocomplete = true
+	}
+	return opt
+}
+
+var errResolvedNotFound = errors.New("could not be resolved")
+
+// parseApplicationCommandArguments parses the options of an application command according
+// to an argument spec.
+func parseApplicationCommandArguments(spec []*CommandArgument, dac *discord.DiscordApplicationCommand) (*CommandArgs, error) {
+	args := newCommandArgs()
+	for _, arg := range spec {
+		opt, ok := dac.Options(arg.Name)
+		if !ok {
+			if arg.Required {
+				return nil, &ArgumentError{Arg: arg}
+			}
+			continue
+		}
+
+		var (
+			v   interface{}
+			err error
+		)
+		switch arg.Type {
+		case ArgumentInt:
+			v, err = parseValue(arg, strconv.FormatInt(opt.IntValue(), 10))
+		case ArgumentMember, ArgumentUser, ArgumentRole, ArgumentChannel:
+			v, err = resolveOption(arg, opt, dac.Data.Resolved)
+		default:
+			v, err = parseValue(arg, opt.StringValue())
+		}
+		if err != nil {
+			return nil, &ArgumentError{Arg: arg, Value: fmt.Sprint(opt.Value), Reason: err.Error()}
+		}
+		args.values[arg.Name] = v
+	}
+	return args, nil
+}
+
+func resolveOption(arg *CommandArgument, opt *discordgo.ApplicationCommandInteractionDataOption, resolved *discordgo.ApplicationCommandInteractionDataResolved) (interface{}, error) {
+	id, _ := opt.Value.(string)
+	if resolved == nil {
+		return nil, errResolvedNotFound
+	}
+
+	switch arg.Type {
+	case ArgumentMember:
+		member, ok := resolved.Members[id]
+		if !ok {
+			return nil, errors.New("member not found")
+		}
+		// resolved members do not include the user
+		member.User = resolved.Users[id]
+		return member, nil
+	case ArgumentUser:
+		if user, ok := resolved.Users[id]; ok {
+			return user, nil
+		}
+		return nil, errors.New("user not found")
+	case ArgumentRole:
+		if role, ok := resolved.Roles[id]; ok {
+			return role, nil
+		}
+		return nil, errors.New("role not found")
+	case ArgumentChannel:
+		if channel, ok := resolved.Channels[id]; ok {
+			return channel, nil
+		}
+		return nil, errors.New("channel not found")
+	}
+	return nil, errResolvedNotFound
+}
+
+// messageContext is a CommandContext for commands invoked by text triggers.
+type messageContext struct {
+	msg   *discord.DiscordMessage
+	args  *CommandArgs
+	reply *discordgo.Message
+}
+
+func (c *messageContext) Context() context.Context  { return c.msg.Context() }
+func (c *messageContext) Discord() *discord.Discord { return c.msg.Discord }
+func (c *messageContext) Author() *discordgo.User   { return c.msg.Author() }
+func (c *messageContext) AuthorID() string          { return c.msg.AuthorID() }
+func (c *messageContext) GuildID() string           { return c.msg.GuildID() }
+func (c *messageContext) ChannelID() string         { return c.msg.ChannelID() }
+func (c *messageContext) IsDM() bool                { return c.msg.IsDM() }
+func (c *messageContext) Args() *CommandArgs        { return c.args }
+
+func (c *messageContext) Reply(text string) error {
+	return c.ReplyComplex(&discordgo.MessageSend{Content: text})
+}
+
+func (c *messageContext) ReplyEmbed(embed *discordgo.MessageEmbed) error {
+	return c.ReplyComplex(&discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+func (c *messageContext) ReplyFile(text, name string, reader io.Reader) error {
+	return c.ReplyComplex(&discordgo.MessageSend{
+		Content: text,
+		Files:   []*discordgo.File{{Name: name, Reader: reader}},
+	})
+}
+
+func (c *messageContext) ReplyComplex(data *discordgo.MessageSend) error {
+	reply, err := c.msg.ReplyComplex(data)
+	if err != nil {
+		return err
+	}
+	if c.reply == nil {
+		c.reply = reply
+	}
+	return nil
+}
+
+func (c *messageContext) EditReply(text string) error {
+	if c.reply == nil {
+		return c.Reply(text)
+	}
+	_, err := c.msg.Sess.ChannelMessageEdit(c.reply.ChannelID, c.reply.ID, text)
+	return err
+}
+
+// interactionContext is a CommandContext for commands invoked by application commands.
+// The first reply responds to the interaction, while later ones are sent as follow-ups.
+type interactionContext struct {
+	it        *discord.DiscordApplicationCommand
+	args      *CommandArgs
+	responded bool
+}
+
+func (c *interactionContext) Context() context.Context  { return c.it.Context() }
+func (c *interactionContext) Discord() *discord.Discord { return c.it.Discord }
+func (c *interactionContext) AuthorID() string          { return c.it.AuthorID() }
+func (c *interactionContext) GuildID() string           { return c.it.GuildID() }
+func (c *interactionContext) ChannelID() string         { return c.it.ChannelID() }
+func (c *interactionContext) IsDM() bool                { return c.it.IsDM() }
+func (c *interactionContext) Args() *CommandArgs        { return c.args }
+
+func (c *interactionContext) Author() *discordgo.User {
+	if c.it.Interaction.Member != nil {
+		return c.it.Interaction.Member.User
+	}
+	return c.it.Interaction.User
+}
+
+func (c *interactionContext) Reply(text string) error {
+	return c.ReplyComplex(&discordgo.MessageSend{Content: text})
+}
+
+func (c *interactionContext) ReplyEmbed(embed *discordgo.MessageEmbed) error {
+	return c.ReplyComplex(&discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+func (c *interactionContext) ReplyFile(text, name string, reader io.Reader) error {
+	return c.ReplyComplex(&discordgo.MessageSend{
+		Content: text,
+		Files:   []*discordgo.File{{Name: name, Reader: reader}},
+	})
+}
+
+func (c *interactionContext) ReplyComplex(data *discordgo.MessageSend) error {
+	embeds := data.Embeds
+	if data.Embed != nil {
+		embeds = append(embeds, data.Embed)
+	}
+	files := data.Files
+	if data.File != nil {
+		files = append(files, data.File)
+	}
+
+	if c.responded {
+		_, err := c.it.Sess.FollowupMessageCreate(c.it.Interaction, true, &discordgo.WebhookParams{
+			Content:    data.Content,
+			Embeds:     embeds,
+			Components: data.Components,
+			Files:      files,
+		})
+		return err
+	}
+
+	err := c.it.RespondComplex(&discordgo.InteractionResponseData{
+		Content:    data.Content,
+		Embeds:     embeds,
+		Components: data.Components,
+		Files:      files,
+	}, discordgo.InteractionResponseChannelMessageWithSource)
+	if err != nil {
+		return err
+	}
+	c.responded = true
+	return nil
+}
+
+func (c *interactionContext) EditReply(text string) error {
+	if !c.responded {
+		return c.Reply(text)
+	}
+	_, err := c.it.Sess.InteractionResponseEdit(c.it.Interaction, &discordgo.WebhookEdit{Content: &text})
+	return err
+}
@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHybridCommand(mod Module, exec func(CommandContext)) *ModuleHybridCommand {
+	return NewModuleHybridCommandBuilder(mod, "test").
+		Description("testing").
+		Arguments(
+			&CommandArgument{Name: "amount", Type: ArgumentInt, Required: true, Min: 1, Max: 10, HasMin: true, HasMax: true},
+			&CommandArgument{Name: "mode", Type: ArgumentEnum, Choices: []string{"on", "off"}},
+			&CommandArgument{Name: "user", Type: ArgumentUser},
+		).
+		RequiredPerms(discordgo.PermissionManageMessages).
+		Execute(exec).
+		Build()
+}
+
+func TestModuleHybridCommand_TextCommand(t *testing.T) {
+	bot := NewTestBot()
+	require.NoError(t, bot.Discord.Sess.Open())
+	require.NoError(t, bot.Discord.Sess.State().ChannelAdd(&discordgo.Channel{ID: "1", Type: discordgo.ChannelTypeDM}))
+
+	var got CommandContext
+	hybrid := newTestHybridCommand(nil, func(ctx CommandContext) {
+		got = ctx
+		assert.NoError(t, ctx.Reply("hello"))
+	})
+	cmd := hybrid.TextCommand()
+	assert.Equal(t, "test", cmd.Name)
+	assert.Equal(t, []string{"test"}, cmd.Triggers)
+	assert.Equal(t, int64(discordgo.PermissionManageMessages), cmd.RequiredPerms)
+
+	msg := NewTestMessage(bot, "")
+	msg.Message.Content = "m?test 5 off"
+	args, err := cmd.parseArguments(msg)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(msg, args))
+
+	require.NotNil(t, got)
+	assert.Equal(t, 5, got.Args().Int("amount"))
+	assert.Equal(t, "off", got.Args().String("mode"))
+	assert.Equal(t, "jeff", got.Author().Username)
+	assert.NotNil(t, got.(*messageContext).reply, "first reply should be kept for EditReply")
+}
+
+func TestModuleHybridCommand_ApplicationCommand(t *testing.T) {
+	hybrid := newTestHybridCommand(nil, func(ctx CommandContext) {})
+	cmd := hybrid.ApplicationCommand()
+
+	assert.Equal(t, discordgo.ChatApplicationCommand, cmd.Type)
+	require.NotNil(t, cmd.DefaultMemberPermissions)
+	assert.Equal(t, int64(discordgo.PermissionManageMessages), *cmd.DefaultMemberPermissions)
+	require.NotNil(t, cmd.DMPermission)
+	assert.False(t, *cmd.DMPermission)
+
+	require.Len(t, cmd.Options, 3)
+	assert.Equal(t, discordgo.ApplicationCommandOptionInteger, cmd.Options[0].Type)
+	assert.True(t, cmd.Options[0].Required)
+	assert.Equal(t, float64(10), cmd.Options[0].MaxValue)
+	assert.Equal(t, discordgo.ApplicationCommandOptionString, cmd.Options[1].Type)
+	assert.Len(t, cmd.Options[1].Choices, 2)
+	assert.Equal(t, discordgo.ApplicationCommandOptionUser, cmd.Options[2].Type)
+}
+
+func TestParseApplicationCommandArguments(t *testing.T) {
+	hybrid := newTestHybridCommand(nil, func(ctx CommandContext) {})
+	dac := &discord.DiscordApplicationCommand{
+		DiscordInteraction: &discord.DiscordInteraction{Interaction: &discordgo.Interaction{}},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "test",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "amount", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(3)},
+				{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "30"},
+			},
+			Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+				Users: map[string]*discordgo.User{"30": {ID: "30"}},
+			},
+		},
+	}
+
+	args, err := parseApplicationCommandArguments(hybrid.Arguments, dac)
+	require.NoError(t, err)
+	assert.Equal(t, 3, args.Int("amount"))
+	assert.False(t, args.Has("mode"))
+	assert.Equal(t, "30", args.User("user").ID)
+
+	dac.Data.Options[0].Value = float64(11)
+	dac = &discord.DiscordApplicationCommand{DiscordInteraction: dac.DiscordInteraction, Data: dac.Data}
+	_, err = parseApplicationCommandArguments(hybrid.Arguments, dac)
+	assert.Error(t, err, "amount is out of range")
+}
+
+func TestModuleBase_RegisterHybridCommands(t *testing.T) {
+	base := NewModule(nil, "testing", mio.NewDiscardLogger())
+	hybrid := newTestHybridCommand(nil, func(ctx CommandContext) {})
+	require.NoError(t, base.RegisterHybridCommands(hybrid))
+
+	assert.Len(t, base.Commands(), 1)
+	assert.Len(t, base.ApplicationCommands(), 1)
+	assert.Error(t, base.RegisterHybridCommands(hybrid), "duplicate registration should fail")
+}
+
+func TestModuleHybridCommand_Autocomplete(t *testing.T) {
+	complete := func(*discord.DiscordAutocomplete) {}
+	hybrid := NewModuleHybridCommandBuilder(nil, "test").
+		Description("testing").
+		Arguments(
+			&CommandArgument{Name: "query", Type: ArgumentRest, Autocomplete: complete},
+			&CommandArgument{Name: "mode", Type: ArgumentEnum, Choices: []string{"on", "off"}, Autocomplete: complete},
+		).
+		Execute(func(CommandContext) {}).
+		Build()
+	cmd := hybrid.ApplicationCommand()
+
+	assert.True(t, cmd.Options[0].Autocomplete)
+	assert.False(t, cmd.Options[1].Autocomplete, "options with choices cannot autocomplete")
+	assert.Contains(t, cmd.Autocomplete, "query")
+	assert.NotContains(t, cmd.Autocomplete, "mode")
+}
@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ScheduledJob is a one-shot job, kept in a JobStore until it has run.
+type ScheduledJob struct {
+	ID      int64
+	Kind    string
+	Payload []byte
+	RunAt   time.Time
+	// Attempts is how many times the job has failed, and LastError why it did last.
+	Attempts  int
+	LastError string
+	// Failed is set once the job has used up its attempts. It is not run again.
+	Failed bool
+	// ClaimedBy is the scheduler running the job, until ClaimedUntil.
+	ClaimedBy    string
+	ClaimedUntil time.Time
+}
+
+// Decode decodes the payload of the job into v.
+func (j *ScheduledJob) Decode(v any) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// JobStore keeps the one-shot jobs of a Scheduler, and the claims on the runs of
+// its recurring jobs.
+type JobStore interface {
+	// AddJob saves job, and sets its ID.
+	AddJob(job *ScheduledJob) error
+	// ClaimJobs claims up to limit jobs that are due at now, and are not failed or
+	// claimed already, for claimer until until. A claimed job is not returned again
+	// before its claim runs out, which keeps jobs from running twice at once, even
+	// with several schedulers on the same store.
+	ClaimJobs(now, until time.Time, claimer string, limit int) ([]*ScheduledJob, error)
+	// CompleteJob, RetryJob and FailJob only change a job that is still claimed by
+	// claimer, and return ErrJobClaimLost otherwise, as a claim that ran out may
+	// have been taken by another scheduler since.
+	//
+	// CompleteJob removes a job that has run.
+	CompleteJob(id int64, claimer string) error
+	// RetryJob releases the claim on a job that failed, so it runs again at runAt.
+	RetryJob(id int64, claimer string, runAt time.Time, lastErr string) error
+	// FailJob marks a job as failed. It is kept, so it can be inspected.
+	FailJob(id int64, claimer, lastErr string) error
+	// CancelJob removes a job. It returns ErrJobNotFound if there is no such job.
+	CancelJob(id int64) error
+	// Jobs returns every job that has not completed, ordered by when they run.
+	Jobs() ([]*ScheduledJob, error)
+	// ClaimRun claims the run of the recurring job name that is due at due for
+	// claimer. It returns false if that run, or a later one, was claimed already,
+	// so each run happens once, even with several schedulers on the same store.
+	ClaimRun(name string, due time.Time, claimer string) (bool, error)
+}
+
+// MemoryJobStore is a JobStore that keeps jobs in memory, so they are lost when
+// the bot restarts.
+type MemoryJobStore struct {
+	sync.Mutex
+	jobs   map[int64]*ScheduledJob
+	nextID int64
+	// runs holds when the last claimed run of each recurring job was due.
+	runs map[string]time.Time
+}
+
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[int64]*ScheduledJob), runs: make(map[string]time.Time)}
+}
+
+func (s *MemoryJobStore) AddJob(job *ScheduledJob) error {
+	s.Lock()
+	defer s.Unlock()
+	s.nextID++
+	job.ID = s.nextID
+	j := *job
+	s.jobs[j.ID] = &j
+	return nil
+}
+
+func (s *MemoryJobStore) ClaimJobs(now, until time.Time, claimer string, limit int) ([]*ScheduledJob, error) {
+	s.Lock()
+	defer s.Unlock()
+	var due []*ScheduledJob
+	for _, j := range s.jobs {
+		if !j.Failed && !j.RunAt.After(now) && j.ClaimedUntil.Before(now) {
+			due = append(due, j)
+		}
+	}
+	sortJobs(due)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*ScheduledJob, 0, len(due))
+	for _, j := range due {
+		j.ClaimedBy, j.ClaimedUntil = claimer, until
+		c := *j
+		claimed = append(claimed, &c)
+	}
+	return claimed, nil
+}
+
+// claimed returns the job id if it is claimed by claimer. The lock must be held.
+func (s *MemoryJobStore) claimed(id int64, claimer string) (*ScheduledJob, error) {
+	j, ok := s.jobs[id]
+	if !ok || j.ClaimedBy != claimer {
+		return nil, ErrJobClaimLost
+	}
+	return j, nil
+}
+
+func (s *MemoryJobStore) CompleteJob(id int64, claimer string) error {
+	s.Lock()
+	defer s.Unlock()
+	if _, err := s.claimed(id, claimer); err != nil {
+		return err
+	}
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *MemoryJobStore) RetryJob(id int64, claimer string, runAt time.Time, lastErr string) error {
+	s.Lock()
+	defer s.Unlock()
+	j, err := s.claimed(id, claimer)
+	if err != nil {
+		return err
+	}
+	j.RunAt = runAt
+	j.Attempts++
+	j.LastError = lastErr
+	j.ClaimedBy, j.ClaimedUntil = "", time.Time{}
+	return nil
+}
+
+func (s *MemoryJobStore) FailJob(id int64, claimer, lastErr string) error {
+	s.Lock()
+	defer s.Unlock()
+	j, err := s.claimed(id, claimer)
+	if err != nil {
+		return err
+	}
+	j.Failed = true
+	j.Attempts++
+	j.LastError = lastErr
+	j.ClaimedBy, j.ClaimedUntil = "", time.Time{}
+	return nil
+}
+
+func (s *MemoryJobStore) CancelJob(id int64) error {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return ErrJobNotFound
+	}
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *MemoryJobStore) Jobs() ([]*ScheduledJob, error) {
+	s.Lock()
+	defer s.Unlock()
+	jobs := make([]*ScheduledJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		c := *j
+		jobs = append(jobs, &c)
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+func (s *MemoryJobStore) ClaimRun(name string, due time.Time, claimer string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	if last, ok := s.runs[name]; ok && !due.After(last) {
+		return false, nil
+	}
+	s.runs[name] = due
+	return true, nil
+}
+
+func sortJobs(jobs []*ScheduledJob) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].RunAt.Equal(jobs[j].RunAt) {
+			return jobs[i].ID < jobs[j].ID
+		}
+		return jobs[i].RunAt.Before(jobs[j].RunAt)
+	})
+}
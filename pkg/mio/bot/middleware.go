@@ -0,0 +1,161 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+)
+
+// InvocationType is the kind of handler an Invocation runs.
+type InvocationType int
+
+const (
+	InvocationCommand InvocationType = 1 << iota
+	InvocationPassive
+	InvocationApplicationCommand
+	InvocationMessageComponent
+	InvocationModalSubmit
+)
+
+func (t InvocationType) String() string {
+	switch t {
+	case InvocationCommand:
+		return "command"
+	case InvocationPassive:
+		return "passive"
+	case InvocationApplicationCommand:
+		return "application_command"
+	case InvocationMessageComponent:
+		return "message_component"
+	case InvocationModalSubmit:
+		return "modal_submit"
+	default:
+		return "unknown"
+	}
+}
+
+// Invocation describes a single run of a handler, as seen by middleware. Only the
+// fields belonging to its Type are set.
+type Invocation struct {
+	Type   InvocationType
+	Module Module
+	Name   string
+
+	// Message is set for commands and passives.
+	Message *discord.DiscordMessage
+	// Interaction is set for application commands, message components and modal submits.
+	Interaction *discord.DiscordInteraction
+
+	Command            *ModuleCommand
+	Args               *CommandArgs
+	Passive            *ModulePassive
+	ApplicationCommand *ModuleApplicationCommand
+	MessageComponent   *ModuleMessageComponent
+	ComponentArgs      *ComponentArgs
+	ModalSubmit        *ModuleModalSubmit
+}
+
+// Context returns the context of the message or interaction being handled.
+func (i *Invocation) Context() context.Context {
+	if i.Message != nil {
+		return i.Message.Context()
+	}
+	return i.Interaction.Context()
+}
+
+func (i *Invocation) AuthorID() string {
+	if i.Message != nil {
+		return i.Message.AuthorID()
+	}
+	return i.Interaction.AuthorID()
+}
+
+func (i *Invocation) GuildID() string {
+	if i.Message != nil {
+		return i.Message.GuildID()
+	}
+	return i.Interaction.GuildID()
+}
+
+func (i *Invocation) ChannelID() string {
+	if i.Message != nil {
+		return i.Message.ChannelID()
+	}
+	return i.Interaction.ChannelID()
+}
+
+// HandlerFunc runs an Invocation.
+type HandlerFunc func(*Invocation)
+
+// Middleware wraps a HandlerFunc. A middleware short-circuits the chain by
+// returning without calling next, in which case the handler is not executed.
+//
+// Middleware runs in the goroutine of the handler, after the built-in checks
+// such as permissions have passed. Cooldowns are used after the middleware, right
+// before the handler, so a middleware that short-circuits does not use them up.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// chainMiddleware wraps h in mws, so the first middleware is the outermost one.
+func chainMiddleware(h HandlerFunc, mws ...[]Middleware) HandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		for j := len(mws[i]) - 1; j >= 0; j-- {
+			h = mws[i][j](h)
+		}
+	}
+	return h
+}
+
+// Use adds global middleware, which runs for every handler of every module,
+// before any module middleware.
+func (b *Bot) Use(mws ...Middleware) {
+	b.Lock()
+	defer b.Unlock()
+	b.middleware = append(b.middleware, mws...)
+}
+
+func (b *Bot) globalMiddleware() []Middleware {
+	b.Lock()
+	defer b.Unlock()
+	return append([]Middleware(nil), b.middleware...)
+}
+
+// Use adds middleware which runs for every handler in the module.
+func (m *ModuleBase) Use(mws ...Middleware) {
+	m.Lock()
+	defer m.Unlock()
+	m.middleware = append(m.middleware, mws...)
+}
+
+func (m *ModuleBase) moduleMiddleware() []Middleware {
+	m.Lock()
+	defer m.Unlock()
+	return append([]Middleware(nil), m.middleware...)
+}
+
+// invoke runs h through the global and module middleware chains. A CommandTimedOut
+// event is emitted if the context of inv times out before h returns.
+func (m *ModuleBase) invoke(inv *Invocation, timeout time.Duration, h HandlerFunc) {
+	ctx := inv.Context()
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			m.Bot.Emit(&CommandTimedOut{inv, timeout})
+		}
+	})
+	defer stop()
+	chainMiddleware(h, m.Bot.globalMiddleware(), m.moduleMiddleware())(inv)
+}
+
+// DefaultTimeout is the deadline of handlers that do not set their own.
+const DefaultTimeout = time.Minute * 5
+
+// handlerContext returns a context derived from the bot context, which times out
+// after timeout, or DefaultTimeout if timeout is 0.
+func (m *ModuleBase) handlerContext(timeout time.Duration) (context.Context, context.CancelFunc, time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(m.Bot.Context(), timeout)
+	return ctx, cancel, timeout
+}
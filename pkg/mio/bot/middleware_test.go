@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordMiddleware(name string, calls *[]string, mu *sync.Mutex) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(inv *Invocation) {
+			mu.Lock()
+			*calls = append(*calls, name)
+			mu.Unlock()
+			next(inv)
+		}
+	}
+}
+
+func TestModuleBase_Middleware(t *testing.T) {
+	t.Run("global runs before module", func(t *testing.T) {
+		bot := NewTestBot()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		var (
+			mu    sync.Mutex
+			calls []string
+		)
+		done := make(chan *Invocation, 1)
+		bot.Use(recordMiddleware("global", &calls, &mu))
+		mod.Use(recordMiddleware("module", &calls, &mu))
+
+		cmd := NewTestCommand(mod)
+		cmd.Execute = func(*discord.DiscordMessage) {}
+		mod.Use(func(next HandlerFunc) HandlerFunc {
+			return func(inv *Invocation) {
+				next(inv)
+				done <- inv
+			}
+		})
+		_ = mod.RegisterCommands(cmd)
+		mod.HandleMessage(NewTestMessage(bot, "1"))
+
+		select {
+		case inv := <-done:
+			assert.Equal(t, InvocationCommand, inv.Type)
+			assert.Equal(t, "test", inv.Name)
+			assert.Equal(t, cmd, inv.Command)
+		case <-time.After(time.Second):
+			t.Fatal("middleware was not called")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{"global", "module"}, calls)
+	})
+
+	t.Run("short-circuit skips execution", func(t *testing.T) {
+		bot := NewTestBot()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		blocked := make(chan bool, 1)
+		bot.Use(func(next HandlerFunc) HandlerFunc {
+			return func(inv *Invocation) {
+				if inv.Type == InvocationCommand {
+					blocked <- true
+					return
+				}
+				next(inv)
+			}
+		})
+		cmdCalled := make(chan bool, 1)
+		cmd := NewTestCommand(mod)
+		cmd.Execute = func(*discord.DiscordMessage) {
+			cmdCalled <- true
+		}
+		_ = mod.RegisterCommands(cmd)
+		mod.HandleMessage(NewTestMessage(bot, "1"))
+
+		select {
+		case <-blocked:
+		case <-time.After(time.Second):
+			t.Fatal("middleware was not called")
+		}
+		select {
+		case <-cmdCalled:
+			t.Errorf("Command was not expected to be called")
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
+
+	t.Run("short-circuit does not use the cooldown", func(t *testing.T) {
+		bot := NewTestBot()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		blocked := make(chan bool, 1)
+		bot.Use(func(next HandlerFunc) HandlerFunc {
+			return func(inv *Invocation) {
+				blocked <- true
+			}
+		})
+		cmd := NewTestCommand(mod)
+		cmd.Cooldown = time.Minute
+		cmd.Execute = func(*discord.DiscordMessage) {}
+		_ = mod.RegisterCommands(cmd)
+		msg := NewTestMessage(bot, "1")
+		mod.HandleMessage(msg)
+
+		select {
+		case <-blocked:
+		case <-time.After(time.Second):
+			t.Fatal("middleware was not called")
+		}
+		_, ok, err := bot.Cooldowns.Take(cmd.CooldownKey(msg), cmd.CooldownLimit())
+		assert.NoError(t, err)
+		assert.True(t, ok, "cooldown was used by a command that did not run")
+	})
+
+	t.Run("runs for interactions", func(t *testing.T) {
+		bot := NewTestBot()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		done := make(chan *Invocation, 1)
+		mod.Use(func(next HandlerFunc) HandlerFunc {
+			return func(inv *Invocation) {
+				done <- inv
+			}
+		})
+		_ = mod.RegisterModalSubmits(NewTestModalSubmit(mod))
+		mod.SetModalSubmitCallback("test", "test")
+		mod.HandleInteraction(NewTestModalSubmitInteraction(bot, "1", "test"))
+
+		select {
+		case inv := <-done:
+			assert.Equal(t, InvocationModalSubmit, inv.Type)
+			assert.NotNil(t, inv.Interaction)
+		case <-time.After(time.Second):
+			t.Fatal("middleware was not called")
+		}
+	})
+}
@@ -37,6 +37,10 @@ type ModuleInfo interface {
 
 type MessageHandler interface {
 	HandleMessage(*discord.DiscordMessage)
+	// HandlePassives runs the passives of the module for a message.
+	HandlePassives(*discord.DiscordMessage)
+	// HandleCommand runs cmd, which belongs to the module, for a message.
+	HandleCommand(*ModuleCommand, *discord.DiscordMessage)
 }
 
 type InteractionHandler interface {
@@ -46,6 +50,7 @@ type InteractionHandler interface {
 type CommandHandler interface {
 	Commands() map[string]*ModuleCommand
 	RegisterCommands(...*ModuleCommand) error
+	RegisterHybridCommands(...*ModuleHybridCommand) error
 	FindCommand(name string) (*ModuleCommand, error)
 }
 
@@ -101,11 +106,15 @@ type ModuleBase struct {
 	applicationCommands map[string]*ModuleApplicationCommand
 	modalSubmits        map[string]*ModuleModalSubmit
 	messageComponents   map[string]*ModuleMessageComponent
+	pages               map[string]*ModulePages
 
 	messageComponentCallbacks map[string]*ModuleMessageComponent
 	modalSubmitCallbacks      map[string]*ModuleModalSubmit
 
 	applicationCommandStructs []*discordgo.ApplicationCommand
+
+	triggers   *commandTrie
+	middleware []Middleware
 }
 
 func NewModule(bot *Bot, name string, logger mio.Logger) *ModuleBase {
@@ -120,9 +129,11 @@ func NewModule(bot *Bot, name string, logger mio.Logger) *ModuleBase {
 		applicationCommands:       make(map[string]*ModuleApplicationCommand),
 		modalSubmits:              make(map[string]*ModuleModalSubmit),
 		messageComponents:         make(map[string]*ModuleMessageComponent),
+		pages:                     make(map[string]*ModulePages),
 		messageComponentCallbacks: make(map[string]*ModuleMessageComponent),
 		modalSubmitCallbacks:      make(map[string]*ModuleModalSubmit),
 		applicationCommandStructs: make([]*discordgo.ApplicationCommand, 0),
+		triggers:                  newCommandTrie(),
 	}
 }
 
@@ -138,22 +149,34 @@ func (m *ModuleBase) AllowDMs() bool {
 	return m.allowDMs
 }
 
+// HandleMessage runs the passives of the module, and the command of the module
+// the message triggers, if any.
 func (m *ModuleBase) HandleMessage(msg *discord.DiscordMessage) {
-	if !m.allowsMessage(msg) {
+	m.HandlePassives(msg)
+
+	if msg.Prefix == "" || len(msg.Args()) <= 0 {
 		return
 	}
 
-	for _, pas := range m.Passives() {
-		m.handlePassive(pas, msg)
+	if cmd, err := m.findCommandByTriggers(msg.CommandContent()); err == nil {
+		m.HandleCommand(cmd, msg)
 	}
+}
 
-	if len(msg.Args()) <= 0 {
+func (m *ModuleBase) HandlePassives(msg *discord.DiscordMessage) {
+	if !m.allowsMessage(msg) {
 		return
 	}
+	for _, pas := range m.Passives() {
+		m.handlePassive(pas, msg)
+	}
+}
 
-	if cmd, err := m.findCommandByTriggers(msg.RawContent()); err == nil {
-		m.handleCommand(cmd, msg)
+func (m *ModuleBase) HandleCommand(cmd *ModuleCommand, msg *discord.DiscordMessage) {
+	if !m.allowsMessage(msg) {
+		return
 	}
+	m.handleCommand(cmd, msg)
 }
 
 func (m *ModuleBase) allowsMessage(msg *discord.DiscordMessage) bool {
@@ -167,7 +190,11 @@ func (m *ModuleBase) allowsMessage(msg *discord.DiscordMessage) bool {
 }
 
 func (m *ModuleBase) handleCommand(cmd *ModuleCommand, msg *discord.DiscordMessage) {
-	if !cmd.Enabled || !cmd.allowsMessage(msg) {
+	if !cmd.Enabled {
+		return
+	}
+	decision := m.Bot.permissionDecision(msg.GuildID(), cmd.Name, msg.ChannelID(), msg.Member())
+	if !cmd.allowsMessage(msg, decision) {
 		return
 	}
 
@@ -176,14 +203,17 @@ func (m *ModuleBase) handleCommand(cmd *ModuleCommand, msg *discord.DiscordMessa
 		return
 	}
 
-	if cdKey := cmd.CooldownKey(msg); cdKey != "" {
-		if t, ok := m.Bot.Cooldowns.Check(cdKey); ok {
-			_, _ = msg.ReplyAndDelete(fmt.Sprintf("This command is on cooldown for another %v", t), time.Second*2)
-			return
-		}
-		m.Bot.Cooldowns.Set(cdKey, time.Duration(cmd.Cooldown))
+	args, err := cmd.parseArguments(msg)
+	if err != nil {
+		usage := FormatUsage(cmd.ArgumentUsage(), m.Bot.Prefixes.Prefix(msg.GuildID()))
+		_, _ = msg.Reply(fmt.Sprintf("%v\nUsage: `%v`", err, usage))
+		return
 	}
-	go m.runCommand(cmd, msg)
+
+	if !m.Bot.startHandler() {
+		return
+	}
+	m.Bot.dispatch(msg.Shard, func() { m.runCommand(cmd, msg, args) })
 }
 
 func (m *ModuleBase) recoverCommand(cmd *ModuleCommand, msg *discord.DiscordMessage) {
@@ -193,17 +223,42 @@ func (m *ModuleBase) recoverCommand(cmd *ModuleCommand, msg *discord.DiscordMess
 	}
 }
 
-func (m *ModuleBase) runCommand(cmd *ModuleCommand, msg *discord.DiscordMessage) {
+func (m *ModuleBase) runCommand(cmd *ModuleCommand, msg *discord.DiscordMessage, args *CommandArgs) {
+	defer m.Bot.handlerDone()
 	defer m.recoverCommand(cmd, msg)
-	m.Bot.Emit(&CommandRan{cmd, msg})
-	cmd.Execute(msg)
+	ctx, cancel, timeout := m.handlerContext(cmd.Timeout)
+	defer cancel()
+	msg = msg.WithContext(ctx)
+	inv := &Invocation{Type: InvocationCommand, Module: cmd.Mod, Name: cmd.Name, Message: msg, Command: cmd, Args: args}
+	m.invoke(inv, timeout, func(inv *Invocation) {
+		// the cooldown is used last, so middleware that stops the command does not use it up
+		if t, ok := m.takeCooldown(cmd.CooldownKey(msg), cmd.CooldownLimit()); !ok {
+			_, _ = msg.ReplyAndDelete(fmt.Sprintf("This command is on cooldown for another %v", t), time.Second*2)
+			return
+		}
+		m.Bot.Emit(&CommandRan{cmd, msg})
+		if cmd.Run != nil {
+			if err := cmd.Run(msg, inv.Args); err != nil {
+				m.commandFailed(inv, err)
+			}
+			return
+		}
+		if cmd.ExecuteArgs != nil {
+			cmd.ExecuteArgs(msg, inv.Args)
+			return
+		}
+		cmd.Execute(msg)
+	})
 }
 
 func (m *ModuleBase) handlePassive(pas *ModulePassive, msg *discord.DiscordMessage) {
-	if !pas.Enabled || !pas.allowsMessage(msg) {
+	if !pas.Enabled || !pas.allowsMessage(msg) || m.Bot.IsDisabled(msg.GuildID(), TogglePassive, pas.Name) {
+		return
+	}
+	if !m.Bot.startHandler() {
 		return
 	}
-	go m.runPassive(pas, msg)
+	m.Bot.dispatch(msg.Shard, func() { m.runPassive(pas, msg) })
 }
 
 func (m *ModuleBase) recoverPassive(pas *ModulePassive, msg *discord.DiscordMessage) {
@@ -214,9 +269,16 @@ func (m *ModuleBase) recoverPassive(pas *ModulePassive, msg *discord.DiscordMess
 }
 
 func (m *ModuleBase) runPassive(pas *ModulePassive, msg *discord.DiscordMessage) {
+	defer m.Bot.handlerDone()
 	defer m.recoverPassive(pas, msg)
-	m.Bot.Emit(&PassiveRan{pas, msg})
-	pas.Execute(msg)
+	ctx, cancel, timeout := m.handlerContext(pas.Timeout)
+	defer cancel()
+	msg = msg.WithContext(ctx)
+	inv := &Invocation{Type: InvocationPassive, Module: pas.Mod, Name: pas.Name, Message: msg, Passive: pas}
+	m.invoke(inv, timeout, func(*Invocation) {
+		m.Bot.Emit(&PassiveRan{pas, msg})
+		pas.Execute(msg)
+	})
 }
 
 func (m *ModuleBase) HandleInteraction(it *discord.DiscordInteraction) {
@@ -233,13 +295,35 @@ func (m *ModuleBase) HandleInteraction(it *discord.DiscordInteraction) {
 				Data:               data,
 			})
 		}
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		data := it.Interaction.ApplicationCommandData()
+		if cmd, err := m.FindApplicationCommand(data.Name); err == nil {
+			m.handleAutocomplete(cmd, &discord.DiscordAutocomplete{
+				DiscordApplicationCommand: &discord.DiscordApplicationCommand{
+					DiscordInteraction: it,
+					Data:               data,
+				},
+			})
+		}
 	case discordgo.InteractionModalSubmit:
 		data := it.Interaction.ModalSubmitData()
-		if cmd, ok := m.modalSubmitCallbacks[data.CustomID]; ok {
-			m.handleModalSubmit(cmd, &discord.DiscordModalSubmit{
-				DiscordInteraction: it,
-				Data:               data,
-			})
+		dms := &discord.DiscordModalSubmit{
+			DiscordInteraction: it,
+			Data:               data,
+		}
+
+		if cmd, ok := m.modalSubmitCallback(data.CustomID); ok {
+			m.handleModalSubmit(cmd, dms, nil)
+		} else if args, err := m.Bot.CustomIDs.Decode(data.CustomID); err == nil {
+			cmd, err := m.FindModalSubmit(args.Name)
+			if err != nil {
+				return
+			}
+			if args.Version != cmd.Version {
+				_ = dms.RespondEphemeral("This has expired, please try again")
+				return
+			}
+			m.handleModalSubmit(cmd, dms, args)
 		}
 	case discordgo.InteractionMessageComponent:
 		data := it.Interaction.MessageComponentData()
@@ -248,16 +332,20 @@ func (m *ModuleBase) HandleInteraction(it *discord.DiscordInteraction) {
 			Data:               data,
 		}
 
-		if cmd, ok := m.messageComponentCallbacks[data.CustomID]; ok {
-			m.handleMessageComponent(cmd, dmc)
-		} else if cmd, err := m.FindMessageComponent(data.CustomID); err == nil {
-			m.handleMessageComponent(cmd, dmc)
-		} else {
-			if parts := strings.Split(data.CustomID, ":"); len(parts) > 1 {
-				if cmd, err := m.FindMessageComponent(parts[0]); err == nil {
-					m.handleMessageComponent(cmd, dmc)
-				}
+		if cmd, ok := m.messageComponentCallback(data.CustomID); ok {
+			m.handleMessageComponent(cmd, dmc, nil)
+		} else if args, err := m.Bot.CustomIDs.Decode(data.CustomID); err == nil {
+			cmd, err := m.FindMessageComponent(args.Name)
+			if err != nil {
+				return
 			}
+			if args.Version != cmd.Version {
+				_ = dmc.RespondEphemeral("This has expired, please try again")
+				return
+			}
+			m.handleMessageComponent(cmd, dmc, args)
+		} else if cmd, err := m.FindMessageComponent(data.CustomID); err == nil {
+			m.handleMessageComponent(cmd, dmc, nil)
 		}
 	}
 }
@@ -267,10 +355,36 @@ func (m *ModuleBase) allowsInteraction(it *discord.DiscordInteraction) bool {
 }
 
 func (m *ModuleBase) handleApplicationCommand(c *ModuleApplicationCommand, it *discord.DiscordApplicationCommand) {
-	if !c.Enabled || !c.allowsInteraction(it) {
+	if !c.Enabled {
+		return
+	}
+	if m.Bot.IsDisabled(it.GuildID(), ToggleCommand, c.Name) {
+		_ = it.RespondEphemeral("This command is disabled in this server")
+		return
+	}
+	if c.UserType == UserTypeBotOwner && !m.Bot.IsOwner(it.AuthorID()) {
+		_ = it.RespondEphemeral("This command is owner only")
+		return
+	}
+	decision := m.Bot.permissionDecision(it.GuildID(), c.Name, it.ChannelID(), it.Interaction.Member)
+	if !c.allowsInteraction(it, decision) {
+		_ = it.RespondEphemeral("Missing permissions to use this command")
+		return
+	}
+	if !m.Bot.startHandler() {
 		return
 	}
-	go m.runApplicationCommand(c, it)
+	m.Bot.dispatch(it.Shard, func() { m.runApplicationCommand(c, it) })
+}
+
+// checkInteractionCooldown returns whether an interaction is off cooldown, and
+// uses the cooldown if it is. Interactions on cooldown get an ephemeral reply.
+func (m *ModuleBase) checkInteractionCooldown(it *discord.DiscordInteraction, cdKey string, limit CooldownLimit) bool {
+	if t, ok := m.takeCooldown(cdKey, limit); !ok {
+		_ = it.RespondEphemeral(fmt.Sprintf("This is on cooldown for another %v", t))
+		return false
+	}
+	return true
 }
 
 func (m *ModuleBase) recoverApplicationCommand(c *ModuleApplicationCommand, it *discord.DiscordApplicationCommand) {
@@ -280,16 +394,85 @@ func (m *ModuleBase) recoverApplicationCommand(c *ModuleApplicationCommand, it *
 }
 
 func (m *ModuleBase) runApplicationCommand(c *ModuleApplicationCommand, it *discord.DiscordApplicationCommand) {
+	defer m.Bot.handlerDone()
 	defer m.recoverApplicationCommand(c, it)
-	m.Bot.Emit(&ApplicationCommandRan{c, it})
-	c.Execute(it)
+	ctx, cancel, timeout := m.handlerContext(c.Timeout)
+	defer cancel()
+	it.DiscordInteraction = it.DiscordInteraction.WithContext(ctx)
+	if c.AutoDefer > 0 {
+		// Discord's deadline counts from when the interaction was received, not
+		// from when it got to run
+		after := c.AutoDefer
+		if !it.TimeReceived.IsZero() {
+			after -= time.Since(it.TimeReceived)
+		}
+		if after <= 0 {
+			_ = it.Defer(c.DeferEphemeral)
+		} else {
+			deferTimer := time.AfterFunc(after, func() { _ = it.Defer(c.DeferEphemeral) })
+			defer deferTimer.Stop()
+		}
+	}
+	inv := &Invocation{Type: InvocationApplicationCommand, Module: c.Mod, Name: c.Name, Interaction: it.DiscordInteraction, ApplicationCommand: c}
+	m.invoke(inv, timeout, func(inv *Invocation) {
+		if !m.checkInteractionCooldown(it.DiscordInteraction, c.CooldownKey(it.DiscordInteraction), c.CooldownLimit()) {
+			return
+		}
+		m.Bot.Emit(&ApplicationCommandRan{c, it})
+		if err := c.execute(it); err != nil {
+			m.commandFailed(inv, err)
+		}
+	})
+}
+
+// handleAutocomplete runs the autocomplete handler of the focused option. Options
+// without a handler, and commands that cannot be used, get no choices.
+func (m *ModuleBase) handleAutocomplete(c *ModuleApplicationCommand, it *discord.DiscordAutocomplete) {
+	key, _, ok := it.Focused()
+	handler, found := c.Autocomplete[key]
+	if !ok || !found || !c.Enabled || m.Bot.IsDisabled(it.GuildID(), ToggleCommand, c.Name) {
+		_ = it.RespondChoices(nil)
+		return
+	}
+	if !m.Bot.startHandler() {
+		return
+	}
+	m.Bot.dispatch(it.Shard, func() { m.runAutocomplete(c, key, handler, it) })
 }
 
-func (m *ModuleBase) handleMessageComponent(c *ModuleMessageComponent, it *discord.DiscordMessageComponent) {
-	if !c.Enabled || !c.allowsInteraction(it) {
+func (m *ModuleBase) runAutocomplete(c *ModuleApplicationCommand, key string, handler AutocompleteHandler, it *discord.DiscordAutocomplete) {
+	defer m.Bot.handlerDone()
+	defer func() {
+		if r := recover(); r != nil {
+			m.Logger.Error("Autocomplete panicked", "command", c.Name, "option", key, "reason", r)
+		}
+	}()
+	ctx, cancel, _ := m.handlerContext(c.Timeout)
+	defer cancel()
+	it.DiscordInteraction = it.DiscordInteraction.WithContext(ctx)
+	handler(it)
+}
+
+func (m *ModuleBase) handleMessageComponent(c *ModuleMessageComponent, it *discord.DiscordMessageComponent, args *ComponentArgs) {
+	if !c.Enabled {
+		return
+	}
+	if c.UserType == UserTypeBotOwner && !m.Bot.IsOwner(it.AuthorID()) {
+		_ = it.RespondEphemeral("This is owner only")
+		return
+	}
+	decision := m.Bot.permissionDecision(it.GuildID(), c.Command, it.ChannelID(), it.Interaction.Member)
+	if !c.allowsInteraction(it, decision) {
+		_ = it.RespondEphemeral("Missing permissions to use this")
 		return
 	}
-	go m.runMessageComponent(c, it)
+	if !m.Bot.startHandler() {
+		return
+	}
+	if args == nil {
+		args = &ComponentArgs{Name: c.Name, Version: c.Version}
+	}
+	m.Bot.dispatch(it.Shard, func() { m.runMessageComponent(c, it, args) })
 }
 
 func (m *ModuleBase) recoverMessageComponent(c *ModuleMessageComponent, it *discord.DiscordMessageComponent) {
@@ -298,17 +481,42 @@ func (m *ModuleBase) recoverMessageComponent(c *ModuleMessageComponent, it *disc
 	}
 }
 
-func (m *ModuleBase) runMessageComponent(c *ModuleMessageComponent, it *discord.DiscordMessageComponent) {
+func (m *ModuleBase) runMessageComponent(c *ModuleMessageComponent, it *discord.DiscordMessageComponent, args *ComponentArgs) {
+	defer m.Bot.handlerDone()
 	defer m.recoverMessageComponent(c, it)
-	m.Bot.Emit(&MessageComponentRan{c, it})
-	c.Execute(it)
+	ctx, cancel, timeout := m.handlerContext(c.Timeout)
+	defer cancel()
+	it.DiscordInteraction = it.DiscordInteraction.WithContext(ctx)
+	inv := &Invocation{Type: InvocationMessageComponent, Module: c.Mod, Name: c.Name, Interaction: it.DiscordInteraction, MessageComponent: c, ComponentArgs: args}
+	m.invoke(inv, timeout, func(inv *Invocation) {
+		if !m.checkInteractionCooldown(it.DiscordInteraction, c.CooldownKey(it.DiscordInteraction), c.CooldownLimit()) {
+			return
+		}
+		m.Bot.Emit(&MessageComponentRan{c, it})
+		if c.ExecuteArgs != nil {
+			c.ExecuteArgs(it, inv.ComponentArgs)
+			return
+		}
+		c.Execute(it)
+	})
 }
 
-func (m *ModuleBase) handleModalSubmit(s *ModuleModalSubmit, it *discord.DiscordModalSubmit) {
-	if !s.Enabled || !s.allowsInteraction(it) {
+func (m *ModuleBase) handleModalSubmit(s *ModuleModalSubmit, it *discord.DiscordModalSubmit, args *ComponentArgs) {
+	if !s.Enabled {
+		return
+	}
+	decision := m.Bot.permissionDecision(it.GuildID(), s.Command, it.ChannelID(), it.Interaction.Member)
+	if !s.allowsInteraction(it, decision) {
+		_ = it.RespondEphemeral("Missing permissions to use this")
 		return
 	}
-	go m.runModalSubmit(s, it)
+	if !m.Bot.startHandler() {
+		return
+	}
+	if args == nil {
+		args = &ComponentArgs{Name: s.Name, Version: s.Version}
+	}
+	m.Bot.dispatch(it.Shard, func() { m.runModalSubmit(s, it, args) })
 }
 
 func (m *ModuleBase) recoverModalSubmit(s *ModuleModalSubmit, it *discord.DiscordModalSubmit) {
@@ -317,10 +525,21 @@ func (m *ModuleBase) recoverModalSubmit(s *ModuleModalSubmit, it *discord.Discor
 	}
 }
 
-func (m *ModuleBase) runModalSubmit(s *ModuleModalSubmit, it *discord.DiscordModalSubmit) {
+func (m *ModuleBase) runModalSubmit(s *ModuleModalSubmit, it *discord.DiscordModalSubmit, args *ComponentArgs) {
+	defer m.Bot.handlerDone()
 	defer m.recoverModalSubmit(s, it)
-	m.Bot.Emit(&ModalSubmitRan{s, it})
-	s.Execute(it)
+	ctx, cancel, timeout := m.handlerContext(s.Timeout)
+	defer cancel()
+	it.DiscordInteraction = it.DiscordInteraction.WithContext(ctx)
+	inv := &Invocation{Type: InvocationModalSubmit, Module: s.Mod, Name: s.Name, Interaction: it.DiscordInteraction, ModalSubmit: s, ComponentArgs: args}
+	m.invoke(inv, timeout, func(inv *Invocation) {
+		m.Bot.Emit(&ModalSubmitRan{s, it})
+		if s.ExecuteArgs != nil {
+			s.ExecuteArgs(it, inv.ComponentArgs)
+			return
+		}
+		s.Execute(it)
+	})
 }
 
 func (m *ModuleBase) Commands() map[string]*ModuleCommand {
@@ -336,12 +555,32 @@ func (m *ModuleBase) RegisterCommands(commands ...*ModuleCommand) error {
 	return nil
 }
 
+// RegisterHybridCommands registers every hybrid command both as a text command
+// and as an application command.
+func (m *ModuleBase) RegisterHybridCommands(commands ...*ModuleHybridCommand) error {
+	for _, cmd := range commands {
+		if err := m.registerCommand(cmd.TextCommand()); err != nil {
+			return err
+		}
+		if err := m.registerApplicationCommand(cmd.ApplicationCommand()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *ModuleBase) registerCommand(cmd *ModuleCommand) error {
 	m.Lock()
 	defer m.Unlock()
 	if _, ok := m.commands[cmd.Name]; ok {
 		return fmt.Errorf("command '%v' already exists in %v", cmd.Name, m.Name())
 	}
+	if err := m.triggers.insert(cmd.Mod, cmd); err != nil {
+		return err
+	}
+	if cmd.Usage == "" && len(cmd.Arguments) > 0 {
+		cmd.Usage = cmd.ArgumentUsage()
+	}
 	m.commands[cmd.Name] = cmd
 	m.Logger.Info("Registered command", "name", cmd.Name)
 	return nil
@@ -366,18 +605,10 @@ func (m *ModuleBase) findCommandByName(name string) (*ModuleCommand, error) {
 	return nil, ErrCommandNotFound
 }
 
+// findCommandByTriggers finds the command with the longest trigger name starts with.
 func (m *ModuleBase) findCommandByTriggers(name string) (*ModuleCommand, error) {
-	for _, cmd := range m.Commands() {
-		for _, trig := range cmd.Triggers {
-			splitTrig := strings.Fields(trig)
-			splitName := strings.Fields(name)
-			if len(splitName) < len(splitTrig) {
-				continue
-			}
-			if strings.EqualFold(strings.Join(splitName[:len(splitTrig)], " "), trig) {
-				return cmd, nil
-			}
-		}
+	if entry, ok := m.triggers.match(name); ok {
+		return entry.cmd, nil
 	}
 	return nil, ErrCommandNotFound
 }
@@ -430,7 +661,7 @@ func (m *ModuleBase) RegisterApplicationCommands(commands ...*ModuleApplicationC
 func (m *ModuleBase) registerApplicationCommand(command *ModuleApplicationCommand) error {
 	m.Lock()
 	defer m.Unlock()
-	if _, ok := m.commands[command.Name]; ok {
+	if _, ok := m.applicationCommands[command.Name]; ok {
 		return fmt.Errorf("application command '%v' already exists in %v", command.Name, m.Name())
 	}
 	m.applicationCommands[command.Name] = command
@@ -485,6 +716,17 @@ func (m *ModuleBase) FindMessageComponent(name string) (*ModuleMessageComponent,
 	return nil, ErrMessageComponentNotFound
 }
 
+// ComponentCustomID returns a signed custom ID for the message component called
+// name, carrying payload. The handler of the component gets the payload back from
+// its ComponentArgs, even after a restart.
+func (m *ModuleBase) ComponentCustomID(name string, payload any) (string, error) {
+	comp, err := m.FindMessageComponent(name)
+	if err != nil {
+		return "", err
+	}
+	return m.Bot.CustomIDs.Encode(comp.Name, comp.Version, payload)
+}
+
 func (m *ModuleBase) SetMessageComponentCallback(id, name string) {
 	m.Lock()
 	defer m.Unlock()
@@ -493,7 +735,16 @@ func (m *ModuleBase) SetMessageComponentCallback(id, name string) {
 	}
 }
 
+func (m *ModuleBase) messageComponentCallback(id string) (*ModuleMessageComponent, bool) {
+	m.Lock()
+	defer m.Unlock()
+	comp, ok := m.messageComponentCallbacks[id]
+	return comp, ok
+}
+
 func (m *ModuleBase) RemoveMessageComponentCallback(id string) {
+	m.Lock()
+	defer m.Unlock()
 	delete(m.messageComponentCallbacks, id)
 }
 
@@ -530,6 +781,16 @@ func (m *ModuleBase) FindModalSubmit(name string) (*ModuleModalSubmit, error) {
 	return nil, ErrModalSubmitNotFound
 }
 
+// ModalCustomID returns a signed custom ID for the modal submit called name,
+// carrying payload, in the same way as ComponentCustomID.
+func (m *ModuleBase) ModalCustomID(name string, payload any) (string, error) {
+	s, err := m.FindModalSubmit(name)
+	if err != nil {
+		return "", err
+	}
+	return m.Bot.CustomIDs.Encode(s.Name, s.Version, payload)
+}
+
 func (m *ModuleBase) SetModalSubmitCallback(id, name string) {
 	m.Lock()
 	defer m.Unlock()
@@ -538,10 +799,58 @@ func (m *ModuleBase) SetModalSubmitCallback(id, name string) {
 	}
 }
 
+func (m *ModuleBase) modalSubmitCallback(id string) (*ModuleModalSubmit, bool) {
+	m.Lock()
+	defer m.Unlock()
+	s, ok := m.modalSubmitCallbacks[id]
+	return s, ok
+}
+
 func (m *ModuleBase) RemoveModalSubmitCallback(id string) {
+	m.Lock()
+	defer m.Unlock()
 	delete(m.modalSubmitCallbacks, id)
 }
 
+// RegisterPages registers the message component and the modal submit that
+// handle the buttons of the paginators of each ModulePages. See NewPaginator.
+func (m *ModuleBase) RegisterPages(pages ...*ModulePages) error {
+	for _, p := range pages {
+		if err := m.registerPages(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ModuleBase) registerPages(pages *ModulePages) error {
+	m.Lock()
+	if _, ok := m.pages[pages.Name]; ok {
+		m.Unlock()
+		return fmt.Errorf("pages '%v' already exist in %v", pages.Name, m.Name())
+	}
+	if pages.Timeout <= 0 {
+		pages.Timeout = DefaultPaginatorTimeout
+	}
+	pages.codec = m.Bot.CustomIDs
+	m.pages[pages.Name] = pages
+	m.Unlock()
+
+	if err := m.RegisterMessageComponents(pages.component()); err != nil {
+		return err
+	}
+	return m.RegisterModalSubmits(pages.modalSubmit())
+}
+
+func (m *ModuleBase) FindPages(name string) (*ModulePages, error) {
+	m.Lock()
+	defer m.Unlock()
+	if p, ok := m.pages[name]; ok {
+		return p, nil
+	}
+	return nil, ErrPagesNotFound
+}
+
 type CooldownScope int
 
 const (
@@ -558,7 +867,9 @@ const (
 	UserTypeBotOwner
 )
 
-// ModuleCommand represents a command for a Module.
+// ModuleCommand represents a command for a Module. Execute runs with a context,
+// available through msg.Context(), which times out after Timeout, or
+// DefaultTimeout if Timeout is 0.
 type ModuleCommand struct {
 	Mod              Module
 	Name             string
@@ -567,16 +878,32 @@ type ModuleCommand struct {
 	Usage            string
 	Cooldown         time.Duration
 	CooldownScope    CooldownScope
+	CooldownMode     CooldownMode
+	CooldownUses     int
 	RequiredPerms    int64
 	RequiresUserType UserType
 	CheckBotPerms    bool
 	AllowedTypes     discord.MessageType
 	AllowDMs         bool
 	Enabled          bool
+	Timeout          time.Duration
 	Execute          func(*discord.DiscordMessage) `json:"-"`
+
+	// Arguments is the argument spec of the command. If set, arguments are parsed
+	// and validated before the command runs, and ExecuteArgs is used instead of Execute.
+	Arguments   []*CommandArgument
+	ExecuteArgs func(*discord.DiscordMessage, *CommandArgs) `json:"-"`
+
+	// Run is used instead of Execute and ExecuteArgs if set. Errors it returns are
+	// shown to the author, logged and emitted as CommandFailed, see CommandError.
+	// args is nil for commands without Arguments.
+	Run func(msg *discord.DiscordMessage, args *CommandArgs) error `json:"-"`
 }
 
-func (cmd *ModuleCommand) allowsMessage(msg *discord.DiscordMessage) bool {
+// allowsMessage checks whether msg may run the command. The permission rules of the
+// guild, summed up by decision, can deny the command, or let the author run it
+// without RequiredPerms. The bot still needs RequiredPerms if CheckBotPerms is set.
+func (cmd *ModuleCommand) allowsMessage(msg *discord.DiscordMessage, decision PermissionDecision) bool {
 	if msg.IsDM() && !cmd.AllowDMs {
 		return false
 	}
@@ -585,9 +912,15 @@ func (cmd *ModuleCommand) allowsMessage(msg *discord.DiscordMessage) bool {
 		return false
 	}
 
+	if decision == PermissionDeny {
+		return false
+	}
+
 	if cmd.RequiredPerms != 0 {
-		if allow, err := msg.AuthorHasPermissions(cmd.RequiredPerms); err != nil || !allow {
-			return false
+		if decision != PermissionAllow {
+			if allow, err := msg.AuthorHasPermissions(cmd.RequiredPerms); err != nil || !allow {
+				return false
+			}
 		}
 		if cmd.CheckBotPerms {
 			if botAllow, err := msg.Discord.BotHasPermissions(msg.ChannelID(), cmd.RequiredPerms); err != nil || !botAllow {
@@ -600,13 +933,24 @@ func (cmd *ModuleCommand) allowsMessage(msg *discord.DiscordMessage) bool {
 }
 
 func (cmd *ModuleCommand) CooldownKey(msg *discord.DiscordMessage) string {
-	switch cmd.CooldownScope {
+	return cooldownKey(cmd.CooldownScope, cmd.Name, msg.AuthorID(), msg.ChannelID(), msg.GuildID())
+}
+
+// CooldownLimit returns how uses of the command are limited.
+func (cmd *ModuleCommand) CooldownLimit() CooldownLimit {
+	return CooldownLimit{Mode: cmd.CooldownMode, Uses: cmd.CooldownUses, Period: cmd.Cooldown}
+}
+
+// cooldownKey builds the key used in the cooldown manager. Text commands and application
+// commands with the same name share keys, so hybrid commands share their cooldowns.
+func cooldownKey(scope CooldownScope, name, userID, channelID, guildID string) string {
+	switch scope {
 	case CooldownScopeUser:
-		return fmt.Sprintf("user:%v:%v", msg.AuthorID(), cmd.Name)
+		return fmt.Sprintf("user:%v:%v", userID, name)
 	case CooldownScopeChannel:
-		return fmt.Sprintf("channel:%v:%v", msg.ChannelID(), cmd.Name)
+		return fmt.Sprintf("channel:%v:%v", channelID, name)
 	case CooldownScopeGuild:
-		return fmt.Sprintf("guild:%v:%v", msg.GuildID(), cmd.Name)
+		return fmt.Sprintf("guild:%v:%v", guildID, name)
 	}
 	return ""
 }
@@ -619,6 +963,7 @@ type ModulePassive struct {
 	AllowedTypes discord.MessageType
 	AllowDMs     bool
 	Enabled      bool
+	Timeout      time.Duration
 	Execute      func(*discord.DiscordMessage) `json:"-"`
 }
 
@@ -633,30 +978,148 @@ func (pas *ModulePassive) allowsMessage(msg *discord.DiscordMessage) bool {
 	return true
 }
 
+// DefaultAutoDefer leaves a second of Discord's three second deadline for the
+// deferral itself.
+const DefaultAutoDefer = time.Second * 2
+
 type ModuleApplicationCommand struct {
 	*discordgo.ApplicationCommand
 	Mod           Module
 	Cooldown      time.Duration
 	CooldownScope CooldownScope
+	CooldownMode  CooldownMode
+	CooldownUses  int
 	UserType      UserType
 	CheckBotPerms bool
 	Enabled       bool
+	Timeout       time.Duration
 	Execute       func(*discord.DiscordApplicationCommand) `json:"-"`
+	// Run is used instead of Execute if set, and reports failures the way
+	// ModuleCommand.Run does.
+	Run func(*discord.DiscordApplicationCommand) error `json:"-"`
+
+	// Subcommands run instead of Execute when one of them is used. They are keyed
+	// by subcommand, or group:subcommand for subcommands in groups.
+	Subcommands map[string]SubcommandHandler `json:"-"`
+	// GuildIDs scopes the command to these guilds. Commands without any are global.
+	GuildIDs []string `json:"-"`
+	// AutoDefer defers the interaction if Execute has not responded to it within
+	// this long of it being received, so slow commands do not miss Discord's
+	// deadline. Responses made after that edit the deferred response.
+	// DeferEphemeral makes it ephemeral.
+	AutoDefer      time.Duration
+	DeferEphemeral bool
+
+	// Autocomplete suggests values for options as they are typed. It is keyed by
+	// option, as DiscordApplicationCommand.Options keys them, and the options need
+	// Autocomplete set.
+	Autocomplete map[string]AutocompleteHandler `json:"-"`
+}
+
+// SubcommandHandler runs a subcommand of an application command. Errors it returns
+// are reported the way ModuleApplicationCommand.Run reports them.
+type SubcommandHandler func(*discord.DiscordSubcommand) error
+
+// execute runs the subcommand that was used, or Run or Execute if there is no
+// handler for it.
+func (m *ModuleApplicationCommand) execute(it *discord.DiscordApplicationCommand) error {
+	path := it.SubcommandPath()
+	if handler, ok := m.Subcommands[path]; ok && path != "" {
+		return handler(&discord.DiscordSubcommand{DiscordApplicationCommand: it, Path: path})
+	}
+	if m.Run != nil {
+		return m.Run(it)
+	}
+	if m.Execute != nil {
+		m.Execute(it)
+		return nil
+	}
+	return UsageError("That subcommand does not exist")
+}
+
+// AutocompleteHandler suggests values for an application command option while it
+// is being typed, by responding with choices.
+type AutocompleteHandler func(*discord.DiscordAutocomplete)
+
+// allowsInteraction works like ModuleCommand.allowsMessage. Discord hides commands
+// from members without DefaultMemberPermissions, so an allow rule only helps members
+// who can already see the command.
+func (m *ModuleApplicationCommand) allowsInteraction(it *discord.DiscordApplicationCommand, decision PermissionDecision) bool {
+	if m.DMPermission != nil && !*m.DMPermission && it.IsDM() {
+		return false
+	}
+	var perms int64
+	if m.DefaultMemberPermissions != nil {
+		perms = *m.DefaultMemberPermissions
+	}
+	return interactionAllowed(it.DiscordInteraction, perms, m.CheckBotPerms, decision)
+}
+
+func (m *ModuleApplicationCommand) CooldownKey(it *discord.DiscordInteraction) string {
+	return cooldownKey(m.CooldownScope, m.Name, it.AuthorID(), it.ChannelID(), it.GuildID())
+}
+
+func (m *ModuleApplicationCommand) CooldownLimit() CooldownLimit {
+	return CooldownLimit{Mode: m.CooldownMode, Uses: m.CooldownUses, Period: m.Cooldown}
+}
+
+// interactionAllowed checks perms like interactionHasPermissions, after applying
+// the permission rules of decision. An allow rule skips the check of the author,
+// but not of the bot.
+func interactionAllowed(it *discord.DiscordInteraction, perms int64, checkBot bool, decision PermissionDecision) bool {
+	if decision == PermissionDeny {
+		return false
+	}
+	if perms == 0 {
+		return true
+	}
+	if decision == PermissionAllow {
+		if !checkBot {
+			return true
+		}
+		ok, err := it.Discord.BotHasPermissions(it.ChannelID(), perms)
+		return err == nil && ok
+	}
+	return interactionHasPermissions(it, perms, checkBot)
 }
 
-func (m *ModuleApplicationCommand) allowsInteraction(it *discord.DiscordApplicationCommand) bool {
+// interactionHasPermissions checks whether the interaction author, and optionally the bot,
+// has perms in the channel of the interaction.
+func interactionHasPermissions(it *discord.DiscordInteraction, perms int64, checkBot bool) bool {
+	if !it.AuthorHasPermissions(perms) {
+		return false
+	}
+	if checkBot {
+		if ok, err := it.Discord.BotHasPermissions(it.ChannelID(), perms); err != nil || !ok {
+			return false
+		}
+	}
 	return true
 }
 
 type ModuleModalSubmit struct {
-	Mod     Module
-	Name    string
-	Enabled bool
-	Execute func(*discord.DiscordModalSubmit) `json:"-"`
+	Mod           Module
+	Name          string
+	Permissions   int64
+	CheckBotPerms bool
+	Enabled       bool
+	Timeout       time.Duration
+	Execute       func(*discord.DiscordModalSubmit) `json:"-"`
+
+	// Command is the command the modal belongs to, if any. Its permission rules
+	// apply to the modal as well.
+	Command string
+
+	// Version is encoded into the signed custom IDs of the modal, see
+	// ModuleMessageComponent.Version.
+	Version int
+	// ExecuteArgs is used instead of Execute if set, and gets the payload of the
+	// signed custom ID of the modal. See ModuleBase.ModalCustomID.
+	ExecuteArgs func(*discord.DiscordModalSubmit, *ComponentArgs) `json:"-"`
 }
 
-func (s *ModuleModalSubmit) allowsInteraction(it *discord.DiscordModalSubmit) bool {
-	return true
+func (s *ModuleModalSubmit) allowsInteraction(it *discord.DiscordModalSubmit, decision PermissionDecision) bool {
+	return interactionAllowed(it.DiscordInteraction, s.Permissions, s.CheckBotPerms, decision)
 }
 
 type ModuleMessageComponent struct {
@@ -664,13 +1127,36 @@ type ModuleMessageComponent struct {
 	Name          string
 	Cooldown      time.Duration
 	CooldownScope CooldownScope
+	CooldownMode  CooldownMode
+	CooldownUses  int
 	Permissions   int64
 	UserType      UserType
 	CheckBotPerms bool
 	Enabled       bool
+	Timeout       time.Duration
 	Execute       func(*discord.DiscordMessageComponent) `json:"-"`
+
+	// Command is the command the component belongs to, if any. Its permission
+	// rules apply to the component as well.
+	Command string
+
+	// Version is encoded into the signed custom IDs of the component. Clicks on
+	// custom IDs of another version are turned away, so bump it when the payload
+	// changes shape.
+	Version int
+	// ExecuteArgs is used instead of Execute if set, and gets the payload of the
+	// signed custom ID that was clicked. See ModuleBase.ComponentCustomID.
+	ExecuteArgs func(*discord.DiscordMessageComponent, *ComponentArgs) `json:"-"`
 }
 
-func (s *ModuleMessageComponent) allowsInteraction(it *discord.DiscordMessageComponent) bool {
-	return true
+func (s *ModuleMessageComponent) allowsInteraction(it *discord.DiscordMessageComponent, decision PermissionDecision) bool {
+	return interactionAllowed(it.DiscordInteraction, s.Permissions, s.CheckBotPerms, decision)
+}
+
+func (s *ModuleMessageComponent) CooldownKey(it *discord.DiscordInteraction) string {
+	return cooldownKey(s.CooldownScope, s.Name, it.AuthorID(), it.ChannelID(), it.GuildID())
+}
+
+func (s *ModuleMessageComponent) CooldownLimit() CooldownLimit {
+	return CooldownLimit{Mode: s.CooldownMode, Uses: s.CooldownUses, Period: s.Cooldown}
 }
@@ -45,6 +45,13 @@ func (b *ModuleCommandBuilder) Cooldown(duration time.Duration, scope CooldownSc
 	return b
 }
 
+// CooldownMode sets how uses are limited over the cooldown, allowing uses uses per cooldown.
+func (b *ModuleCommandBuilder) CooldownMode(mode CooldownMode, uses int) *ModuleCommandBuilder {
+	b.cmd.CooldownMode = mode
+	b.cmd.CooldownUses = uses
+	return b
+}
+
 func (b *ModuleCommandBuilder) RequiredPerms(perms int64) *ModuleCommandBuilder {
 	b.cmd.RequiredPerms = perms
 	return b
@@ -75,11 +82,33 @@ func (b *ModuleCommandBuilder) Execute(exec func(*discord.DiscordMessage)) *Modu
 	return b
 }
 
+func (b *ModuleCommandBuilder) Arguments(args ...*CommandArgument) *ModuleCommandBuilder {
+	b.cmd.Arguments = args
+	return b
+}
+
+func (b *ModuleCommandBuilder) ExecuteArgs(exec func(*discord.DiscordMessage, *CommandArgs)) *ModuleCommandBuilder {
+	b.cmd.ExecuteArgs = exec
+	return b
+}
+
+// Run sets a handler which returns its errors instead of replying with them.
+func (b *ModuleCommandBuilder) Run(run func(*discord.DiscordMessage, *CommandArgs) error) *ModuleCommandBuilder {
+	b.cmd.Run = run
+	return b
+}
+
+// Timeout sets the deadline of the context the command runs with.
+func (b *ModuleCommandBuilder) Timeout(timeout time.Duration) *ModuleCommandBuilder {
+	b.cmd.Timeout = timeout
+	return b
+}
+
 func (b *ModuleCommandBuilder) Build() *ModuleCommand {
 	if b.cmd.AllowedTypes == 0 {
 		panic("allowed types cannot be 0")
 	}
-	if b.cmd.Execute == nil {
+	if b.cmd.Execute == nil && b.cmd.ExecuteArgs == nil && b.cmd.Run == nil {
 		panic("missing execute")
 	}
 	return b.cmd
@@ -114,6 +143,11 @@ func (b *ModulePassiveBuilder) Execute(exec func(*discord.DiscordMessage)) *Modu
 	return b
 }
 
+func (b *ModulePassiveBuilder) Timeout(timeout time.Duration) *ModulePassiveBuilder {
+	b.pas.Timeout = timeout
+	return b
+}
+
 func (b *ModulePassiveBuilder) Build() *ModulePassive {
 	if b.pas.AllowedTypes == 0 {
 		panic("allowed types cannot be 0")
@@ -158,14 +192,26 @@ func (b *ModuleApplicationCommandBuilder) AddOption(option *discordgo.Applicatio
 	return b
 }
 
-func (b *ModuleApplicationCommandBuilder) AddSubcommand(subcommand *discordgo.ApplicationCommandOption) *ModuleApplicationCommandBuilder {
+// AddSubcommand adds a subcommand that runs execute when it is used.
+func (b *ModuleApplicationCommandBuilder) AddSubcommand(subcommand *discordgo.ApplicationCommandOption, execute SubcommandHandler) *ModuleApplicationCommandBuilder {
 	subcommand.Type = discordgo.ApplicationCommandOptionSubCommand
+	b.addSubcommandHandler(subcommand.Name, execute)
 	return b.AddOption(subcommand)
 }
 
-func (b *ModuleApplicationCommandBuilder) AddSubcommandGroup(group *discordgo.ApplicationCommandOption) *ModuleApplicationCommandBuilder {
-	group.Type = discordgo.ApplicationCommandOptionSubCommandGroup
-	return b.AddOption(group)
+// AddSubcommandGroup adds a group of subcommands, which run their own handlers.
+func (b *ModuleApplicationCommandBuilder) AddSubcommandGroup(group *SubcommandGroup) *ModuleApplicationCommandBuilder {
+	for name, execute := range group.handlers {
+		b.addSubcommandHandler(group.option.Name+":"+name, execute)
+	}
+	return b.AddOption(group.option)
+}
+
+func (b *ModuleApplicationCommandBuilder) addSubcommandHandler(path string, execute SubcommandHandler) {
+	if b.command.Subcommands == nil {
+		b.command.Subcommands = make(map[string]SubcommandHandler)
+	}
+	b.command.Subcommands[path] = execute
 }
 
 func (b *ModuleApplicationCommandBuilder) Cooldown(cooldown time.Duration, scope CooldownScope) *ModuleApplicationCommandBuilder {
@@ -174,6 +220,13 @@ func (b *ModuleApplicationCommandBuilder) Cooldown(cooldown time.Duration, scope
 	return b
 }
 
+// CooldownMode sets how uses are limited over the cooldown, allowing uses uses per cooldown.
+func (b *ModuleApplicationCommandBuilder) CooldownMode(mode CooldownMode, uses int) *ModuleApplicationCommandBuilder {
+	b.command.CooldownMode = mode
+	b.command.CooldownUses = uses
+	return b
+}
+
 func (b *ModuleApplicationCommandBuilder) NoDM() *ModuleApplicationCommandBuilder {
 	dmPerms := false
 	b.command.DMPermission = &dmPerms
@@ -195,12 +248,185 @@ func (b *ModuleApplicationCommandBuilder) Execute(f func(*discord.DiscordApplica
 	return b
 }
 
+// Run sets a handler which returns its errors instead of responding with them.
+func (b *ModuleApplicationCommandBuilder) Run(f func(*discord.DiscordApplicationCommand) error) *ModuleApplicationCommandBuilder {
+	b.command.Run = f
+	return b
+}
+
+func (b *ModuleApplicationCommandBuilder) Timeout(timeout time.Duration) *ModuleApplicationCommandBuilder {
+	b.command.Timeout = timeout
+	return b
+}
+
+// AutoDefer defers the interaction if the command has not responded to it within
+// after. See DefaultAutoDefer.
+func (b *ModuleApplicationCommandBuilder) AutoDefer(after time.Duration, ephemeral bool) *ModuleApplicationCommandBuilder {
+	b.command.AutoDefer = after
+	b.command.DeferEphemeral = ephemeral
+	return b
+}
+
+// Guilds scopes the command to guildIDs instead of making it global. Guild
+// commands update right away, which makes them handy for testing.
+func (b *ModuleApplicationCommandBuilder) Guilds(guildIDs ...string) *ModuleApplicationCommandBuilder {
+	b.command.GuildIDs = append(b.command.GuildIDs, guildIDs...)
+	return b
+}
+
+// Autocomplete sets the handler that suggests values for option. Options of
+// subcommands are given as subcommand:option. The option is marked for
+// autocompletion when the command is built.
+func (b *ModuleApplicationCommandBuilder) Autocomplete(option string, handler AutocompleteHandler) *ModuleApplicationCommandBuilder {
+	if b.command.Autocomplete == nil {
+		b.command.Autocomplete = make(map[string]AutocompleteHandler)
+	}
+	b.command.Autocomplete[option] = handler
+	return b
+}
+
 func (b *ModuleApplicationCommandBuilder) Build() *ModuleApplicationCommand {
 	if b.command.Type == 0 {
 		panic("command type cannot be 0")
 	}
-	if b.command.Execute == nil {
+	if b.command.Execute == nil && b.command.Run == nil && len(b.command.Subcommands) == 0 {
 		panic("missing execute")
 	}
+	markAutocompleteOptions(b.command.Options, "", b.command.Autocomplete)
 	return b.command
 }
+
+// SubcommandGroup is a group of subcommands and the handlers they run.
+type SubcommandGroup struct {
+	option   *discordgo.ApplicationCommandOption
+	handlers map[string]SubcommandHandler
+}
+
+func NewSubcommandGroup(name, description string) *SubcommandGroup {
+	return &SubcommandGroup{
+		option: &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+			Name:        name,
+			Description: description,
+		},
+		handlers: make(map[string]SubcommandHandler),
+	}
+}
+
+// AddSubcommand adds a subcommand to the group that runs execute when it is used.
+func (g *SubcommandGroup) AddSubcommand(subcommand *discordgo.ApplicationCommandOption, execute SubcommandHandler) *SubcommandGroup {
+	subcommand.Type = discordgo.ApplicationCommandOptionSubCommand
+	g.option.Options = append(g.option.Options, subcommand)
+	g.handlers[subcommand.Name] = execute
+	return g
+}
+
+// markAutocompleteOptions sets Autocomplete on the options that have a handler.
+func markAutocompleteOptions(options []*discordgo.ApplicationCommandOption, prefix string, handlers map[string]AutocompleteHandler) {
+	for _, opt := range options {
+		key := prefix + opt.Name
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand || opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			markAutocompleteOptions(opt.Options, key+":", handlers)
+			continue
+		}
+		if _, ok := handlers[key]; ok {
+			opt.Autocomplete = true
+		}
+	}
+}
+
+type ModuleHybridCommandBuilder struct {
+	cmd *ModuleHybridCommand
+}
+
+func NewModuleHybridCommandBuilder(mod Module, name string) *ModuleHybridCommandBuilder {
+	return &ModuleHybridCommandBuilder{
+		cmd: &ModuleHybridCommand{
+			Mod:              mod,
+			Name:             name,
+			Triggers:         []string{name},
+			CooldownScope:    CooldownScopeNone,
+			RequiresUserType: UserTypeAny,
+			Enabled:          true,
+		},
+	}
+}
+
+func (b *ModuleHybridCommandBuilder) Description(text string) *ModuleHybridCommandBuilder {
+	b.cmd.Description = text
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) Triggers(trigs ...string) *ModuleHybridCommandBuilder {
+	b.cmd.Triggers = trigs
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) Usage(text string) *ModuleHybridCommandBuilder {
+	b.cmd.Usage = text
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) Arguments(args ...*CommandArgument) *ModuleHybridCommandBuilder {
+	b.cmd.Arguments = args
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) Cooldown(duration time.Duration, scope CooldownScope) *ModuleHybridCommandBuilder {
+	b.cmd.Cooldown = duration
+	b.cmd.CooldownScope = scope
+	return b
+}
+
+// CooldownMode sets how uses are limited over the cooldown, allowing uses uses per cooldown.
+func (b *ModuleHybridCommandBuilder) CooldownMode(mode CooldownMode, uses int) *ModuleHybridCommandBuilder {
+	b.cmd.CooldownMode = mode
+	b.cmd.CooldownUses = uses
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) RequiredPerms(perms int64) *ModuleHybridCommandBuilder {
+	b.cmd.RequiredPerms = perms
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) RequiresBotOwner() *ModuleHybridCommandBuilder {
+	b.cmd.RequiresUserType = UserTypeBotOwner
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) CheckBotPerms() *ModuleHybridCommandBuilder {
+	b.cmd.CheckBotPerms = true
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) AllowDMs() *ModuleHybridCommandBuilder {
+	b.cmd.AllowDMs = true
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) Execute(exec func(CommandContext)) *ModuleHybridCommandBuilder {
+	b.cmd.Execute = exec
+	return b
+}
+
+// Run sets a handler which returns its errors instead of replying with them.
+func (b *ModuleHybridCommandBuilder) Run(run func(CommandContext) error) *ModuleHybridCommandBuilder {
+	b.cmd.Run = run
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) Timeout(timeout time.Duration) *ModuleHybridCommandBuilder {
+	b.cmd.Timeout = timeout
+	return b
+}
+
+func (b *ModuleHybridCommandBuilder) Build() *ModuleHybridCommand {
+	if b.cmd.Description == "" {
+		panic("missing description")
+	}
+	if b.cmd.Execute == nil && b.cmd.Run == nil {
+		panic("missing execute")
+	}
+	return b.cmd
+}
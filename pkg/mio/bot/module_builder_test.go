@@ -231,6 +231,24 @@ func TestModuleApplicationCommandBuilder(t *testing.T) {
 		require.NotNil(t, builder.command.Execute, "Execute should be set")
 	})
 
+	t.Run("Subcommands", func(t *testing.T) {
+		exec := func(sub *discord.DiscordSubcommand) error { return nil }
+		command := NewModuleApplicationCommandBuilder(mod, "testCommand").
+			Type(discordgo.ChatApplicationCommand).
+			AddSubcommand(&discordgo.ApplicationCommandOption{Name: "sub", Description: "sub"}, exec).
+			AddSubcommandGroup(NewSubcommandGroup("group", "group").
+				AddSubcommand(&discordgo.ApplicationCommandOption{Name: "leaf", Description: "leaf"}, exec)).
+			Build()
+
+		require.Len(t, command.Options, 2, "Options slice should have the subcommand and the group")
+		assert.Equal(t, discordgo.ApplicationCommandOptionSubCommand, command.Options[0].Type, "Subcommand type mismatch")
+		assert.Equal(t, discordgo.ApplicationCommandOptionSubCommandGroup, command.Options[1].Type, "Group type mismatch")
+		require.Len(t, command.Options[1].Options, 1, "Group should have one subcommand")
+		assert.Equal(t, discordgo.ApplicationCommandOptionSubCommand, command.Options[1].Options[0].Type, "Group subcommand type mismatch")
+		assert.Contains(t, command.Subcommands, "sub", "Subcommand handler missing")
+		assert.Contains(t, command.Subcommands, "group:leaf", "Group subcommand handler missing")
+	})
+
 	t.Run("Build", func(t *testing.T) {
 		t.Run("Panic on Type zero", func(t *testing.T) {
 			assert.Panics(t, func() {
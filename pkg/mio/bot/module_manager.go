@@ -1,21 +1,24 @@
 package bot
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/intrntsrfr/meido/pkg/mio"
 )
 
 type ModuleManager struct {
-	Modules map[string]Module
-	logger  mio.Logger
+	Modules  map[string]Module
+	triggers *commandTrie
+	logger   mio.Logger
 }
 
 func NewModuleManager(logger mio.Logger) *ModuleManager {
 	logger = logger.Named("ModuleManager")
 	return &ModuleManager{
-		Modules: make(map[string]Module),
-		logger:  logger,
+		Modules:  make(map[string]Module),
+		triggers: newCommandTrie(),
+		logger:   logger,
 	}
 }
 
@@ -25,10 +28,41 @@ func (m *ModuleManager) RegisterModule(mod Module) {
 		m.logger.Error("Failed to register module", "module", mod.Name(), "error", err)
 		return
 	}
+	if err := m.registerTriggers(mod); err != nil {
+		m.logger.Error("Failed to register module", "module", mod.Name(), "error", err)
+		return
+	}
 	m.Modules[mod.Name()] = mod
 	m.logger.Info("Registered module", "name", mod.Name())
 }
 
+// registerTriggers adds the command triggers of mod to the trie. Nothing is added
+// if any of them conflict with the triggers of already registered modules.
+func (m *ModuleManager) registerTriggers(mod Module) error {
+	for _, cmd := range mod.Commands() {
+		for _, trig := range cmd.Triggers {
+			if err := m.triggers.conflict(cmd, trig); err != nil {
+				return err
+			}
+		}
+	}
+	for _, cmd := range mod.Commands() {
+		if err := m.triggers.insert(mod, cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchCommand finds the command, across every module, with the longest trigger
+// content starts with.
+func (m *ModuleManager) MatchCommand(content string) (Module, *ModuleCommand, error) {
+	if entry, ok := m.triggers.match(content); ok {
+		return entry.mod, entry.cmd, nil
+	}
+	return nil, nil, ErrCommandNotFound
+}
+
 func (m *ModuleManager) FindModule(name string) (Module, error) {
 	for _, m := range m.Modules {
 		if strings.EqualFold(m.Name(), name) {
@@ -39,6 +73,9 @@ func (m *ModuleManager) FindModule(name string) (Module, error) {
 }
 
 func (m *ModuleManager) FindCommand(name string) (*ModuleCommand, error) {
+	if _, cmd, err := m.MatchCommand(name); err == nil {
+		return cmd, nil
+	}
 	for _, m := range m.Modules {
 		if cmd, err := m.FindCommand(name); err == nil {
 			return cmd, nil
@@ -64,3 +101,28 @@ func (m *ModuleManager) FindApplicationCommand(name string) (*ModuleApplicationC
 	}
 	return nil, ErrApplicationCommandNotFound
 }
+
+// CommandNames returns the sorted names of every text and application command that
+// start with prefix, ignoring case. Hybrid commands are only listed once.
+func (m *ModuleManager) CommandNames(prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	seen := make(map[string]bool)
+	names := []string{}
+	add := func(name string) {
+		if seen[name] || !strings.HasPrefix(strings.ToLower(name), prefix) {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	for _, mod := range m.Modules {
+		for _, cmd := range mod.Commands() {
+			add(cmd.Name)
+		}
+		for _, cmd := range mod.ApplicationCommands() {
+			add(cmd.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
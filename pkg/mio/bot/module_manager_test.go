@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/intrntsrfr/meido/pkg/mio"
@@ -45,3 +46,20 @@ func TestModuleManager_FailedHook(t *testing.T) {
 		t.Errorf("len(ModuleManager.Modules) should be 0 after failed hook")
 	}
 }
+
+func TestModuleManager_CommandNames(t *testing.T) {
+	mngr := NewModuleManager(mio.NewDiscardLogger())
+	mod := NewTestModule(nil, "test", mio.NewDiscardLogger())
+	mod.RegisterCommands(&ModuleCommand{Name: "warn"}, &ModuleCommand{Name: "ban"})
+	mod.RegisterHybridCommands(&ModuleHybridCommand{Mod: mod, Name: "warnlog", Triggers: []string{"warnlog"}})
+	mngr.RegisterModule(mod)
+
+	got := mngr.CommandNames("WA")
+	want := []string{"warn", "warnlog"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ModuleManager.CommandNames() = %v, want %v", got, want)
+	}
+	if got := mngr.CommandNames(""); len(got) != 3 {
+		t.Errorf("ModuleManager.CommandNames() = %v, want 3 names", got)
+	}
+}
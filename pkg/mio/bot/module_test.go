@@ -1,6 +1,8 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -89,8 +91,8 @@ func TestModuleBase_RegisterCommands(t *testing.T) {
 func TestModuleBase_FindCommand(t *testing.T) {
 	base := NewModule(nil, "testing", mio.NewDiscardLogger())
 	cmd := &ModuleCommand{
-		Name:     "test",
-		Triggers: []string{"m?test", "m?settings test"},
+		Name:     "testcommand",
+		Triggers: []string{"test", "settings test"},
 	}
 	base.RegisterCommands(cmd)
 
@@ -107,28 +109,28 @@ func TestModuleBase_FindCommand(t *testing.T) {
 		{
 			name:    "positive test 1",
 			m:       base,
-			args:    args{"m?test"},
+			args:    args{"test"},
 			want:    cmd,
 			wantErr: false,
 		},
 		{
 			name:    "positive test 2",
 			m:       base,
-			args:    args{"m?settings test abc"},
+			args:    args{"settings test abc"},
 			want:    cmd,
 			wantErr: false,
 		},
 		{
 			name:    "positive test 3",
 			m:       base,
-			args:    args{"test"},
+			args:    args{"testcommand"},
 			want:    cmd,
 			wantErr: false,
 		},
 		{
 			name:    "negative test 1",
 			m:       base,
-			args:    args{"m?testing"},
+			args:    args{"testing"},
 			want:    nil,
 			wantErr: true,
 		},
@@ -379,6 +381,66 @@ func TestModuleBase_HandleApplicationCommand(t *testing.T) {
 		mod.HandleInteraction(it)
 		wg.Wait()
 	})
+	t.Run("owner only does not run for others", func(t *testing.T) {
+		bot := NewTestBot()
+		_ = bot.Discord.Sess.Open()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmdCalled := make(chan bool, 1)
+		cmd := NewTestApplicationCommand(mod)
+		cmd.UserType = UserTypeBotOwner
+		cmd.Execute = func(*discord.DiscordApplicationCommand) {
+			cmdCalled <- true
+		}
+		mod.RegisterApplicationCommands(cmd)
+		mod.HandleInteraction(NewTestApplicationCommandInteraction(bot, "1"))
+		select {
+		case <-cmdCalled:
+			t.Errorf("Application command was not expected to be called")
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
+
+	t.Run("cooldown stops second run", func(t *testing.T) {
+		bot := NewTestBot()
+		_ = bot.Discord.Sess.Open()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmdCalled := make(chan bool, 2)
+		cmd := NewTestApplicationCommand(mod)
+		cmd.Cooldown = time.Hour
+		cmd.CooldownScope = CooldownScopeChannel
+		cmd.Execute = func(*discord.DiscordApplicationCommand) {
+			cmdCalled <- true
+		}
+		mod.RegisterApplicationCommands(cmd)
+		mod.HandleInteraction(NewTestApplicationCommandInteraction(bot, "1"))
+		mod.HandleInteraction(NewTestApplicationCommandInteraction(bot, "1"))
+		<-cmdCalled
+		select {
+		case <-cmdCalled:
+			t.Errorf("Application command was not expected to be called twice")
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
+
+	t.Run("missing permissions does not run", func(t *testing.T) {
+		bot := NewTestBot()
+		_ = bot.Discord.Sess.Open()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmdCalled := make(chan bool, 1)
+		cmd := NewTestApplicationCommand(mod)
+		perms := int64(discordgo.PermissionBanMembers)
+		cmd.DefaultMemberPermissions = &perms
+		cmd.Execute = func(*discord.DiscordApplicationCommand) {
+			cmdCalled <- true
+		}
+		mod.RegisterApplicationCommands(cmd)
+		mod.HandleInteraction(NewTestApplicationCommandInteraction(bot, "1"))
+		select {
+		case <-cmdCalled:
+			t.Errorf("Application command was not expected to be called")
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
 }
 
 func TestModuleBase_HandleMessageComponent(t *testing.T) {
@@ -414,20 +476,54 @@ func TestModuleBase_HandleMessageComponent(t *testing.T) {
 		wg.Wait()
 	})
 
-	t.Run("with suffix gets handled", func(t *testing.T) {
+	t.Run("signed custom id gets handled", func(t *testing.T) {
 		bot := NewTestBot()
-		wg := sync.WaitGroup{}
-		wg.Add(1)
-		bot.AddHandler(func(*MessageComponentRan) {
-			wg.Done()
-		})
 		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
 		cmd := NewTestMessageComponent(mod)
+		got := make(chan string, 1)
+		cmd.ExecuteArgs = func(_ *discord.DiscordMessageComponent, args *ComponentArgs) {
+			var payload struct{ Key string }
+			_ = args.Decode(&payload)
+			got <- payload.Key
+		}
 		mod.RegisterMessageComponents(cmd)
-		customID := cmd.Name + ":key"
+		customID, err := mod.ComponentCustomID(cmd.Name, struct{ Key string }{"key"})
+		if err != nil {
+			t.Fatalf("Unexpected error when encoding custom ID: %s", err)
+		}
 		it := NewTestMessageComponentInteraction(bot, "1", customID)
 		mod.HandleInteraction(it)
-		wg.Wait()
+		select {
+		case key := <-got:
+			if key != "key" {
+				t.Errorf("Expected payload key, got %v", key)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Message component was expected to run")
+		}
+	})
+
+	t.Run("forged or outdated custom id does not run", func(t *testing.T) {
+		bot := NewTestBot()
+		ran := make(chan bool, 1)
+		bot.AddHandler(func(*MessageComponentRan) {
+			ran <- true
+		})
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmd := NewTestMessageComponent(mod)
+		cmd.Version = 2
+		mod.RegisterMessageComponents(cmd)
+
+		forged, _ := NewCustomIDCodec([]byte("other")).Encode(cmd.Name, cmd.Version, struct{ Key string }{"key"})
+		outdated, _ := bot.CustomIDs.Encode(cmd.Name, 1, struct{ Key string }{"key"})
+		for _, customID := range []string{forged, outdated, cmd.Name + ":key"} {
+			mod.HandleInteraction(NewTestMessageComponentInteraction(bot, "1", customID))
+		}
+		select {
+		case <-ran:
+			t.Errorf("Message component was not expected to run")
+		case <-time.After(time.Millisecond * 50):
+		}
 	})
 
 	t.Run("panic gets handled", func(t *testing.T) {
@@ -449,6 +545,24 @@ func TestModuleBase_HandleMessageComponent(t *testing.T) {
 		mod.HandleInteraction(it)
 		wg.Wait()
 	})
+	t.Run("missing permissions does not run", func(t *testing.T) {
+		bot := NewTestBot()
+		_ = bot.Discord.Sess.Open()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmdCalled := make(chan bool, 1)
+		cmd := NewTestMessageComponent(mod)
+		cmd.Permissions = discordgo.PermissionBanMembers
+		cmd.Execute = func(*discord.DiscordMessageComponent) {
+			cmdCalled <- true
+		}
+		mod.RegisterMessageComponents(cmd)
+		mod.HandleInteraction(NewTestMessageComponentInteraction(bot, "1", "test"))
+		select {
+		case <-cmdCalled:
+			t.Errorf("Message component was not expected to be called")
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
 }
 
 func TestModuleBase_HandleModalSubmit(t *testing.T) {
@@ -488,6 +602,51 @@ func TestModuleBase_HandleModalSubmit(t *testing.T) {
 		mod.HandleInteraction(it)
 		wg.Wait()
 	})
+
+	t.Run("signed custom id gets handled", func(t *testing.T) {
+		bot := NewTestBot()
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmd := NewTestModalSubmit(mod)
+		got := make(chan string, 1)
+		cmd.ExecuteArgs = func(_ *discord.DiscordModalSubmit, args *ComponentArgs) {
+			var payload struct{ Key string }
+			_ = args.Decode(&payload)
+			got <- payload.Key
+		}
+		mod.RegisterModalSubmits(cmd)
+		customID, err := mod.ModalCustomID(cmd.Name, struct{ Key string }{"key"})
+		if err != nil {
+			t.Fatalf("Unexpected error when encoding custom ID: %s", err)
+		}
+		mod.HandleInteraction(NewTestModalSubmitInteraction(bot, "1", customID))
+		select {
+		case key := <-got:
+			if key != "key" {
+				t.Errorf("Expected payload key, got %v", key)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Modal submit was expected to run")
+		}
+	})
+
+	t.Run("forged custom id does not run", func(t *testing.T) {
+		bot := NewTestBot()
+		ran := make(chan bool, 1)
+		bot.AddHandler(func(*ModalSubmitRan) {
+			ran <- true
+		})
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmd := NewTestModalSubmit(mod)
+		mod.RegisterModalSubmits(cmd)
+
+		forged, _ := NewCustomIDCodec([]byte("other")).Encode(cmd.Name, cmd.Version, struct{ Key string }{"key"})
+		mod.HandleInteraction(NewTestModalSubmitInteraction(bot, "1", forged))
+		select {
+		case <-ran:
+			t.Errorf("Modal submit was not expected to run")
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
 }
 
 func TestModuleCommand_CooldownKey(t *testing.T) {
@@ -520,3 +679,217 @@ func TestModuleCommand_CooldownKey(t *testing.T) {
 		})
 	}
 }
+
+func TestModuleBase_HandlerContext(t *testing.T) {
+	t.Run("timeout emits CommandTimedOut", func(t *testing.T) {
+		bot := NewTestBot()
+		timedOut := make(chan *CommandTimedOut, 1)
+		bot.AddHandler(func(evt *CommandTimedOut) {
+			timedOut <- evt
+		})
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmd := NewTestCommand(mod)
+		cmd.Timeout = time.Millisecond * 10
+		cmd.Execute = func(msg *discord.DiscordMessage) {
+			<-msg.Context().Done()
+		}
+		mod.RegisterCommands(cmd)
+		mod.HandleMessage(NewTestMessage(bot, "1"))
+
+		select {
+		case evt := <-timedOut:
+			if evt.Invocation.Command != cmd || evt.Timeout != cmd.Timeout {
+				t.Errorf("CommandTimedOut has wrong data: %+v", evt)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("CommandTimedOut was not emitted")
+		}
+	})
+
+	t.Run("context is derived from bot context", func(t *testing.T) {
+		bot := NewTestBot()
+		ctx, cancel := context.WithCancel(context.Background())
+		bot.ctx = ctx
+		done := make(chan error, 1)
+		mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+		cmd := NewTestCommand(mod)
+		cmd.Execute = func(msg *discord.DiscordMessage) {
+			<-msg.Context().Done()
+			done <- msg.Context().Err()
+		}
+		mod.RegisterCommands(cmd)
+		mod.HandleMessage(NewTestMessage(bot, "1"))
+		cancel()
+
+		select {
+		case err := <-done:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("context error = %v, want %v", err, context.Canceled)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("command context was not cancelled")
+		}
+	})
+}
+
+func TestModuleBase_HandleAutocomplete(t *testing.T) {
+	t.Run("focused option handler runs", func(t *testing.T) {
+		bot, mod, sess := newRecordingTestBot()
+		cmd := NewModuleApplicationCommandBuilder(mod, "test").
+			Type(discordgo.ChatApplicationCommand).
+			Description("testing").
+			AddOption(&discordgo.ApplicationCommandOption{
+				Name:        "query",
+				Description: "query",
+				Type:        discordgo.ApplicationCommandOptionString,
+			}).
+			Autocomplete("query", func(it *discord.DiscordAutocomplete) {
+				_ = it.RespondStringChoices(it.Query()+"1", it.Query()+"2")
+			}).
+			Execute(testApplicationCommandRun).
+			Build()
+		if !cmd.Options[0].Autocomplete {
+			t.Errorf("Expected option to be marked for autocomplete")
+		}
+		mod.RegisterApplicationCommands(cmd)
+		mod.HandleInteraction(NewTestAutocompleteInteraction(bot, "1", "query", "w"))
+
+		resp := awaitResponse(t, sess)
+		if resp.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
+			t.Fatalf("Expected autocomplete result, got %v", resp.Type)
+		}
+		if len(resp.Data.Choices) != 2 || resp.Data.Choices[0].Value != "w1" {
+			t.Errorf("Unexpected choices: %v", resp.Data.Choices)
+		}
+	})
+
+	t.Run("option without handler gets no choices", func(t *testing.T) {
+		bot, mod, sess := newRecordingTestBot()
+		mod.RegisterApplicationCommands(NewTestApplicationCommand(mod))
+		mod.HandleInteraction(NewTestAutocompleteInteraction(bot, "1", "query", "w"))
+
+		resp := awaitResponse(t, sess)
+		if len(resp.Data.Choices) != 0 {
+			t.Errorf("Expected no choices, got %v", resp.Data.Choices)
+		}
+	})
+}
+
+func TestModuleBase_AutoDefer(t *testing.T) {
+	t.Run("slow command gets deferred", func(t *testing.T) {
+		bot, mod, sess := newRecordingTestBot()
+		cmd := NewTestApplicationCommand(mod)
+		cmd.AutoDefer = time.Millisecond * 10
+		cmd.Execute = func(it *discord.DiscordApplicationCommand) {
+			time.Sleep(time.Millisecond * 50)
+			_ = it.Respond("slow")
+		}
+		mod.RegisterApplicationCommands(cmd)
+		mod.HandleInteraction(NewTestApplicationCommandInteraction(bot, "1"))
+
+		resp := awaitResponse(t, sess)
+		if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+			t.Fatalf("Expected a deferred response, got %v", resp.Type)
+		}
+		select {
+		case edit := <-sess.edits:
+			if *edit.Content != "slow" {
+				t.Errorf("Expected the response to be edited in, got %v", *edit.Content)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected the deferred response to be edited")
+		}
+	})
+
+	t.Run("time spent queued counts", func(t *testing.T) {
+		bot, mod, sess := newRecordingTestBot()
+		cmd := NewTestApplicationCommand(mod)
+		cmd.AutoDefer = time.Second
+		ran := make(chan bool, 1)
+		cmd.Execute = func(it *discord.DiscordApplicationCommand) {
+			ran <- it.Acknowledged()
+		}
+		mod.RegisterApplicationCommands(cmd)
+		it := NewTestApplicationCommandInteraction(bot, "1")
+		it.TimeReceived = time.Now().Add(-time.Second * 2)
+		mod.HandleInteraction(it)
+
+		resp := awaitResponse(t, sess)
+		if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+			t.Fatalf("Expected a deferred response, got %v", resp.Type)
+		}
+		if !<-ran {
+			t.Errorf("Expected the interaction to be deferred before the command ran")
+		}
+	})
+
+	t.Run("fast command does not get deferred", func(t *testing.T) {
+		bot, mod, sess := newRecordingTestBot()
+		cmd := NewTestApplicationCommand(mod)
+		cmd.AutoDefer = time.Millisecond * 20
+		cmd.Execute = func(it *discord.DiscordApplicationCommand) {
+			_ = it.Respond("fast")
+		}
+		mod.RegisterApplicationCommands(cmd)
+		mod.HandleInteraction(NewTestApplicationCommandInteraction(bot, "1"))
+
+		resp := awaitResponse(t, sess)
+		if resp.Type != discordgo.InteractionResponseChannelMessageWithSource {
+			t.Fatalf("Expected a message response, got %v", resp.Type)
+		}
+		select {
+		case resp := <-sess.responses:
+			t.Errorf("Expected no more responses, got %v", resp.Type)
+		case <-time.After(time.Millisecond * 50):
+		}
+	})
+}
+
+func TestModuleBase_HandleSubcommand(t *testing.T) {
+	bot := NewTestBot()
+	mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+	ran := make(chan string, 1)
+	route := func(name string) SubcommandHandler {
+		return func(sub *discord.DiscordSubcommand) error {
+			user, _ := sub.User("user")
+			ran <- name + " " + user.ID
+			return nil
+		}
+	}
+	cmd := NewModuleApplicationCommandBuilder(mod, "test").
+		Type(discordgo.ChatApplicationCommand).
+		Description("testing").
+		AddSubcommand(&discordgo.ApplicationCommandOption{Name: "plain", Description: "plain"}, route("plain")).
+		AddSubcommandGroup(NewSubcommandGroup("user", "user").
+			AddSubcommand(&discordgo.ApplicationCommandOption{Name: "get", Description: "get"}, route("user:get")).
+			AddSubcommand(&discordgo.ApplicationCommandOption{Name: "edit", Description: "edit"}, route("user:edit"))).
+		Build()
+	mod.RegisterApplicationCommands(cmd)
+
+	it := NewTestApplicationCommandInteraction(bot, "1")
+	it.Interaction.Data = discordgo.ApplicationCommandInteractionData{
+		Name:        "test",
+		CommandType: discordgo.ChatApplicationCommand,
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+			Name: "user",
+			Type: discordgo.ApplicationCommandOptionSubCommandGroup,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+				Name: "edit",
+				Type: discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "42"},
+				},
+			}},
+		}},
+	}
+	mod.HandleInteraction(it)
+
+	select {
+	case got := <-ran:
+		if got != "user:edit 42" {
+			t.Errorf("Expected user:edit to run with its user, got %v", got)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Subcommand was expected to run")
+	}
+}
@@ -0,0 +1,395 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/intrntsrfr/meido/pkg/utils/builders"
+)
+
+// DefaultPaginatorTimeout is how long the buttons of a paginator keep working
+// after they were last used, if its ModulePages does not set a timeout.
+const DefaultPaginatorTimeout = time.Minute * 2
+
+var (
+	ErrNoPages         = errors.New("page source has no pages")
+	ErrPagesNotFound   = errors.New("pages not found")
+	ErrPaginatorModule = errors.New("paginator module must be built on ModuleBase")
+)
+
+// PageSource provides the pages of a Paginator.
+type PageSource interface {
+	// PageCount returns the number of pages.
+	PageCount() int
+	// Page renders the page at index, starting from 0.
+	Page(index int) (*discordgo.MessageEmbed, error)
+}
+
+// EmbedPages is a PageSource of embeds that are already rendered.
+type EmbedPages []*discordgo.MessageEmbed
+
+func (p EmbedPages) PageCount() int {
+	return len(p)
+}
+
+func (p EmbedPages) Page(index int) (*discordgo.MessageEmbed, error) {
+	return p[index], nil
+}
+
+// PageFunc is a PageSource that renders its pages when they are shown.
+type PageFunc struct {
+	Count  int
+	Render func(index int) (*discordgo.MessageEmbed, error)
+}
+
+func (p PageFunc) PageCount() int {
+	return p.Count
+}
+
+func (p PageFunc) Page(index int) (*discordgo.MessageEmbed, error) {
+	return p.Render(index)
+}
+
+// PageCount returns how many pages it takes to show n items, perPage at a time.
+func PageCount(n, perPage int) int {
+	if n <= 0 || perPage <= 0 {
+		return 0
+	}
+	return (n + perPage - 1) / perPage
+}
+
+// PagesFunc builds the pages of a paginator again when one of its buttons is
+// used. args carries what the paginator was given with Paginator.Args.
+type PagesFunc func(it *discord.DiscordInteraction, args *ComponentArgs) (PageSource, error)
+
+// ModulePages handles the buttons of one kind of paginator. A paginator keeps the
+// page it shows, the user it was started for and its Args in the signed custom
+// IDs of its buttons, and Pages builds its pages again when a button is used, so
+// paginators keep working after a restart. See ModuleBase.RegisterPages.
+type ModulePages struct {
+	Mod  Module
+	Name string
+	// Version is encoded into the custom IDs of the buttons, see
+	// ModuleMessageComponent.Version.
+	Version int
+	// Timeout is how long the buttons keep working after they were last used. It
+	// is DefaultPaginatorTimeout if it is 0.
+	Timeout time.Duration
+	Pages   PagesFunc
+
+	codec *CustomIDCodec
+}
+
+const (
+	paginatorFirst = iota
+	paginatorPrev
+	paginatorJump
+	paginatorNext
+	paginatorLast
+	paginatorStop
+
+	paginatorPageInput = "page"
+)
+
+// paginatorState is what the custom IDs of the buttons of a paginator carry.
+// Every button has its own Action, as custom IDs in a message must be unique.
+type paginatorState struct {
+	Action  int
+	Page    int
+	Expires int64
+	Author  string
+	Args    []json.RawMessage
+}
+
+// pagesModule is implemented by modules built on ModuleBase.
+type pagesModule interface {
+	Module
+	FindPages(name string) (*ModulePages, error)
+}
+
+// Paginator shows the pages of a PageSource one at a time, with buttons to move
+// between them. Only the user it was started for can use the buttons, and they
+// stop working once they have not been used for the timeout of its ModulePages.
+type Paginator struct {
+	mod    Module
+	name   string
+	source PageSource
+	args   any
+	page   int
+}
+
+// NewPaginator returns a paginator of the ModulePages called name in mod, showing
+// source.
+func NewPaginator(mod Module, name string, source PageSource) *Paginator {
+	return &Paginator{
+		mod:    mod,
+		name:   name,
+		source: source,
+	}
+}
+
+// Args sets what the Pages of the paginator get to build its pages again. args
+// is a struct, or a pointer to one, and is encoded into the custom IDs of the
+// buttons, so it should be small.
+func (p *Paginator) Args(args any) *Paginator {
+	p.args = args
+	return p
+}
+
+// StartPage sets the page shown first, starting from 0.
+func (p *Paginator) StartPage(index int) *Paginator {
+	p.page = index
+	return p
+}
+
+// Send replies to msg with the first page, for its author to control.
+func (p *Paginator) Send(msg *discord.DiscordMessage) error {
+	embed, rows, err := p.start(msg.AuthorID())
+	if err != nil {
+		return err
+	}
+	builder := builders.NewMessageSendBuilder().Embed(embed)
+	for _, row := range rows {
+		builder.AddActionRow(row)
+	}
+	_, err = msg.ReplyComplex(builder.Build())
+	return err
+}
+
+// Respond responds to it with the first page, for the user of the interaction to
+// control.
+func (p *Paginator) Respond(it *discord.DiscordInteraction) error {
+	embed, rows, err := p.start(it.AuthorID())
+	if err != nil {
+		return err
+	}
+	return it.RespondComplex(&discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: messageComponents(rows),
+	}, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+// start renders the first page and its buttons.
+func (p *Paginator) start(authorID string) (*discordgo.MessageEmbed, []*discordgo.ActionsRow, error) {
+	mod, ok := p.mod.(pagesModule)
+	if !ok {
+		return nil, nil, ErrPaginatorModule
+	}
+	pages, err := mod.FindPages(p.name)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := paginatorState{Author: authorID}
+	if p.args != nil {
+		if state.Args, err = rawPayload(p.args); err != nil {
+			return nil, nil, err
+		}
+	}
+	return pages.render(p.source, p.page, state)
+}
+
+func (p *ModulePages) component() *ModuleMessageComponent {
+	return &ModuleMessageComponent{
+		Mod:           p.Mod,
+		Name:          p.Name,
+		Version:       p.Version,
+		CooldownScope: CooldownScopeNone,
+		UserType:      UserTypeAny,
+		Enabled:       true,
+		ExecuteArgs:   p.handleComponent,
+	}
+}
+
+func (p *ModulePages) modalSubmit() *ModuleModalSubmit {
+	return &ModuleModalSubmit{
+		Mod:         p.Mod,
+		Name:        p.Name,
+		Version:     p.Version,
+		Enabled:     true,
+		ExecuteArgs: p.handleModalSubmit,
+	}
+}
+
+// render renders the page at index of source, and the buttons to move from it.
+// There are no buttons if there is only one page.
+func (p *ModulePages) render(source PageSource, index int, state paginatorState) (*discordgo.MessageEmbed, []*discordgo.ActionsRow, error) {
+	count := source.PageCount()
+	if count < 1 {
+		return nil, nil, ErrNoPages
+	}
+	index = max(0, min(index, count-1))
+	embed, err := source.Page(index)
+	if err != nil || count == 1 {
+		return embed, nil, err
+	}
+
+	state.Page = index
+	state.Expires = time.Now().Add(p.Timeout).Unix()
+	ids := make([]string, paginatorStop+1)
+	for action := range ids {
+		state.Action = action
+		if ids[action], err = p.codec.Encode(p.Name, p.Version, &state); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	last := count - 1
+	button := func(label string, action int, disabled bool) *discordgo.Button {
+		style := discordgo.PrimaryButton
+		if action == paginatorJump {
+			style = discordgo.SecondaryButton
+		}
+		return &discordgo.Button{Label: label, Style: style, CustomID: ids[action], Disabled: disabled}
+	}
+	nav := builders.NewActionRowBuilder().
+		AddComponent(button("⏮️", paginatorFirst, index == 0)).
+		AddComponent(button("◀️", paginatorPrev, index == 0)).
+		AddComponent(button(fmt.Sprintf("%v / %v", index+1, count), paginatorJump, false)).
+		AddComponent(button("▶️", paginatorNext, index == last)).
+		AddComponent(button("⏭️", paginatorLast, index == last)).
+		Build()
+	stop := builders.NewActionRowBuilder().
+		AddButton("Stop", discordgo.DangerButton, ids[paginatorStop]).
+		Build()
+	return embed, []*discordgo.ActionsRow{nav, stop}, nil
+}
+
+func messageComponents(rows []*discordgo.ActionsRow) []discordgo.MessageComponent {
+	components := []discordgo.MessageComponent{}
+	for _, row := range rows {
+		components = append(components, row)
+	}
+	return components
+}
+
+// state decodes the state of the paginator args belongs to, and checks that the
+// user of it may use the paginator. it is responded to if they may not.
+func (p *ModulePages) state(it *discord.DiscordInteraction, args *ComponentArgs) (paginatorState, bool) {
+	var state paginatorState
+	if err := args.Decode(&state); err != nil {
+		_ = it.RespondEphemeral("This has expired, please try again")
+		return state, false
+	}
+	if it.AuthorID() != state.Author {
+		_ = it.RespondEphemeral("Only the person who used the command can use these buttons")
+		return state, false
+	}
+	if time.Now().Unix() > state.Expires {
+		p.removeButtons(it)
+		return state, false
+	}
+	return state, true
+}
+
+// source builds the pages of the paginator with state again.
+func (p *ModulePages) source(it *discord.DiscordInteraction, state paginatorState) (PageSource, bool) {
+	source, err := p.Pages(it, &ComponentArgs{Name: p.Name, Version: p.Version, values: state.Args})
+	if err != nil {
+		_ = it.RespondEphemeral("There was an issue, please try again!")
+		return nil, false
+	}
+	return source, true
+}
+
+func (p *ModulePages) handleComponent(it *discord.DiscordMessageComponent, args *ComponentArgs) {
+	state, ok := p.state(it.DiscordInteraction, args)
+	if !ok {
+		return
+	}
+	switch state.Action {
+	case paginatorJump:
+		p.openJumpModal(it, state)
+		return
+	case paginatorStop:
+		p.removeButtons(it.DiscordInteraction)
+		return
+	}
+
+	source, ok := p.source(it.DiscordInteraction, state)
+	if !ok {
+		return
+	}
+	page := state.Page
+	switch state.Action {
+	case paginatorFirst:
+		page = 0
+	case paginatorPrev:
+		page--
+	case paginatorNext:
+		page++
+	case paginatorLast:
+		page = source.PageCount() - 1
+	}
+	p.show(it.DiscordInteraction, source, page, state)
+}
+
+func (p *ModulePages) openJumpModal(it *discord.DiscordMessageComponent, state paginatorState) {
+	customID, err := p.codec.Encode(p.Name, p.Version, &state)
+	if err != nil {
+		_ = it.RespondEphemeral("There was an issue, please try again!")
+		return
+	}
+	_ = it.RespondModal(builders.NewModalBuilder(customID, "Jump to page").
+		AddTextInput(&discordgo.TextInput{
+			CustomID:    paginatorPageInput,
+			Label:       "Page",
+			Style:       discordgo.TextInputShort,
+			Placeholder: strconv.Itoa(state.Page + 1),
+			Required:    true,
+			MaxLength:   6,
+		}).
+		Build())
+}
+
+func (p *ModulePages) handleModalSubmit(it *discord.DiscordModalSubmit, args *ComponentArgs) {
+	state, ok := p.state(it.DiscordInteraction, args)
+	if !ok {
+		return
+	}
+	source, ok := p.source(it.DiscordInteraction, state)
+	if !ok {
+		return
+	}
+	count := source.PageCount()
+	page, err := it.FieldInt(paginatorPageInput)
+	if err != nil || page < 1 || page > count {
+		_ = it.RespondEphemeral(fmt.Sprintf("Pick a page between 1 and %v", count))
+		return
+	}
+	p.show(it.DiscordInteraction, source, page-1, state)
+}
+
+// show updates the paginator message to show the page at index of source.
+func (p *ModulePages) show(it *discord.DiscordInteraction, source PageSource, index int, state paginatorState) {
+	embed, rows, err := p.render(source, index, state)
+	if errors.Is(err, ErrNoPages) {
+		p.removeButtons(it)
+		return
+	}
+	if err != nil {
+		_ = it.RespondEphemeral("There was an issue, please try again!")
+		return
+	}
+	_ = it.RespondComplex(&discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: messageComponents(rows),
+	}, discordgo.InteractionResponseUpdateMessage)
+}
+
+// removeButtons removes the buttons from the paginator message, and keeps the
+// page it shows.
+func (p *ModulePages) removeButtons(it *discord.DiscordInteraction) {
+	var embeds []*discordgo.MessageEmbed
+	if it.Interaction.Message != nil {
+		embeds = it.Interaction.Message.Embeds
+	}
+	_ = it.RespondComplex(&discordgo.InteractionResponseData{
+		Embeds:     embeds,
+		Components: []discordgo.MessageComponent{},
+	}, discordgo.InteractionResponseUpdateMessage)
+}
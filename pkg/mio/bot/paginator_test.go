@@ -0,0 +1,178 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPages(n int) EmbedPages {
+	pages := make(EmbedPages, n)
+	for i := range pages {
+		pages[i] = &discordgo.MessageEmbed{Title: string(rune('a' + i))}
+	}
+	return pages
+}
+
+func TestPageCount(t *testing.T) {
+	assert.Equal(t, 0, PageCount(0, 10))
+	assert.Equal(t, 1, PageCount(1, 10))
+	assert.Equal(t, 1, PageCount(10, 10))
+	assert.Equal(t, 2, PageCount(11, 10))
+	assert.Equal(t, 0, PageCount(5, 0))
+}
+
+func registerTestPages(t *testing.T, mod *testModule, n int) {
+	t.Helper()
+	assert.NoError(t, mod.RegisterPages(&ModulePages{
+		Mod:  mod,
+		Name: "pages",
+		Pages: func(it *discord.DiscordInteraction, args *ComponentArgs) (PageSource, error) {
+			return newTestPages(n), nil
+		},
+	}))
+}
+
+// buttonID returns the custom ID of the paginator button for action in resp.
+func buttonID(resp *discordgo.InteractionResponse, action int) string {
+	if action == paginatorStop {
+		return resp.Data.Components[1].(*discordgo.ActionsRow).Components[0].(*discordgo.Button).CustomID
+	}
+	return resp.Data.Components[0].(*discordgo.ActionsRow).Components[action].(*discordgo.Button).CustomID
+}
+
+func TestPaginator_Navigation(t *testing.T) {
+	bot, mod, sess := newRecordingTestBot()
+	registerTestPages(t, mod, 4)
+	assert.NoError(t, NewPaginator(mod, "pages", newTestPages(4)).Respond(newTestInteraction(bot, "1")))
+
+	resp := awaitResponse(t, sess)
+	assert.Equal(t, discordgo.InteractionResponseChannelMessageWithSource, resp.Type)
+	assert.Equal(t, "a", resp.Data.Embeds[0].Title)
+	assert.Len(t, resp.Data.Components, 2)
+
+	click := func(action int) *discordgo.InteractionResponse {
+		bot.EventHandler.HandleInteraction(NewTestMessageComponentInteraction(bot, "1", buttonID(resp, action)))
+		return awaitResponse(t, sess)
+	}
+
+	resp = click(paginatorNext)
+	assert.Equal(t, discordgo.InteractionResponseUpdateMessage, resp.Type)
+	assert.Equal(t, "b", resp.Data.Embeds[0].Title)
+
+	resp = click(paginatorLast)
+	assert.Equal(t, "d", resp.Data.Embeds[0].Title)
+	nav := resp.Data.Components[0].(*discordgo.ActionsRow)
+	assert.True(t, nav.Components[3].(*discordgo.Button).Disabled)
+	assert.Equal(t, "4 / 4", nav.Components[2].(*discordgo.Button).Label)
+
+	resp = click(paginatorPrev)
+	assert.Equal(t, "c", resp.Data.Embeds[0].Title)
+
+	resp = click(paginatorFirst)
+	assert.Equal(t, "a", resp.Data.Embeds[0].Title)
+
+	modal := click(paginatorJump)
+	assert.Equal(t, discordgo.InteractionResponseModal, modal.Type)
+
+	submit := NewTestModalSubmitInteraction(bot, "1", modal.Data.CustomID)
+	submit.Interaction.Data = discordgo.ModalSubmitInteractionData{
+		CustomID: modal.Data.CustomID,
+		Components: []discordgo.MessageComponent{&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			&discordgo.TextInput{CustomID: paginatorPageInput, Value: "3"},
+		}}},
+	}
+	bot.EventHandler.HandleInteraction(submit)
+	resp = awaitResponse(t, sess)
+	assert.Equal(t, "c", resp.Data.Embeds[0].Title)
+
+	resp = click(paginatorStop)
+	assert.Equal(t, discordgo.InteractionResponseUpdateMessage, resp.Type)
+	assert.Empty(t, resp.Data.Components)
+}
+
+func TestPaginator_AuthorOnly(t *testing.T) {
+	bot, mod, sess := newRecordingTestBot()
+	registerTestPages(t, mod, 2)
+	assert.NoError(t, NewPaginator(mod, "pages", newTestPages(2)).Respond(newTestInteraction(bot, "1")))
+	resp := awaitResponse(t, sess)
+
+	it := NewTestMessageComponentInteraction(bot, "1", buttonID(resp, paginatorNext))
+	it.Interaction.Member.User = &discordgo.User{ID: "2"}
+	bot.EventHandler.HandleInteraction(it)
+
+	resp = awaitResponse(t, sess)
+	assert.Equal(t, discordgo.MessageFlagsEphemeral, resp.Data.Flags)
+}
+
+func TestPaginator_Expires(t *testing.T) {
+	bot, mod, sess := newRecordingTestBot()
+	registerTestPages(t, mod, 2)
+	customID, err := bot.CustomIDs.Encode("pages", 0, &paginatorState{Action: paginatorNext, Expires: time.Now().Add(-time.Minute).Unix()})
+	assert.NoError(t, err)
+
+	it := NewTestMessageComponentInteraction(bot, "1", customID)
+	it.Interaction.Message = &discordgo.Message{Embeds: []*discordgo.MessageEmbed{{Title: "a"}}}
+	bot.EventHandler.HandleInteraction(it)
+
+	resp := awaitResponse(t, sess)
+	assert.Equal(t, discordgo.InteractionResponseUpdateMessage, resp.Type)
+	assert.Equal(t, "a", resp.Data.Embeds[0].Title)
+	assert.Empty(t, resp.Data.Components)
+}
+
+func TestPaginator_SurvivesRestart(t *testing.T) {
+	bot, mod, sess := newRecordingTestBot()
+	registerTestPages(t, mod, 3)
+	assert.NoError(t, NewPaginator(mod, "pages", newTestPages(3)).StartPage(1).Respond(newTestInteraction(bot, "1")))
+	resp := awaitResponse(t, sess)
+
+	// a new bot with the same secret picks up where the old one left off
+	restarted, restartedMod, restartedSess := newRecordingTestBot()
+	restarted.CustomIDs = bot.CustomIDs
+	registerTestPages(t, restartedMod, 3)
+	restarted.EventHandler.HandleInteraction(NewTestMessageComponentInteraction(restarted, "1", buttonID(resp, paginatorNext)))
+
+	resp = awaitResponse(t, restartedSess)
+	assert.Equal(t, "c", resp.Data.Embeds[0].Title)
+}
+
+func TestPaginator_Args(t *testing.T) {
+	bot, mod, sess := newRecordingTestBot()
+	type pageArgs struct {
+		Count int
+	}
+	assert.NoError(t, mod.RegisterPages(&ModulePages{
+		Mod:  mod,
+		Name: "pages",
+		Pages: func(it *discord.DiscordInteraction, args *ComponentArgs) (PageSource, error) {
+			var pa pageArgs
+			if err := args.Decode(&pa); err != nil {
+				return nil, err
+			}
+			return newTestPages(pa.Count), nil
+		},
+	}))
+	p := NewPaginator(mod, "pages", newTestPages(5)).Args(&pageArgs{Count: 5})
+	assert.NoError(t, p.Respond(newTestInteraction(bot, "1")))
+	resp := awaitResponse(t, sess)
+
+	bot.EventHandler.HandleInteraction(NewTestMessageComponentInteraction(bot, "1", buttonID(resp, paginatorLast)))
+	resp = awaitResponse(t, sess)
+	assert.Equal(t, "e", resp.Data.Embeds[0].Title)
+}
+
+func TestPaginator_SinglePage(t *testing.T) {
+	bot, mod, sess := newRecordingTestBot()
+	registerTestPages(t, mod, 1)
+	assert.NoError(t, NewPaginator(mod, "pages", newTestPages(1)).Respond(newTestInteraction(bot, "1")))
+
+	resp := awaitResponse(t, sess)
+	assert.Empty(t, resp.Data.Components)
+
+	assert.ErrorIs(t, NewPaginator(mod, "pages", EmbedPages{}).Respond(newTestInteraction(bot, "1")), ErrNoPages)
+	assert.ErrorIs(t, NewPaginator(mod, "other", newTestPages(1)).Respond(newTestInteraction(bot, "1")), ErrPagesNotFound)
+}
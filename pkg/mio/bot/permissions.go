@@ -0,0 +1,231 @@
+package bot
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// PermissionTarget is what a PermissionRule applies to.
+type PermissionTarget string
+
+const (
+	PermissionTargetRole    PermissionTarget = "role"
+	PermissionTargetChannel PermissionTarget = "channel"
+	PermissionTargetUser    PermissionTarget = "user"
+)
+
+var ErrInvalidPermissionTarget = errors.New("invalid permission target")
+
+// ParsePermissionTarget returns the PermissionTarget named by s.
+func ParsePermissionTarget(s string) (PermissionTarget, error) {
+	switch t := PermissionTarget(strings.ToLower(s)); t {
+	case PermissionTargetRole, PermissionTargetChannel, PermissionTargetUser:
+		return t, nil
+	}
+	return "", ErrInvalidPermissionTarget
+}
+
+// PermissionRule allows or denies a role, channel or user the use of a command
+// in a guild. Rules of a command also apply to the application command with the same name.
+type PermissionRule struct {
+	GuildID  string
+	Command  string
+	Target   PermissionTarget
+	TargetID string
+	Allow    bool
+}
+
+// PermissionDecision is the outcome of evaluating the rules of a command.
+type PermissionDecision int
+
+const (
+	// PermissionDefault means no rule applies, so the permissions required by
+	// the command decide.
+	PermissionDefault PermissionDecision = iota
+	// PermissionAllow lets the user run the command without the permissions it requires.
+	PermissionAllow
+	// PermissionDeny stops the user from running the command.
+	PermissionDeny
+)
+
+// EvaluatePermissionRules decides whether a user with roleIDs may run a command in a
+// channel, given the rules of the command. The @everyone role has the ID of the guild.
+//
+// Channel rules are checked first: a command is denied in channels with a deny rule,
+// and if any channel has an allow rule, only those channels may use it. Then a user
+// rule decides, if there is one. Otherwise, an allow rule for any of the roles
+// allows the command, before a deny rule for any of them denies it.
+func EvaluatePermissionRules(rules []*PermissionRule, channelID, userID string, roleIDs []string) PermissionDecision {
+	var (
+		channelAllowed, hasChannelAllows bool
+		user                             *PermissionRule
+		roleAllow, roleDeny              bool
+	)
+	for _, r := range rules {
+		switch r.Target {
+		case PermissionTargetChannel:
+			if r.Allow {
+				hasChannelAllows = true
+			}
+			if r.TargetID == channelID {
+				if !r.Allow {
+					return PermissionDeny
+				}
+				channelAllowed = true
+			}
+		case PermissionTargetUser:
+			if r.TargetID == userID {
+				user = r
+			}
+		case PermissionTargetRole:
+			for _, id := range roleIDs {
+				if r.TargetID == id {
+					roleAllow = roleAllow || r.Allow
+					roleDeny = roleDeny || !r.Allow
+				}
+			}
+		}
+	}
+
+	switch {
+	case hasChannelAllows && !channelAllowed:
+		return PermissionDeny
+	case user != nil && user.Allow:
+		return PermissionAllow
+	case user != nil:
+		return PermissionDeny
+	case roleAllow:
+		return PermissionAllow
+	case roleDeny:
+		return PermissionDeny
+	}
+	return PermissionDefault
+}
+
+// PermissionStore keeps the permission rules of commands in each guild. Command
+// names are case-insensitive.
+type PermissionStore interface {
+	// Rules returns the rules of a command in a guild.
+	Rules(guildID, command string) ([]*PermissionRule, error)
+	// GuildRules returns every rule in a guild, sorted by command.
+	GuildRules(guildID string) ([]*PermissionRule, error)
+	// SetRule adds a rule, replacing any rule of the command for the same target.
+	SetRule(rule *PermissionRule) error
+	// RemoveRule removes the rule of a command for a target.
+	RemoveRule(guildID, command string, target PermissionTarget, targetID string) error
+	// ClearRules removes every rule of a command in a guild.
+	ClearRules(guildID, command string) error
+}
+
+// MemoryPermissionStore is a PermissionStore that keeps rules in memory.
+type MemoryPermissionStore struct {
+	sync.RWMutex
+	rules map[string][]*PermissionRule
+}
+
+func NewMemoryPermissionStore() *MemoryPermissionStore {
+	return &MemoryPermissionStore{
+		rules: make(map[string][]*PermissionRule),
+	}
+}
+
+func (s *MemoryPermissionStore) Rules(guildID, command string) ([]*PermissionRule, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return FilterPermissionRules(s.rules[guildID], command), nil
+}
+
+func (s *MemoryPermissionStore) GuildRules(guildID string) ([]*PermissionRule, error) {
+	s.RLock()
+	defer s.RUnlock()
+	rules := append([]*PermissionRule(nil), s.rules[guildID]...)
+	SortPermissionRules(rules)
+	return rules, nil
+}
+
+func (s *MemoryPermissionStore) SetRule(rule *PermissionRule) error {
+	s.Lock()
+	defer s.Unlock()
+	r := *rule
+	r.Command = strings.ToLower(r.Command)
+	rules := removePermissionRule(s.rules[r.GuildID], r.Command, r.Target, r.TargetID)
+	s.rules[r.GuildID] = append(rules, &r)
+	return nil
+}
+
+func (s *MemoryPermissionStore) RemoveRule(guildID, command string, target PermissionTarget, targetID string) error {
+	s.Lock()
+	defer s.Unlock()
+	s.rules[guildID] = removePermissionRule(s.rules[guildID], strings.ToLower(command), target, targetID)
+	return nil
+}
+
+func (s *MemoryPermissionStore) ClearRules(guildID, command string) error {
+	s.Lock()
+	defer s.Unlock()
+	var rules []*PermissionRule
+	for _, r := range s.rules[guildID] {
+		if !strings.EqualFold(r.Command, command) {
+			rules = append(rules, r)
+		}
+	}
+	s.rules[guildID] = rules
+	return nil
+}
+
+func removePermissionRule(rules []*PermissionRule, command string, target PermissionTarget, targetID string) []*PermissionRule {
+	var kept []*PermissionRule
+	for _, r := range rules {
+		if r.Command != command || r.Target != target || r.TargetID != targetID {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// FilterPermissionRules returns the rules that belong to command.
+func FilterPermissionRules(rules []*PermissionRule, command string) []*PermissionRule {
+	var filtered []*PermissionRule
+	for _, r := range rules {
+		if strings.EqualFold(r.Command, command) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// SortPermissionRules sorts rules by command, target and target ID.
+func SortPermissionRules(rules []*PermissionRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.Command != b.Command {
+			return a.Command < b.Command
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.TargetID < b.TargetID
+	})
+}
+
+// permissionDecision evaluates the rules of a command for a member in a channel.
+// No rules apply in DMs, if command is empty, or if the rules could not be fetched.
+func (b *Bot) permissionDecision(guildID, command, channelID string, member *discordgo.Member) PermissionDecision {
+	if guildID == "" || command == "" || member == nil || member.User == nil || b.Permissions == nil {
+		return PermissionDefault
+	}
+	rules, err := b.Permissions.Rules(guildID, command)
+	if err != nil {
+		b.Logger.Error("Could not get permission rules", "guildID", guildID, "command", command, "error", err)
+		return PermissionDefault
+	}
+	if len(rules) == 0 {
+		return PermissionDefault
+	}
+	roles := append([]string{guildID}, member.Roles...)
+	return EvaluatePermissionRules(rules, channelID, member.User.ID, roles)
+}
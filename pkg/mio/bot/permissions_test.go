@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluatePermissionRules(t *testing.T) {
+	rule := func(target PermissionTarget, id string, allow bool) *PermissionRule {
+		return &PermissionRule{GuildID: "1", Command: "fish", Target: target, TargetID: id, Allow: allow}
+	}
+	tests := []struct {
+		name  string
+		rules []*PermissionRule
+		want  PermissionDecision
+	}{
+		{"no rules", nil, PermissionDefault},
+		{"channel denied", []*PermissionRule{rule(PermissionTargetChannel, "10", false)}, PermissionDeny},
+		{"other channel denied", []*PermissionRule{rule(PermissionTargetChannel, "11", false)}, PermissionDefault},
+		{"channel allowed", []*PermissionRule{rule(PermissionTargetChannel, "10", true)}, PermissionDefault},
+		{"only other channel allowed", []*PermissionRule{rule(PermissionTargetChannel, "11", true)}, PermissionDeny},
+		{"user allowed", []*PermissionRule{rule(PermissionTargetUser, "20", true)}, PermissionAllow},
+		{"user denied", []*PermissionRule{rule(PermissionTargetUser, "20", false)}, PermissionDeny},
+		{"role allowed", []*PermissionRule{rule(PermissionTargetRole, "30", true)}, PermissionAllow},
+		{"role denied", []*PermissionRule{rule(PermissionTargetRole, "30", false)}, PermissionDeny},
+		{"role allow beats role deny", []*PermissionRule{
+			rule(PermissionTargetRole, "1", false),
+			rule(PermissionTargetRole, "30", true),
+		}, PermissionAllow},
+		{"user beats role", []*PermissionRule{
+			rule(PermissionTargetRole, "30", true),
+			rule(PermissionTargetUser, "20", false),
+		}, PermissionDeny},
+		{"channel beats user", []*PermissionRule{
+			rule(PermissionTargetUser, "20", true),
+			rule(PermissionTargetChannel, "10", false),
+		}, PermissionDeny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluatePermissionRules(tt.rules, "10", "20", []string{"1", "30"})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMemoryPermissionStore(t *testing.T) {
+	s := NewMemoryPermissionStore()
+	_ = s.SetRule(&PermissionRule{GuildID: "1", Command: "Fish", Target: PermissionTargetChannel, TargetID: "10", Allow: true})
+	_ = s.SetRule(&PermissionRule{GuildID: "1", Command: "fish", Target: PermissionTargetChannel, TargetID: "10", Allow: false})
+	_ = s.SetRule(&PermissionRule{GuildID: "1", Command: "warn", Target: PermissionTargetRole, TargetID: "30", Allow: true})
+
+	rules, err := s.Rules("1", "fish")
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	assert.False(t, rules[0].Allow)
+
+	rules, _ = s.GuildRules("1")
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "fish", rules[0].Command)
+
+	_ = s.RemoveRule("1", "warn", PermissionTargetRole, "30")
+	rules, _ = s.Rules("1", "warn")
+	assert.Empty(t, rules)
+
+	_ = s.ClearRules("1", "FISH")
+	rules, _ = s.GuildRules("1")
+	assert.Empty(t, rules)
+}
+
+func TestModuleBase_HandleCommand_PermissionRules(t *testing.T) {
+	bot := NewTestBot()
+	mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+	called := make(chan bool, 1)
+	cmd := NewTestCommand(mod)
+	cmd.Execute = func(*discord.DiscordMessage) {
+		called <- true
+	}
+	_ = mod.RegisterCommands(cmd)
+	_ = bot.Permissions.SetRule(&PermissionRule{GuildID: "1", Command: "test", Target: PermissionTargetChannel, TargetID: "1"})
+
+	mod.HandleMessage(NewTestMessage(bot, "1"))
+	select {
+	case <-called:
+		t.Errorf("Command was not expected to be called")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	mod.HandleMessage(NewTestMessage(bot, "2"))
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Errorf("Command was expected to be called")
+	}
+}
+
+func TestModuleBase_HandleInteraction_PermissionRules(t *testing.T) {
+	bot := NewTestBot()
+	mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+	called := make(chan string, 2)
+	comp := NewTestMessageComponent(mod)
+	comp.Permissions = discordgo.PermissionBanMembers
+	comp.Command = "test"
+	comp.Execute = func(*discord.DiscordMessageComponent) {
+		called <- "component"
+	}
+	modal := NewTestModalSubmit(mod)
+	modal.Permissions = discordgo.PermissionBanMembers
+	modal.Command = "test"
+	modal.Execute = func(*discord.DiscordModalSubmit) {
+		called <- "modal"
+	}
+	_ = mod.RegisterMessageComponents(comp)
+	_ = mod.RegisterModalSubmits(modal)
+	mod.SetMessageComponentCallback("test", "test")
+	mod.SetModalSubmitCallback("test", "test")
+
+	send := func() {
+		mod.HandleInteraction(NewTestMessageComponentInteraction(bot, "1", "test"))
+		mod.HandleInteraction(NewTestModalSubmitInteraction(bot, "1", "test"))
+	}
+
+	send()
+	select {
+	case name := <-called:
+		t.Errorf("%v was not expected to be called without permissions", name)
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	// the guild ID is the ID of the everyone role
+	_ = bot.Permissions.SetRule(&PermissionRule{GuildID: "1", Command: "test", Target: PermissionTargetRole, TargetID: "1", Allow: true})
+	send()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Fatal("handlers were expected to be called with an allow rule")
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultPrefix is the prefix used when neither the config nor a guild
+// specifies one.
+const DefaultPrefix = "m?"
+
+// PrefixResolver decides which command prefix is used in a guild.
+type PrefixResolver interface {
+	// Prefix returns the prefix for a guild. An empty guildID means a DM.
+	Prefix(guildID string) string
+	// SetPrefix changes the prefix for a guild. An empty prefix resets it
+	// to the default.
+	SetPrefix(guildID, prefix string) error
+	// DefaultPrefix returns the prefix used when a guild has not set one.
+	DefaultPrefix() string
+}
+
+// MemoryPrefixResolver is a PrefixResolver that keeps guild prefixes in memory.
+type MemoryPrefixResolver struct {
+	sync.RWMutex
+	defaultPrefix string
+	prefixes      map[string]string
+}
+
+func NewMemoryPrefixResolver(defaultPrefix string) *MemoryPrefixResolver {
+	if defaultPrefix == "" {
+		defaultPrefix = DefaultPrefix
+	}
+	return &MemoryPrefixResolver{
+		defaultPrefix: defaultPrefix,
+		prefixes:      make(map[string]string),
+	}
+}
+
+func (r *MemoryPrefixResolver) Prefix(guildID string) string {
+	r.RLock()
+	defer r.RUnlock()
+	if p, ok := r.prefixes[guildID]; ok {
+		return p
+	}
+	return r.defaultPrefix
+}
+
+func (r *MemoryPrefixResolver) SetPrefix(guildID, prefix string) error {
+	r.Lock()
+	defer r.Unlock()
+	if prefix == "" {
+		delete(r.prefixes, guildID)
+		return nil
+	}
+	r.prefixes[guildID] = prefix
+	return nil
+}
+
+func (r *MemoryPrefixResolver) DefaultPrefix() string {
+	return r.defaultPrefix
+}
+
+// matchPrefix checks whether content starts with either the given prefix or a
+// mention of the bot user. The matched text is returned as it appears in
+// content, so it can be trimmed off later.
+func matchPrefix(content, prefix, botID string) (string, bool) {
+	if prefix != "" && len(content) >= len(prefix) && strings.EqualFold(content[:len(prefix)], prefix) {
+		return content[:len(prefix)], true
+	}
+	if botID == "" {
+		return "", false
+	}
+	for _, mention := range []string{"<@" + botID + ">", "<@!" + botID + ">"} {
+		if strings.HasPrefix(content, mention) {
+			return mention, true
+		}
+	}
+	return "", false
+}
+
+// FormatUsage renders a prefix-less usage string with a prefix. Every line of
+// the usage is treated as a separate example.
+func FormatUsage(usage, prefix string) string {
+	lines := strings.Split(usage, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package bot
+
+import "testing"
+
+func TestMemoryPrefixResolver(t *testing.T) {
+	r := NewMemoryPrefixResolver("")
+	if got := r.Prefix("1"); got != DefaultPrefix {
+		t.Errorf("MemoryPrefixResolver.Prefix() = %v, want %v", got, DefaultPrefix)
+	}
+
+	_ = r.SetPrefix("1", "!")
+	if got := r.Prefix("1"); got != "!" {
+		t.Errorf("MemoryPrefixResolver.Prefix() = %v, want %v", got, "!")
+	}
+	if got := r.Prefix("2"); got != DefaultPrefix {
+		t.Errorf("MemoryPrefixResolver.Prefix() = %v, want %v", got, DefaultPrefix)
+	}
+
+	_ = r.SetPrefix("1", "")
+	if got := r.Prefix("1"); got != DefaultPrefix {
+		t.Errorf("MemoryPrefixResolver.Prefix() after reset = %v, want %v", got, DefaultPrefix)
+	}
+}
+
+func TestMatchPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		prefix  string
+		botID   string
+		want    string
+		wantOk  bool
+	}{
+		{"prefix", "m?ping", "m?", "1", "m?", true},
+		{"prefix case insensitive", "M?ping", "m?", "1", "M?", true},
+		{"other prefix", "!ping", "m?", "1", "", false},
+		{"mention", "<@1> ping", "m?", "1", "<@1>", true},
+		{"nick mention", "<@!1> ping", "m?", "1", "<@!1>", true},
+		{"other mention", "<@2> ping", "m?", "1", "", false},
+		{"no bot user", "<@1> ping", "m?", "", "", false},
+		{"short content", "m", "m?", "1", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchPrefix(tt.content, tt.prefix, tt.botID)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("matchPrefix() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFormatUsage(t *testing.T) {
+	want := "!ban [user]\n!ban [user] <days>"
+	if got := FormatUsage("ban [user]\nban [user] <days>", "!"); got != want {
+		t.Errorf("FormatUsage() = %v, want %v", got, want)
+	}
+}
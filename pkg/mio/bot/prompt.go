@@ -0,0 +1,175 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	mutils "github.com/intrntsrfr/meido/pkg/mio/utils"
+)
+
+// DefaultPromptTimeout is how long prompts wait if they do not set a timeout.
+const DefaultPromptTimeout = time.Second * 30
+
+var (
+	ErrPromptTimedOut      = errors.New("prompt timed out")
+	ErrPromptOutOfAttempts = errors.New("prompt ran out of attempts")
+)
+
+// PromptOptions decides which events a prompt waits for, and for how long.
+type PromptOptions struct {
+	// ChannelID, UserID and MessageID limit the events a prompt considers. MessageID
+	// is the message reactions are added to, or components are attached to. Empty
+	// fields match everything.
+	ChannelID string
+	UserID    string
+	MessageID string
+	// Timeout defaults to DefaultPromptTimeout.
+	Timeout time.Duration
+	// MaxAttempts is how many considered events can be rejected before the prompt
+	// gives up. 0 means no limit.
+	MaxAttempts int
+}
+
+func (o PromptOptions) matches(channelID, userID, messageID string) bool {
+	return (o.ChannelID == "" || o.ChannelID == channelID) &&
+		(o.UserID == "" || o.UserID == userID) &&
+		(o.MessageID == "" || o.MessageID == messageID)
+}
+
+// Prompts lets handlers wait for messages, reactions and component clicks, such as
+// the answer to a question. Waiting stops when an event is accepted, the timeout
+// or attempts run out, or the context is done.
+type Prompts struct {
+	messages   *mutils.Waiters[*discord.DiscordMessage]
+	reactions  *mutils.Waiters[*discordgo.MessageReaction]
+	components *mutils.Waiters[*discord.DiscordMessageComponent]
+}
+
+func NewPrompts() *Prompts {
+	return &Prompts{
+		messages:   mutils.NewWaiters[*discord.DiscordMessage](),
+		reactions:  mutils.NewWaiters[*discordgo.MessageReaction](),
+		components: mutils.NewWaiters[*discord.DiscordMessageComponent](),
+	}
+}
+
+// AwaitMessage waits for a new message the options match and accept returns true for.
+// A nil accept accepts every message. MessageID is ignored.
+func (p *Prompts) AwaitMessage(ctx context.Context, opts PromptOptions, accept func(*discord.DiscordMessage) bool) (*discord.DiscordMessage, error) {
+	opts.MessageID = ""
+	return await(ctx, p.messages, opts, func(msg *discord.DiscordMessage) bool {
+		return msg.Type() == discord.MessageTypeCreate && msg.Message != nil &&
+			opts.matches(msg.ChannelID(), msg.AuthorID(), "")
+	}, accept, nil)
+}
+
+// AwaitReaction waits for an added reaction the options match and accept returns true for.
+func (p *Prompts) AwaitReaction(ctx context.Context, opts PromptOptions, accept func(*discordgo.MessageReaction) bool) (*discordgo.MessageReaction, error) {
+	return await(ctx, p.reactions, opts, func(r *discordgo.MessageReaction) bool {
+		return opts.matches(r.ChannelID, r.UserID, r.MessageID)
+	}, accept, nil)
+}
+
+// AwaitComponent waits for a component click the options match and accept returns
+// true for. Clicks the prompt considers are not passed on to modules, so the caller
+// must respond to the one it gets. The clicks it turns down are acknowledged
+// without a message, so they do not show as failed.
+func (p *Prompts) AwaitComponent(ctx context.Context, opts PromptOptions, accept func(*discord.DiscordMessageComponent) bool) (*discord.DiscordMessageComponent, error) {
+	return await(ctx, p.components, opts, func(c *discord.DiscordMessageComponent) bool {
+		messageID := ""
+		if c.Interaction.Message != nil {
+			messageID = c.Interaction.Message.ID
+		}
+		return opts.matches(c.ChannelID(), c.AuthorID(), messageID)
+	}, accept, acknowledgeComponent)
+}
+
+// Prompt replies text to msg, and waits for an answer from its author in the same
+// channel. The question is deleted once the prompt is done. Options other than
+// ChannelID and UserID are kept.
+func (p *Prompts) Prompt(msg *discord.DiscordMessage, text string, opts PromptOptions, accept func(*discord.DiscordMessage) bool) (*discord.DiscordMessage, error) {
+	opts.ChannelID = msg.ChannelID()
+	opts.UserID = msg.AuthorID()
+	question, err := msg.Reply(text)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = msg.Sess.ChannelMessageDelete(question.ChannelID, question.ID) }()
+	return p.AwaitMessage(msg.Context(), opts, accept)
+}
+
+// acknowledgeComponent responds to a click a prompt turned down, leaving the
+// message as it is.
+func acknowledgeComponent(c *discord.DiscordMessageComponent) {
+	_ = c.RespondComplex(nil, discordgo.InteractionResponseDeferredMessageUpdate)
+}
+
+// await waits for an event that match and accept return true for. reject, if set,
+// is called with every matched event that is not returned, including the ones
+// that are still queued once waiting stops.
+func await[T any](ctx context.Context, w *mutils.Waiters[T], opts PromptOptions, match, accept func(T) bool, reject func(T)) (T, error) {
+	var zero T
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPromptTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	ch, cancel := w.Wait(match)
+	defer func() {
+		cancel()
+		if reject == nil {
+			return
+		}
+		for {
+			select {
+			case v := <-ch:
+				reject(v)
+			default:
+				return
+			}
+		}
+	}()
+	attempts := 0
+	for {
+		select {
+		case v := <-ch:
+			if accept == nil || accept(v) {
+				return v, nil
+			}
+			if reject != nil {
+				reject(v)
+			}
+			attempts++
+			if opts.MaxAttempts > 0 && attempts >= opts.MaxAttempts {
+				return zero, ErrPromptOutOfAttempts
+			}
+		case <-timer.C:
+			return zero, ErrPromptTimedOut
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// deliverComponent hands a component click to waiting prompts. It returns whether
+// any prompt considered it.
+func (p *Prompts) deliverComponent(it *discord.DiscordInteraction) bool {
+	if it.Interaction.Type != discordgo.InteractionMessageComponent {
+		return false
+	}
+	return p.components.Deliver(&discord.DiscordMessageComponent{
+		DiscordInteraction: it,
+		Data:               it.Interaction.MessageComponentData(),
+	})
+}
+
+func reactionAddHandler(p *Prompts) func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	return func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+		p.reactions.Deliver(r.MessageReaction)
+	}
+}
@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPromptMessage(bot *Bot, channelID, content string) *discord.DiscordMessage {
+	msg := NewTestMessage(bot, "1")
+	msg.Message.ChannelID = channelID
+	msg.Message.Content = content
+	return msg
+}
+
+func TestPrompts_AwaitMessage(t *testing.T) {
+	t.Run("accepts matching message", func(t *testing.T) {
+		bot := NewTestBot()
+		go func() {
+			time.Sleep(time.Millisecond * 10)
+			bot.EventHandler.DeliverCallbacks(newTestPromptMessage(bot, "2", "yes"))
+			bot.EventHandler.DeliverCallbacks(newTestPromptMessage(bot, "1", "no"))
+			bot.EventHandler.DeliverCallbacks(newTestPromptMessage(bot, "1", "yes"))
+		}()
+		got, err := bot.Prompts.AwaitMessage(context.Background(), PromptOptions{ChannelID: "1", Timeout: time.Second},
+			func(msg *discord.DiscordMessage) bool { return msg.RawContent() == "yes" })
+		assert.NoError(t, err)
+		assert.Equal(t, "1", got.ChannelID())
+		assert.Equal(t, "yes", got.RawContent())
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		bot := NewTestBot()
+		_, err := bot.Prompts.AwaitMessage(context.Background(), PromptOptions{Timeout: time.Millisecond * 10}, nil)
+		assert.ErrorIs(t, err, ErrPromptTimedOut)
+		assert.Equal(t, 0, bot.Prompts.messages.Len())
+	})
+
+	t.Run("runs out of attempts", func(t *testing.T) {
+		bot := NewTestBot()
+		go func() {
+			time.Sleep(time.Millisecond * 10)
+			for i := 0; i < 2; i++ {
+				bot.EventHandler.DeliverCallbacks(newTestPromptMessage(bot, "1", "no"))
+			}
+		}()
+		_, err := bot.Prompts.AwaitMessage(context.Background(), PromptOptions{Timeout: time.Second, MaxAttempts: 2},
+			func(msg *discord.DiscordMessage) bool { return false })
+		assert.ErrorIs(t, err, ErrPromptOutOfAttempts)
+	})
+
+	t.Run("stops with context", func(t *testing.T) {
+		bot := NewTestBot()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := bot.Prompts.AwaitMessage(ctx, PromptOptions{Timeout: time.Second}, nil)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
+func TestPrompts_AwaitComponent(t *testing.T) {
+	bot := NewTestBot()
+	mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+	called := make(chan bool, 1)
+	comp := NewTestMessageComponent(mod)
+	comp.Execute = func(*discord.DiscordMessageComponent) {
+		called <- true
+	}
+	_ = mod.RegisterMessageComponents(comp)
+	mod.SetMessageComponentCallback("button", "test")
+	bot.RegisterModule(mod)
+
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		bot.EventHandler.HandleInteraction(NewTestMessageComponentInteraction(bot, "1", "button"))
+	}()
+	got, err := bot.Prompts.AwaitComponent(context.Background(), PromptOptions{Timeout: time.Second}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "button", got.Data.CustomID)
+
+	select {
+	case <-called:
+		t.Errorf("Component handler was not expected to be called")
+	case <-time.After(time.Millisecond * 50):
+	}
+}
+
+func TestPrompts_AwaitComponent_AcknowledgesRejected(t *testing.T) {
+	bot, _, sess := newRecordingTestBot()
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		bot.EventHandler.HandleInteraction(NewTestMessageComponentInteraction(bot, "1", "no"))
+		bot.EventHandler.HandleInteraction(NewTestMessageComponentInteraction(bot, "1", "yes"))
+	}()
+	got, err := bot.Prompts.AwaitComponent(context.Background(), PromptOptions{Timeout: time.Second}, func(c *discord.DiscordMessageComponent) bool {
+		return c.Data.CustomID == "yes"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "yes", got.Data.CustomID)
+
+	resp := awaitResponse(t, sess)
+	assert.Equal(t, discordgo.InteractionResponseDeferredMessageUpdate, resp.Type)
+}
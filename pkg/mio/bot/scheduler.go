@@ -0,0 +1,412 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio"
+)
+
+var (
+	ErrJobExists     = errors.New("a job with that name already exists")
+	ErrJobNotFound   = errors.New("job not found")
+	ErrNoJobHandler  = errors.New("no handler for that kind of job")
+	ErrInvalidJob    = errors.New("job needs a name, a valid schedule and a function to run, and can not have negative durations")
+	ErrJobClaimLost  = errors.New("job is no longer claimed by this scheduler")
+	ErrSchedulerDone = errors.New("scheduler is stopped")
+)
+
+const (
+	// DefaultJobPollInterval is how often the scheduler looks for due one-shot jobs.
+	DefaultJobPollInterval = time.Second * 15
+	// DefaultJobLease is how long a one-shot job is claimed for when it runs. It is
+	// also the timeout of the job, so the claim never runs out while it is running.
+	DefaultJobLease = time.Minute * 5
+	// DefaultJobMaxAttempts is how many times a one-shot job is tried before it is
+	// marked as failed.
+	DefaultJobMaxAttempts = 3
+	// jobClaimLimit is the most one-shot jobs claimed at once.
+	jobClaimLimit = 50
+)
+
+// JobFunc is the work of a recurring job.
+type JobFunc func(ctx context.Context) error
+
+// OneShotFunc runs a one-shot job of a kind. See Scheduler.Handle.
+type OneShotFunc func(ctx context.Context, job *ScheduledJob) error
+
+// Job is a job that runs on a Schedule, for as long as the bot runs. A job never
+// runs concurrently with itself; if a run takes longer than the time to the next
+// one, the next one is skipped. Each run is claimed in the JobStore first, so with
+// several schedulers on the same store, a run happens only once, unless the job is
+// Local.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Jitter delays every run by a random duration up to Jitter, so jobs in
+	// different processes, or with the same schedule, do not all run at once.
+	Jitter time.Duration
+	// Timeout is how long a run may take. There is no timeout if it is 0.
+	Timeout time.Duration
+	// Local jobs run in every process and are not claimed, for work that is about
+	// the process itself, like its presence.
+	Local bool
+	Run   JobFunc
+}
+
+// JobStatus is a snapshot of the state of a recurring job.
+type JobStatus struct {
+	Name         string
+	Schedule     string
+	Running      bool
+	NextRun      time.Time
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    string
+	Runs         int
+	Failures     int
+}
+
+type recurringJob struct {
+	*Job
+	status JobStatus
+}
+
+// Scheduler runs recurring jobs, and one-shot jobs kept in a JobStore, so they
+// are run even if the bot restarts before they are due.
+type Scheduler struct {
+	mu       sync.Mutex
+	store    JobStore
+	logger   mio.Logger
+	jobs     map[string]*recurringJob
+	handlers map[string]OneShotFunc
+	claimer  string
+	ctx      context.Context
+	cancel   context.CancelFunc
+	running  sync.WaitGroup
+	wake     chan struct{}
+	stopped  bool
+
+	// PollInterval, Lease and MaxAttempts default to DefaultJobPollInterval,
+	// DefaultJobLease and DefaultJobMaxAttempts. They must be set before Start.
+	PollInterval time.Duration
+	Lease        time.Duration
+	MaxAttempts  int
+}
+
+func NewScheduler(store JobStore, logger mio.Logger) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		store:        store,
+		logger:       logger.Named("scheduler"),
+		jobs:         make(map[string]*recurringJob),
+		handlers:     make(map[string]OneShotFunc),
+		claimer:      fmt.Sprintf("%v-%v", host, os.Getpid()),
+		wake:         make(chan struct{}, 1),
+		PollInterval: DefaultJobPollInterval,
+		Lease:        DefaultJobLease,
+		MaxAttempts:  DefaultJobMaxAttempts,
+	}
+}
+
+// Add adds a recurring job. Jobs added before Start run once the scheduler starts.
+func (s *Scheduler) Add(job *Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil || job.Jitter < 0 || job.Timeout < 0 {
+		return ErrInvalidJob
+	}
+	if every, ok := job.Schedule.(*everySchedule); ok && every.d <= 0 {
+		return ErrInvalidJob
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return ErrSchedulerDone
+	}
+	if _, ok := s.jobs[job.Name]; ok {
+		return ErrJobExists
+	}
+	rj := &recurringJob{Job: job, status: JobStatus{Name: job.Name, Schedule: job.Schedule.String()}}
+	s.jobs[job.Name] = rj
+	if s.ctx != nil {
+		s.running.Add(1)
+		go s.runRecurring(s.ctx, rj)
+	}
+	return nil
+}
+
+// Every adds a job that runs fn every d. d must be positive.
+func (s *Scheduler) Every(name string, d time.Duration, fn JobFunc) error {
+	return s.Add(&Job{Name: name, Schedule: Every(d), Run: fn})
+}
+
+// Cron adds a job that runs fn on a cron schedule. See ParseCron.
+func (s *Scheduler) Cron(name, expr string, fn JobFunc) error {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return err
+	}
+	return s.Add(&Job{Name: name, Schedule: schedule, Run: fn})
+}
+
+// Handle sets the function that runs one-shot jobs of kind. Handlers should be
+// set before the scheduler starts, as jobs with no handler are marked as failed.
+func (s *Scheduler) Handle(kind string, fn OneShotFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[kind] = fn
+}
+
+// ScheduleAt saves a one-shot job of kind that runs at, with payload encoded as
+// JSON. Jobs that are due are run within PollInterval.
+func (s *Scheduler) ScheduleAt(kind string, at time.Time, payload any) (*ScheduledJob, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	job := &ScheduledJob{Kind: kind, Payload: data, RunAt: at}
+	if err := s.store.AddJob(job); err != nil {
+		return nil, err
+	}
+	if !at.After(time.Now().Add(s.PollInterval)) {
+		s.poke()
+	}
+	return job, nil
+}
+
+// ScheduleIn is like ScheduleAt, but runs the job after d.
+func (s *Scheduler) ScheduleIn(kind string, d time.Duration, payload any) (*ScheduledJob, error) {
+	return s.ScheduleAt(kind, time.Now().Add(d), payload)
+}
+
+// Cancel removes a one-shot job that has not run yet.
+func (s *Scheduler) Cancel(id int64) error {
+	return s.store.CancelJob(id)
+}
+
+// Jobs returns the status of the recurring jobs, sorted by name.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, j.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// Pending returns the one-shot jobs that have not completed, including failed ones.
+func (s *Scheduler) Pending() ([]*ScheduledJob, error) {
+	return s.store.Jobs()
+}
+
+// Start starts running jobs until ctx is done or Stop is called.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil || s.stopped {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for _, j := range s.jobs {
+		s.running.Add(1)
+		go s.runRecurring(s.ctx, j)
+	}
+	s.running.Add(1)
+	go s.poll(s.ctx)
+}
+
+// Stop stops the scheduler, cancelling the context of running jobs, and waits
+// for them to return. Unfinished one-shot jobs are run again once their claim
+// runs out.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	s.running.Wait()
+}
+
+func (s *Scheduler) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) runRecurring(ctx context.Context, j *recurringJob) {
+	defer s.running.Done()
+	due := time.Now()
+	// every schedules are counted from the zero time, so schedulers in different
+	// processes agree on when runs are due, and claim the same ones
+	if every, ok := j.Schedule.(*everySchedule); ok {
+		due = due.Truncate(every.d)
+	}
+	for {
+		due = j.Schedule.Next(due)
+		// runs that were due while the last one ran are skipped
+		for now := time.Now(); !due.IsZero() && due.Before(now); {
+			due = j.Schedule.Next(due)
+		}
+		if due.IsZero() {
+			s.logger.Warn("Job will not run again", "job", j.Name)
+			return
+		}
+		at := due
+		if j.Jitter > 0 {
+			at = at.Add(time.Duration(rand.Int63n(int64(j.Jitter))))
+		}
+		s.mu.Lock()
+		j.status.NextRun = at
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(at))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if j.Local {
+			s.runJob(ctx, j)
+			continue
+		}
+		claimed, err := s.store.ClaimRun(j.Name, due, s.claimer)
+		if err != nil {
+			s.logger.Error("Claiming job run failed", "job", j.Name, "due", due, "error", err)
+			continue
+		}
+		if claimed {
+			s.runJob(ctx, j)
+		}
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, j *recurringJob) {
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	s.mu.Lock()
+	j.status.Running = true
+	j.status.LastRun = time.Now()
+	s.mu.Unlock()
+
+	err := s.call(ctx, func(ctx context.Context) error { return j.Run(ctx) })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.status.Running = false
+	j.status.LastDuration = time.Since(j.status.LastRun)
+	j.status.Runs++
+	j.status.LastError = ""
+	if err != nil {
+		j.status.Failures++
+		j.status.LastError = err.Error()
+		s.logger.Error("Job failed", "job", j.Name, "error", err)
+	}
+}
+
+// call runs fn, and turns a panic into an error.
+func (s *Scheduler) call(ctx context.Context, fn JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+func (s *Scheduler) poll(ctx context.Context) {
+	defer s.running.Done()
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.claimDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// claimDue claims the one-shot jobs that are due, and runs them.
+func (s *Scheduler) claimDue(ctx context.Context) {
+	now := time.Now()
+	jobs, err := s.store.ClaimJobs(now, now.Add(s.Lease), s.claimer, jobClaimLimit)
+	if err != nil {
+		s.logger.Error("Claiming jobs failed", "error", err)
+		return
+	}
+	for _, job := range jobs {
+		s.running.Add(1)
+		go s.runOneShot(ctx, job)
+	}
+	if len(jobs) == jobClaimLimit {
+		s.poke()
+	}
+}
+
+func (s *Scheduler) runOneShot(ctx context.Context, job *ScheduledJob) {
+	defer s.running.Done()
+	s.mu.Lock()
+	fn, ok := s.handlers[job.Kind]
+	s.mu.Unlock()
+	if !ok {
+		s.logger.Error("No handler for job", "id", job.ID, "kind", job.Kind)
+		if err := s.store.FailJob(job.ID, s.claimer, ErrNoJobHandler.Error()); err != nil {
+			s.logStoreError("Could not mark job as failed", job, err)
+		}
+		return
+	}
+
+	// the job must finish before its claim runs out, or it could run twice
+	ctx, cancel := context.WithDeadline(ctx, job.ClaimedUntil)
+	defer cancel()
+	err := s.call(ctx, func(ctx context.Context) error { return fn(ctx, job) })
+	if err == nil {
+		if err := s.store.CompleteJob(job.ID, s.claimer); err != nil {
+			s.logStoreError("Could not complete job", job, err)
+		}
+		return
+	}
+
+	if job.Attempts+1 >= s.MaxAttempts {
+		s.logger.Error("Job failed for good", "id", job.ID, "kind", job.Kind, "attempts", job.Attempts+1, "error", err)
+		if err := s.store.FailJob(job.ID, s.claimer, err.Error()); err != nil {
+			s.logStoreError("Could not mark job as failed", job, err)
+		}
+		return
+	}
+	// back off a minute, then 2, 4 and so on
+	retryAt := time.Now().Add(time.Minute << job.Attempts)
+	s.logger.Warn("Job failed, retrying", "id", job.ID, "kind", job.Kind, "retry at", retryAt, "error", err)
+	if err := s.store.RetryJob(job.ID, s.claimer, retryAt, err.Error()); err != nil {
+		s.logStoreError("Could not reschedule job", job, err)
+	}
+}
+
+// logStoreError logs an error of the store about a one-shot job. Losing the claim
+// only means another scheduler, or a cancel, got to the job first.
+func (s *Scheduler) logStoreError(msg string, job *ScheduledJob, err error) {
+	if errors.Is(err, ErrJobClaimLost) {
+		s.logger.Warn(msg+", the claim was lost", "id", job.ID, "kind", job.Kind)
+		return
+	}
+	s.logger.Error(msg, "id", job.ID, "kind", job.Kind, "error", err)
+}
@@ -0,0 +1,296 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScheduler(t *testing.T, store JobStore) *Scheduler {
+	s := NewScheduler(store, mio.NewDiscardLogger())
+	s.PollInterval = time.Millisecond * 10
+	s.Start(context.Background())
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func TestScheduler_Recurring(t *testing.T) {
+	s := newTestScheduler(t, NewMemoryJobStore())
+	var runs atomic.Int32
+	require.NoError(t, s.Every("count", time.Millisecond*10, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}))
+	require.NoError(t, s.Add(&Job{Name: "fail", Schedule: Every(time.Millisecond * 10), Jitter: time.Millisecond, Run: func(ctx context.Context) error {
+		return errors.New("oh no")
+	}}))
+	assert.ErrorIs(t, s.Every("count", time.Second, func(ctx context.Context) error { return nil }), ErrJobExists)
+	assert.ErrorIs(t, s.Add(&Job{Name: "nothing"}), ErrInvalidJob)
+	assert.ErrorIs(t, s.Every("never", 0, func(ctx context.Context) error { return nil }), ErrInvalidJob)
+	assert.ErrorIs(t, s.Every("backwards", -time.Second, func(ctx context.Context) error { return nil }), ErrInvalidJob)
+	assert.ErrorIs(t, s.Add(&Job{Name: "jitter", Schedule: Every(time.Second), Jitter: -time.Second, Run: func(ctx context.Context) error { return nil }}), ErrInvalidJob)
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond*5)
+	assert.Eventually(t, func() bool { return s.Jobs()[1].Runs >= 1 }, time.Second, time.Millisecond*5)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "count", jobs[0].Name)
+	assert.Equal(t, "every 10ms", jobs[0].Schedule)
+	assert.Empty(t, jobs[0].LastError)
+	assert.False(t, jobs[0].NextRun.IsZero())
+	assert.Equal(t, "oh no", jobs[1].LastError)
+	assert.Equal(t, jobs[1].Runs, jobs[1].Failures)
+}
+
+func TestScheduler_RecurringOncePerStore(t *testing.T) {
+	store := NewMemoryJobStore()
+	var mu sync.Mutex
+	runs := make(map[time.Time]int)
+	run := func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs[time.Now().Truncate(time.Millisecond*50)]++
+		return nil
+	}
+	// two schedulers on the same store, like two processes of the bot
+	for _, claimer := range []string{"one", "two"} {
+		s := NewScheduler(store, mio.NewDiscardLogger())
+		s.claimer = claimer
+		require.NoError(t, s.Every("shared", time.Millisecond*50, run))
+		s.Start(context.Background())
+		t.Cleanup(s.Stop)
+	}
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(runs) >= 4
+	}, time.Second*2, time.Millisecond*5)
+	mu.Lock()
+	defer mu.Unlock()
+	for due, n := range runs {
+		assert.Equal(t, 1, n, "the run due at %v should happen once", due)
+	}
+}
+
+func TestScheduler_LocalRunsInEveryProcess(t *testing.T) {
+	store := NewMemoryJobStore()
+	var runs [2]atomic.Int32
+	for i, claimer := range []string{"one", "two"} {
+		s := NewScheduler(store, mio.NewDiscardLogger())
+		s.claimer = claimer
+		require.NoError(t, s.Add(&Job{
+			Name:     "local",
+			Schedule: Every(time.Millisecond * 20),
+			Local:    true,
+			Run: func(ctx context.Context) error {
+				runs[i].Add(1)
+				return nil
+			},
+		}))
+		s.Start(context.Background())
+		t.Cleanup(s.Stop)
+	}
+
+	assert.Eventually(t, func() bool {
+		return runs[0].Load() >= 2 && runs[1].Load() >= 2
+	}, time.Second*2, time.Millisecond*5)
+}
+
+func TestScheduler_NoOverlap(t *testing.T) {
+	s := newTestScheduler(t, NewMemoryJobStore())
+	var running, overlaps atomic.Int32
+	require.NoError(t, s.Every("slow", time.Millisecond, func(ctx context.Context) error {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		time.Sleep(time.Millisecond * 5)
+		running.Add(-1)
+		return nil
+	}))
+	assert.Eventually(t, func() bool { return s.Jobs()[0].Runs >= 5 }, time.Second, time.Millisecond*5)
+	assert.Zero(t, overlaps.Load())
+}
+
+func TestScheduler_Panic(t *testing.T) {
+	s := newTestScheduler(t, NewMemoryJobStore())
+	require.NoError(t, s.Every("panic", time.Millisecond*5, func(ctx context.Context) error {
+		panic("oh no")
+	}))
+	assert.Eventually(t, func() bool { return s.Jobs()[0].Failures >= 2 }, time.Second, time.Millisecond*5)
+	assert.Equal(t, "job panicked: oh no", s.Jobs()[0].LastError)
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	s := NewScheduler(NewMemoryJobStore(), mio.NewDiscardLogger())
+	cancelled := make(chan struct{})
+	require.NoError(t, s.Every("wait", time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}))
+	s.Start(context.Background())
+	assert.Eventually(t, func() bool { return s.Jobs()[0].Running }, time.Second, time.Millisecond)
+
+	s.Stop()
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("Stop should cancel running jobs, and wait for them")
+	}
+	assert.ErrorIs(t, s.Every("late", time.Second, func(ctx context.Context) error { return nil }), ErrSchedulerDone)
+}
+
+type unbanPayload struct {
+	GuildID string
+	UserID  string
+}
+
+func TestScheduler_OneShot(t *testing.T) {
+	store := NewMemoryJobStore()
+	s := newTestScheduler(t, store)
+	got := make(chan unbanPayload, 1)
+	s.Handle("unban", func(ctx context.Context, job *ScheduledJob) error {
+		var p unbanPayload
+		if err := job.Decode(&p); err != nil {
+			return err
+		}
+		got <- p
+		return nil
+	})
+
+	later, err := s.ScheduleIn("unban", time.Hour, unbanPayload{"1", "3"})
+	require.NoError(t, err)
+	_, err = s.ScheduleIn("unban", 0, unbanPayload{"1", "2"})
+	require.NoError(t, err)
+
+	select {
+	case p := <-got:
+		assert.Equal(t, unbanPayload{"1", "2"}, p)
+	case <-time.After(time.Second):
+		t.Fatal("Expected the job to run")
+	}
+	assert.Eventually(t, func() bool {
+		jobs, _ := s.Pending()
+		return len(jobs) == 1
+	}, time.Second, time.Millisecond*5, "Jobs should be removed once they ran")
+
+	jobs, err := s.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, later.ID, jobs[0].ID)
+	require.NoError(t, s.Cancel(later.ID))
+	assert.ErrorIs(t, s.Cancel(later.ID), ErrJobNotFound)
+}
+
+func TestScheduler_OneShotRetry(t *testing.T) {
+	store := NewMemoryJobStore()
+	s := newTestScheduler(t, store)
+	s.MaxAttempts = 2
+	var attempts atomic.Int32
+	s.Handle("fail", func(ctx context.Context, job *ScheduledJob) error {
+		attempts.Add(1)
+		return errors.New("oh no")
+	})
+	job, err := s.ScheduleIn("fail", 0, nil)
+	require.NoError(t, err)
+
+	// the first retry is a minute out, so move it up
+	assert.Eventually(t, func() bool {
+		jobs, _ := store.Jobs()
+		return len(jobs) == 1 && jobs[0].Attempts == 1
+	}, time.Second, time.Millisecond*5)
+	store.Lock()
+	store.jobs[job.ID].RunAt = time.Now()
+	store.Unlock()
+	s.poke()
+
+	assert.Eventually(t, func() bool {
+		jobs, _ := store.Jobs()
+		return jobs[0].Failed
+	}, time.Second, time.Millisecond*5)
+	jobs, _ := store.Jobs()
+	assert.Equal(t, 2, jobs[0].Attempts)
+	assert.Equal(t, "oh no", jobs[0].LastError)
+	assert.Equal(t, int32(2), attempts.Load())
+
+	_, err = s.ScheduleIn("unknown", 0, nil)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		jobs, _ := store.Jobs()
+		return len(jobs) == 2 && jobs[1].Failed && jobs[1].LastError == ErrNoJobHandler.Error()
+	}, time.Second, time.Millisecond*5)
+}
+
+func TestMemoryJobStore_Claim(t *testing.T) {
+	store := NewMemoryJobStore()
+	now := time.Now()
+	require.NoError(t, store.AddJob(&ScheduledJob{Kind: "a", RunAt: now.Add(-time.Minute)}))
+	require.NoError(t, store.AddJob(&ScheduledJob{Kind: "b", RunAt: now.Add(-time.Hour)}))
+	require.NoError(t, store.AddJob(&ScheduledJob{Kind: "c", RunAt: now.Add(time.Hour)}))
+
+	claimed, err := store.ClaimJobs(now, now.Add(time.Minute), "one", 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, "b", claimed[0].Kind, "Jobs should be claimed in the order they are due")
+	assert.Equal(t, "one", claimed[0].ClaimedBy)
+
+	claimed, err = store.ClaimJobs(now, now.Add(time.Minute), "two", 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "Claimed jobs should not be claimed again")
+
+	// claims run out, so jobs of schedulers that died are run again
+	claimed, err = store.ClaimJobs(now.Add(time.Minute*2), now.Add(time.Minute*3), "two", 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, "two", claimed[0].ClaimedBy)
+}
+
+func TestMemoryJobStore_Fencing(t *testing.T) {
+	store := NewMemoryJobStore()
+	now := time.Now()
+	job := &ScheduledJob{Kind: "a", RunAt: now}
+	require.NoError(t, store.AddJob(job))
+	_, err := store.ClaimJobs(now, now.Add(time.Minute), "one", 1)
+	require.NoError(t, err)
+
+	// the claim of one runs out while its job is still running, and two takes over
+	claimed, err := store.ClaimJobs(now.Add(time.Minute*2), now.Add(time.Minute*3), "two", 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	assert.ErrorIs(t, store.CompleteJob(job.ID, "one"), ErrJobClaimLost)
+	assert.ErrorIs(t, store.RetryJob(job.ID, "one", now, "oh no"), ErrJobClaimLost)
+	assert.ErrorIs(t, store.FailJob(job.ID, "one", "oh no"), ErrJobClaimLost)
+	jobs, _ := store.Jobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "two", jobs[0].ClaimedBy)
+	assert.Zero(t, jobs[0].Attempts)
+
+	require.NoError(t, store.CompleteJob(job.ID, "two"))
+	assert.ErrorIs(t, store.CompleteJob(job.ID, "two"), ErrJobClaimLost)
+}
+
+func TestMemoryJobStore_ClaimRun(t *testing.T) {
+	store := NewMemoryJobStore()
+	due := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)
+
+	claimed, err := store.ClaimRun("job", due, "one")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, _ = store.ClaimRun("job", due, "two")
+	assert.False(t, claimed, "a run should only be claimed once")
+	claimed, _ = store.ClaimRun("job", due.Add(-time.Hour), "two")
+	assert.False(t, claimed, "runs before the last claimed one should not be claimed")
+	claimed, _ = store.ClaimRun("other", due, "two")
+	assert.True(t, claimed)
+	claimed, _ = store.ClaimRun("job", due.Add(time.Hour), "two")
+	assert.True(t, claimed)
+}
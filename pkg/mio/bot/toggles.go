@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ToggleKind is the kind of thing a Toggle disables.
+type ToggleKind string
+
+const (
+	ToggleModule  ToggleKind = "module"
+	ToggleCommand ToggleKind = "command"
+	TogglePassive ToggleKind = "passive"
+)
+
+var ErrInvalidToggleKind = errors.New("invalid toggle kind")
+
+// ParseToggleKind returns the ToggleKind named by s.
+func ParseToggleKind(s string) (ToggleKind, error) {
+	switch k := ToggleKind(strings.ToLower(s)); k {
+	case ToggleModule, ToggleCommand, TogglePassive:
+		return k, nil
+	}
+	return "", ErrInvalidToggleKind
+}
+
+// Toggle is a module, command or passive that is disabled in a guild.
+// Command toggles also apply to application commands with the same name.
+type Toggle struct {
+	Kind ToggleKind
+	Name string
+}
+
+// ToggleStore keeps track of which modules, commands and passives are disabled
+// in each guild. Names are case-insensitive. Nothing can be disabled in DMs.
+type ToggleStore interface {
+	// IsDisabled returns whether name is disabled in a guild.
+	IsDisabled(guildID string, kind ToggleKind, name string) bool
+	// SetDisabled disables or re-enables name in a guild.
+	SetDisabled(guildID string, kind ToggleKind, name string, disabled bool) error
+	// Disabled returns everything that is disabled in a guild, sorted by kind and name.
+	Disabled(guildID string) ([]Toggle, error)
+}
+
+// MemoryToggleStore is a ToggleStore that keeps toggles in memory.
+type MemoryToggleStore struct {
+	sync.RWMutex
+	toggles map[string]map[Toggle]struct{}
+}
+
+func NewMemoryToggleStore() *MemoryToggleStore {
+	return &MemoryToggleStore{
+		toggles: make(map[string]map[Toggle]struct{}),
+	}
+}
+
+func (s *MemoryToggleStore) IsDisabled(guildID string, kind ToggleKind, name string) bool {
+	if guildID == "" {
+		return false
+	}
+	s.RLock()
+	defer s.RUnlock()
+	_, ok := s.toggles[guildID][Toggle{kind, strings.ToLower(name)}]
+	return ok
+}
+
+func (s *MemoryToggleStore) SetDisabled(guildID string, kind ToggleKind, name string, disabled bool) error {
+	s.Lock()
+	defer s.Unlock()
+	t := Toggle{kind, strings.ToLower(name)}
+	if !disabled {
+		delete(s.toggles[guildID], t)
+		return nil
+	}
+	if _, ok := s.toggles[guildID]; !ok {
+		s.toggles[guildID] = make(map[Toggle]struct{})
+	}
+	s.toggles[guildID][t] = struct{}{}
+	return nil
+}
+
+func (s *MemoryToggleStore) Disabled(guildID string) ([]Toggle, error) {
+	s.RLock()
+	defer s.RUnlock()
+	toggles := make([]Toggle, 0, len(s.toggles[guildID]))
+	for t := range s.toggles[guildID] {
+		toggles = append(toggles, t)
+	}
+	SortToggles(toggles)
+	return toggles, nil
+}
+
+// SortToggles sorts toggles by kind and then name.
+func SortToggles(toggles []Toggle) {
+	sort.Slice(toggles, func(i, j int) bool {
+		if toggles[i].Kind != toggles[j].Kind {
+			return toggles[i].Kind < toggles[j].Kind
+		}
+		return toggles[i].Name < toggles[j].Name
+	})
+}
+
+// IsDisabled returns whether name is disabled in a guild. It is always false in DMs.
+func (b *Bot) IsDisabled(guildID string, kind ToggleKind, name string) bool {
+	if guildID == "" || b.Toggles == nil {
+		return false
+	}
+	return b.Toggles.IsDisabled(guildID, kind, name)
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryToggleStore(t *testing.T) {
+	s := NewMemoryToggleStore()
+	assert.False(t, s.IsDisabled("1", ToggleCommand, "ping"))
+
+	_ = s.SetDisabled("1", ToggleCommand, "Ping", true)
+	_ = s.SetDisabled("1", ToggleModule, "fun", true)
+	assert.True(t, s.IsDisabled("1", ToggleCommand, "ping"))
+	assert.False(t, s.IsDisabled("1", TogglePassive, "ping"))
+	assert.False(t, s.IsDisabled("2", ToggleCommand, "ping"))
+	assert.False(t, s.IsDisabled("", ToggleCommand, "ping"))
+
+	toggles, err := s.Disabled("1")
+	assert.NoError(t, err)
+	assert.Equal(t, []Toggle{{ToggleCommand, "ping"}, {ToggleModule, "fun"}}, toggles)
+
+	_ = s.SetDisabled("1", ToggleCommand, "ping", false)
+	assert.False(t, s.IsDisabled("1", ToggleCommand, "ping"))
+}
+
+func TestParseToggleKind(t *testing.T) {
+	k, err := ParseToggleKind("Module")
+	assert.NoError(t, err)
+	assert.Equal(t, ToggleModule, k)
+	_, err = ParseToggleKind("role")
+	assert.ErrorIs(t, err, ErrInvalidToggleKind)
+}
+
+func TestEventHandler_Toggles(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    ToggleKind
+		toggle  string
+		guildID string
+		want    bool
+	}{
+		{"nothing disabled", "", "", "1", true},
+		{"command disabled", ToggleCommand, "test", "1", false},
+		{"module disabled", ToggleModule, "Testing", "1", false},
+		{"other guild", ToggleCommand, "test", "2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := NewTestBot()
+			mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+			called := make(chan bool, 1)
+			cmd := NewTestCommand(mod)
+			cmd.Execute = func(*discord.DiscordMessage) {
+				called <- true
+			}
+			_ = mod.RegisterCommands(cmd)
+			bot.RegisterModule(mod)
+			if tt.kind != "" {
+				_ = bot.Toggles.SetDisabled("1", tt.kind, tt.toggle, true)
+			}
+
+			bot.EventHandler.HandleMessage(NewTestMessage(bot, tt.guildID))
+			select {
+			case <-called:
+				if !tt.want {
+					t.Errorf("Command was not expected to be called")
+				}
+			case <-time.After(time.Millisecond * 50):
+				if tt.want {
+					t.Errorf("Command was expected to be called")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrTriggerConflict = errors.New("trigger conflict")
+
+// commandTrie maps triggers to commands, one word per level, so multi-word triggers
+// such as "settings prefix" are found in O(words).
+type commandTrie struct {
+	root *trieNode
+}
+
+type trieNode struct {
+	children map[string]*trieNode
+	entry    *trieEntry
+}
+
+type trieEntry struct {
+	mod     Module
+	cmd     *ModuleCommand
+	trigger string
+}
+
+func newCommandTrie() *commandTrie {
+	return &commandTrie{root: &trieNode{children: make(map[string]*trieNode)}}
+}
+
+func triggerWords(trigger string) []string {
+	return strings.Fields(strings.ToLower(trigger))
+}
+
+// conflict returns an error if trigger already belongs to another command.
+func (t *commandTrie) conflict(cmd *ModuleCommand, trigger string) error {
+	node := t.root
+	for _, word := range triggerWords(trigger) {
+		if node = node.children[word]; node == nil {
+			return nil
+		}
+	}
+	if node.entry != nil && node.entry.cmd != cmd {
+		return fmt.Errorf("%w: trigger '%v' of command '%v' is already used by command '%v'",
+			ErrTriggerConflict, trigger, cmd.Name, node.entry.cmd.Name)
+	}
+	return nil
+}
+
+// insert adds every trigger of cmd to the trie. Nothing is added if any of them conflict.
+func (t *commandTrie) insert(mod Module, cmd *ModuleCommand) error {
+	for _, trig := range cmd.Triggers {
+		if err := t.conflict(cmd, trig); err != nil {
+			return err
+		}
+	}
+	for _, trig := range cmd.Triggers {
+		words := triggerWords(trig)
+		if len(words) == 0 {
+			continue
+		}
+		node := t.root
+		for _, word := range words {
+			child, ok := node.children[word]
+			if !ok {
+				child = &trieNode{children: make(map[string]*trieNode)}
+				node.children[word] = child
+			}
+			node = child
+		}
+		node.entry = &trieEntry{mod: mod, cmd: cmd, trigger: trig}
+	}
+	return nil
+}
+
+// match finds the command with the longest trigger that content starts with.
+func (t *commandTrie) match(content string) (*trieEntry, bool) {
+	var found *trieEntry
+	node := t.root
+	for _, word := range triggerWords(content) {
+		if node = node.children[word]; node == nil {
+			break
+		}
+		if node.entry != nil {
+			found = node.entry
+		}
+	}
+	return found, found != nil
+}
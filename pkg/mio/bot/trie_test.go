@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/intrntsrfr/meido/pkg/mio"
+)
+
+func TestCommandTrie_Match(t *testing.T) {
+	trie := newCommandTrie()
+	settings := &ModuleCommand{Name: "settings", Triggers: []string{"settings"}}
+	prefix := &ModuleCommand{Name: "prefixsettings", Triggers: []string{"settings prefix"}}
+	ban := &ModuleCommand{Name: "ban", Triggers: []string{"ban", "b"}}
+	for _, cmd := range []*ModuleCommand{settings, prefix, ban} {
+		if err := trie.insert(nil, cmd); err != nil {
+			t.Fatalf("commandTrie.insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		content string
+		want    *ModuleCommand
+	}{
+		{"settings", settings},
+		{"settings fishing", settings},
+		{"settings prefix !", prefix},
+		{"SETTINGS   Prefix", prefix},
+		{"b @user", ban},
+		{"bans", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			entry, ok := trie.match(tt.content)
+			if tt.want == nil {
+				if ok {
+					t.Errorf("commandTrie.match() = %v, want no match", entry.cmd.Name)
+				}
+				return
+			}
+			if !ok || entry.cmd != tt.want {
+				t.Errorf("commandTrie.match() did not match %v", tt.want.Name)
+			}
+		})
+	}
+}
+
+func TestCommandTrie_Conflict(t *testing.T) {
+	trie := newCommandTrie()
+	_ = trie.insert(nil, &ModuleCommand{Name: "one", Triggers: []string{"settings prefix"}})
+
+	err := trie.insert(nil, &ModuleCommand{Name: "two", Triggers: []string{"other", "Settings  prefix"}})
+	if !errors.Is(err, ErrTriggerConflict) {
+		t.Fatalf("commandTrie.insert() error = %v, want %v", err, ErrTriggerConflict)
+	}
+	if _, ok := trie.match("other"); ok {
+		t.Errorf("commandTrie.insert() added triggers of a conflicting command")
+	}
+}
+
+func TestModuleManager_TriggerConflict(t *testing.T) {
+	mngr := NewModuleManager(mio.NewDiscardLogger())
+	first := NewTestModule(nil, "first", mio.NewDiscardLogger())
+	_ = first.RegisterCommands(&ModuleCommand{Name: "a", Triggers: []string{"settings"}})
+	second := NewTestModule(nil, "second", mio.NewDiscardLogger())
+	_ = second.RegisterCommands(&ModuleCommand{Name: "b", Triggers: []string{"settings"}})
+	third := NewTestModule(nil, "third", mio.NewDiscardLogger())
+	_ = third.RegisterCommands(&ModuleCommand{Name: "c", Triggers: []string{"settings prefix"}})
+
+	mngr.RegisterModule(first)
+	mngr.RegisterModule(second)
+	mngr.RegisterModule(third)
+	if len(mngr.Modules) != 2 {
+		t.Fatalf("len(ModuleManager.Modules) = %v, want 2", len(mngr.Modules))
+	}
+
+	mod, cmd, err := mngr.MatchCommand("settings prefix m?")
+	if err != nil || cmd.Name != "c" || mod.Name() != "third" {
+		t.Errorf("ModuleManager.MatchCommand() did not find the longest match")
+	}
+}
@@ -2,10 +2,13 @@ package bot
 
 import (
 	"errors"
+	"testing"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/intrntsrfr/meido/pkg/mio"
 	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/intrntsrfr/meido/pkg/mio/discord/mocks"
 	"github.com/intrntsrfr/meido/pkg/mio/test"
 )
 
@@ -37,8 +40,8 @@ func (m *testModule) Hook() error {
 func NewTestCommand(mod Module) *ModuleCommand {
 	return NewModuleCommandBuilder(mod, "test").
 		Description("testing").
-		Triggers(".test").
-		Usage(".test").
+		Triggers("test").
+		Usage("test").
 		Cooldown(0, CooldownScopeChannel).
 		AllowedTypes(discord.MessageTypeCreate).
 		Execute(testCommandRun).
@@ -108,8 +111,9 @@ func NewTestMessage(bot *Bot, guildID string) *discord.DiscordMessage {
 		Sess:        bot.Discord.Sess,
 		Discord:     bot.Discord,
 		MessageType: discord.MessageTypeCreate,
+		Prefix:      "m?",
 		Message: &discordgo.Message{
-			Content:   ".test hello",
+			Content:   "m?test hello",
 			GuildID:   guildID,
 			Author:    author,
 			ChannelID: "1",
@@ -132,6 +136,19 @@ func NewTestApplicationCommandInteraction(bot *Bot, guildID string) *discord.Dis
 	return it
 }
 
+func NewTestAutocompleteInteraction(bot *Bot, guildID, option, value string) *discord.DiscordInteraction {
+	it := newTestInteraction(bot, guildID)
+	it.Interaction.Type = discordgo.InteractionApplicationCommandAutocomplete
+	it.Interaction.Data = discordgo.ApplicationCommandInteractionData{
+		Name:        "test",
+		CommandType: discordgo.ChatApplicationCommand,
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: option, Type: discordgo.ApplicationCommandOptionString, Value: value, Focused: true},
+		},
+	}
+	return it
+}
+
 func NewTestMessageComponentInteraction(bot *Bot, guildID, customID string) *discord.DiscordInteraction {
 	it := newTestInteraction(bot, guildID)
 	it.Interaction.Type = discordgo.InteractionMessageComponent
@@ -168,3 +185,48 @@ func newTestInteraction(bot *Bot, guildID string) *discord.DiscordInteraction {
 	}
 	return it
 }
+
+// recordingSession records the interaction responses and edits it is sent.
+type recordingSession struct {
+	*mocks.DiscordSessionMock
+	responses chan *discordgo.InteractionResponse
+	edits     chan *discordgo.WebhookEdit
+}
+
+func (s *recordingSession) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	s.responses <- resp
+	return nil
+}
+
+func (s *recordingSession) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.edits <- newresp
+	return &discordgo.Message{}, nil
+}
+
+func newRecordingTestBot() (*Bot, *testModule, *recordingSession) {
+	conf := test.NewTestConfig()
+	sess := &recordingSession{
+		DiscordSessionMock: mocks.NewDiscordSession(conf.GetString("token"), conf.GetInt("shards")),
+		responses:          make(chan *discordgo.InteractionResponse, 8),
+		edits:              make(chan *discordgo.WebhookEdit, 8),
+	}
+	bot := NewBotBuilder(conf).
+		WithDefaultHandlers().
+		WithDiscord(discord.NewTestDiscord(conf, sess, nil)).
+		WithLogger(mio.NewDiscardLogger()).
+		Build()
+	mod := NewTestModule(bot, "testing", mio.NewDiscardLogger())
+	bot.RegisterModule(mod)
+	return bot, mod, sess
+}
+
+func awaitResponse(t *testing.T, sess *recordingSession) *discordgo.InteractionResponse {
+	t.Helper()
+	select {
+	case resp := <-sess.responses:
+		return resp
+	case <-time.After(time.Second):
+		t.Fatal("Expected an interaction response")
+		return nil
+	}
+}
@@ -0,0 +1,212 @@
+package bot
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what happens to events when the queue of a WorkerPool is full.
+type OverflowPolicy int
+
+const (
+	// OverflowDrop drops events when the queue is full.
+	OverflowDrop OverflowPolicy = iota
+	// OverflowBlock waits for room in the queue, which applies backpressure to the gateway.
+	OverflowBlock
+)
+
+const (
+	DefaultWorkers   = 128
+	DefaultQueueSize = 1024
+)
+
+// WorkerPoolConfig configures the worker pools of a Bot.
+type WorkerPoolConfig struct {
+	// Workers and QueueSize default to DefaultWorkers and DefaultQueueSize.
+	Workers   int
+	QueueSize int
+	Policy    OverflowPolicy
+	// PerShard gives every shard its own pool, so a busy shard cannot starve the others.
+	PerShard bool
+}
+
+// WorkerPoolStats is a snapshot of the state of a WorkerPool.
+type WorkerPoolStats struct {
+	Workers       int
+	Busy          int
+	QueueDepth    int
+	QueueCapacity int
+	Processed     int64
+	Dropped       int64
+}
+
+// WorkerPool runs tasks on a fixed amount of goroutines.
+type WorkerPool struct {
+	mu     sync.RWMutex
+	tasks  chan func()
+	policy OverflowPolicy
+	closed bool
+	// done is closed by Close, to wake up Submit calls waiting for room. The tasks
+	// channel is only closed once none of them are left.
+	done    chan struct{}
+	waiting sync.WaitGroup
+
+	workers   int
+	busy      atomic.Int64
+	processed atomic.Int64
+	dropped   atomic.Int64
+}
+
+// NewWorkerPool creates a WorkerPool and starts its workers. The defaults are used
+// for values of 0.
+func NewWorkerPool(workers, queueSize int, policy OverflowPolicy) *WorkerPool {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	p := &WorkerPool{
+		tasks:   make(chan func(), queueSize),
+		done:    make(chan struct{}),
+		policy:  policy,
+		workers: workers,
+	}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *WorkerPool) work() {
+	for task := range p.tasks {
+		p.busy.Add(1)
+		task()
+		p.busy.Add(-1)
+		p.processed.Add(1)
+	}
+}
+
+// Submit queues a task according to the overflow policy of the pool. It returns
+// whether the task was queued. A Submit waiting for room returns false once the
+// pool is closed.
+func (p *WorkerPool) Submit(task func()) bool {
+	if p.policy == OverflowBlock {
+		return p.submitWait(task)
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return false
+	}
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+		p.dropped.Add(1)
+		return false
+	}
+}
+
+// submitWait waits for room in the queue without holding the lock, so Close is
+// not held up by it.
+func (p *WorkerPool) submitWait(task func()) bool {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return false
+	}
+	p.waiting.Add(1)
+	p.mu.RUnlock()
+	defer p.waiting.Done()
+
+	select {
+	case p.tasks <- task:
+		return true
+	case <-p.done:
+		return false
+	}
+}
+
+// TrySubmit queues a task if there is room in the queue, regardless of the overflow
+// policy of the pool. It returns whether the task was queued.
+func (p *WorkerPool) TrySubmit(task func()) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return false
+	}
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *WorkerPool) Stats() WorkerPoolStats {
+	return WorkerPoolStats{
+		Workers:       p.workers,
+		Busy:          int(p.busy.Load()),
+		QueueDepth:    len(p.tasks),
+		QueueCapacity: cap(p.tasks),
+		Processed:     p.processed.Load(),
+		Dropped:       p.dropped.Load(),
+	}
+}
+
+// Close stops accepting tasks. The workers exit once the queued tasks are done.
+func (p *WorkerPool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.done)
+	p.mu.Unlock()
+
+	p.waiting.Wait()
+	close(p.tasks)
+}
+
+// WorkerPools holds either a single global WorkerPool, or one per shard.
+type WorkerPools struct {
+	pools []*WorkerPool
+}
+
+// NewWorkerPools creates the pools described by cfg. shards is only used if
+// cfg.PerShard is set.
+func NewWorkerPools(cfg WorkerPoolConfig, shards int) *WorkerPools {
+	if !cfg.PerShard || shards < 1 {
+		shards = 1
+	}
+	w := &WorkerPools{}
+	for i := 0; i < shards; i++ {
+		w.pools = append(w.pools, NewWorkerPool(cfg.Workers, cfg.QueueSize, cfg.Policy))
+	}
+	return w
+}
+
+// Pool returns the pool of a shard.
+func (w *WorkerPools) Pool(shard int) *WorkerPool {
+	if shard < 0 {
+		shard = 0
+	}
+	return w.pools[shard%len(w.pools)]
+}
+
+// Stats returns the stats of every pool, indexed by shard if the pools are per shard.
+func (w *WorkerPools) Stats() []WorkerPoolStats {
+	stats := make([]WorkerPoolStats, 0, len(w.pools))
+	for _, p := range w.pools {
+		stats = append(stats, p.Stats())
+	}
+	return stats
+}
+
+func (w *WorkerPools) Close() {
+	for _, p := range w.pools {
+		p.Close()
+	}
+}
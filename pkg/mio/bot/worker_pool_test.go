@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerPool_Submit(t *testing.T) {
+	t.Run("runs tasks", func(t *testing.T) {
+		pool := NewWorkerPool(2, 4, OverflowDrop)
+		defer pool.Close()
+		done := make(chan bool, 1)
+		assert.True(t, pool.Submit(func() { done <- true }))
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("task was not run")
+		}
+	})
+
+	t.Run("drops when full", func(t *testing.T) {
+		pool := NewWorkerPool(1, 1, OverflowDrop)
+		defer pool.Close()
+		release := make(chan struct{})
+		started := make(chan bool)
+		assert.True(t, pool.Submit(func() {
+			started <- true
+			<-release
+		}))
+		<-started
+		assert.True(t, pool.Submit(func() {}), "queue should have room for one task")
+		assert.False(t, pool.Submit(func() {}), "task should be dropped")
+		assert.False(t, pool.TrySubmit(func() {}))
+
+		stats := pool.Stats()
+		assert.Equal(t, 1, stats.Busy)
+		assert.Equal(t, 1, stats.QueueDepth)
+		assert.Equal(t, int64(1), stats.Dropped)
+		close(release)
+	})
+
+	t.Run("blocks when full", func(t *testing.T) {
+		pool := NewWorkerPool(1, 1, OverflowBlock)
+		defer pool.Close()
+		release := make(chan struct{})
+		started := make(chan bool)
+		pool.Submit(func() {
+			started <- true
+			<-release
+		})
+		<-started
+		pool.Submit(func() {})
+
+		submitted := make(chan bool)
+		go func() {
+			submitted <- pool.Submit(func() {})
+		}()
+		select {
+		case <-submitted:
+			t.Fatal("Submit did not block")
+		case <-time.After(time.Millisecond * 50):
+		}
+		close(release)
+		assert.True(t, <-submitted)
+	})
+
+	t.Run("close wakes up blocked submit", func(t *testing.T) {
+		pool := NewWorkerPool(1, 1, OverflowBlock)
+		release := make(chan struct{})
+		defer close(release)
+		started := make(chan bool)
+		pool.Submit(func() {
+			started <- true
+			<-release
+		})
+		<-started
+		pool.Submit(func() {})
+
+		submitted := make(chan bool)
+		go func() {
+			submitted <- pool.Submit(func() {})
+		}()
+		time.Sleep(time.Millisecond * 20)
+
+		closed := make(chan struct{})
+		go func() {
+			pool.Close()
+			close(closed)
+		}()
+		select {
+		case ok := <-submitted:
+			assert.False(t, ok, "Submit should fail once the pool is closed")
+		case <-time.After(time.Second):
+			t.Fatal("Submit was still blocked after Close")
+		}
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("Close was blocked by Submit")
+		}
+	})
+
+	t.Run("closed pool rejects tasks", func(t *testing.T) {
+		pool := NewWorkerPool(1, 1, OverflowBlock)
+		pool.Close()
+		assert.False(t, pool.Submit(func() {}))
+	})
+}
+
+func TestWorkerPools_Pool(t *testing.T) {
+	global := NewWorkerPools(WorkerPoolConfig{Workers: 1}, 3)
+	defer global.Close()
+	assert.Same(t, global.Pool(0), global.Pool(2))
+
+	perShard := NewWorkerPools(WorkerPoolConfig{Workers: 1, PerShard: true}, 3)
+	defer perShard.Close()
+	assert.NotSame(t, perShard.Pool(0), perShard.Pool(2))
+	assert.Len(t, perShard.Stats(), 3)
+}
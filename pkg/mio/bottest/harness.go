@@ -0,0 +1,220 @@
+// Package bottest runs modules on a real bot.Bot against an in-memory Discord, so
+// tests can send messages and interactions through the EventHandler and assert on
+// what the bot did, without a network.
+package bottest
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/intrntsrfr/meido/pkg/mio/test"
+)
+
+// The harness starts out with a guild owned by OwnerID, with a single channel.
+const (
+	Prefix    = "m?"
+	GuildID   = "100"
+	ChannelID = "200"
+	OwnerID   = "10"
+)
+
+// DefaultPermissions are the permissions of @everyone in the guild of a harness.
+const DefaultPermissions = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages |
+	discordgo.PermissionReadMessageHistory | discordgo.PermissionEmbedLinks
+
+// WaitTimeout is how long the harness waits for handlers to finish.
+const WaitTimeout = time.Second * 5
+
+// Harness is a bot running on a Session.
+type Harness struct {
+	t       testing.TB
+	Bot     *bot.Bot
+	Session *Session
+	Owner   *discordgo.User
+
+	nextID atomic.Int64
+}
+
+// New returns a harness whose bot is configured by configure, if given, on top of
+// a test config with Prefix as its prefix. The bot is closed when the test ends.
+func New(t testing.TB, configure ...func(*bot.BotBuilder)) *Harness {
+	t.Helper()
+	conf := test.NewTestConfig()
+	conf.Set("prefix", Prefix)
+
+	sess := NewSession()
+	_ = sess.Open()
+	builder := bot.NewBotBuilder(conf).
+		WithDiscord(discord.NewTestDiscord(conf, sess, mio.NewDiscardLogger())).
+		WithLogger(mio.NewDiscardLogger())
+	for _, f := range configure {
+		f(builder)
+	}
+
+	h := &Harness{t: t, Bot: builder.Build(), Session: sess}
+	h.nextID.Store(5000)
+	h.Owner = &discordgo.User{ID: OwnerID, Username: "owner"}
+	sess.AddUser(h.Owner)
+	sess.AddGuild(GuildID, "Test guild", OwnerID, DefaultPermissions)
+	sess.AddMember(GuildID, h.Owner)
+	sess.AddChannel(GuildID, ChannelID, "general")
+	t.Cleanup(h.Bot.Close)
+	return h
+}
+
+// Register hooks and registers modules, and fails the test if any of them could
+// not be registered.
+func (h *Harness) Register(mods ...bot.Module) {
+	h.t.Helper()
+	for _, mod := range mods {
+		h.Bot.RegisterModule(mod)
+		if _, err := h.Bot.FindModule(mod.Name()); err != nil {
+			h.t.Fatalf("Could not register module %v", mod.Name())
+		}
+	}
+}
+
+// Member adds a member with roleIDs to the guild of the harness.
+func (h *Harness) Member(userID, username string, roleIDs ...string) *discordgo.User {
+	u := &discordgo.User{ID: userID, Username: username}
+	h.Session.AddMember(GuildID, u, roleIDs...)
+	return u
+}
+
+// Send sends a message from author to the channel of the harness, and waits for
+// the handlers it triggers to finish.
+func (h *Harness) Send(author *discordgo.User, content string) *discord.DiscordMessage {
+	h.t.Helper()
+	msg := h.Dispatch(ChannelID, author, content)
+	h.Wait()
+	return msg
+}
+
+var mentionRe = regexp.MustCompile(`<@!?(\d+)>`)
+
+// Dispatch sends a message from author to a channel through the EventHandler, and
+// returns without waiting for handlers, which lets tests answer prompts.
+func (h *Harness) Dispatch(channelID string, author *discordgo.User, content string) *discord.DiscordMessage {
+	h.t.Helper()
+	c, err := h.Session.State().Channel(channelID)
+	if err != nil {
+		h.t.Fatalf("Unknown channel %v", channelID)
+	}
+	m := &discordgo.Message{
+		ID:        h.newID(),
+		ChannelID: channelID,
+		GuildID:   c.GuildID,
+		Content:   content,
+		Author:    author,
+		Timestamp: time.Now(),
+	}
+	for _, match := range mentionRe.FindAllStringSubmatch(content, -1) {
+		if u, err := h.Session.User(match[1]); err == nil {
+			m.Mentions = append(m.Mentions, u)
+		}
+	}
+	if c.GuildID != "" {
+		m.Member = h.member(c.GuildID, author)
+	}
+
+	msg := &discord.DiscordMessage{
+		Sess:         h.Session,
+		Discord:      h.Bot.Discord,
+		Message:      m,
+		MessageType:  discord.MessageTypeCreate,
+		TimeReceived: time.Now(),
+	}
+	h.Bot.EventHandler.SetPrefix(msg)
+	h.Bot.EventHandler.DeliverCallbacks(msg)
+	h.Bot.EventHandler.HandleMessage(msg)
+	return msg
+}
+
+// Command runs an application command as author in the channel of the harness,
+// and waits for its handlers to finish.
+func (h *Harness) Command(author *discordgo.User, data discordgo.ApplicationCommandInteractionData) *discord.DiscordInteraction {
+	h.t.Helper()
+	return h.Interact(&discordgo.Interaction{
+		Type:      discordgo.InteractionApplicationCommand,
+		ChannelID: ChannelID,
+		GuildID:   GuildID,
+		Data:      data,
+	}, author)
+}
+
+// Interact sends an interaction from author through the EventHandler, and waits
+// for its handlers to finish. The ID, member and user of it are filled in.
+func (h *Harness) Interact(it *discordgo.Interaction, author *discordgo.User) *discord.DiscordInteraction {
+	h.t.Helper()
+	it.ID = h.newID()
+	if it.GuildID == "" {
+		it.User = author
+	} else {
+		it.Member = h.member(it.GuildID, author)
+		if perms, err := h.Session.State().UserChannelPermissions(author.ID, it.ChannelID); err == nil {
+			it.Member.Permissions = perms
+		}
+	}
+
+	dit := &discord.DiscordInteraction{
+		Sess:         h.Session,
+		Discord:      h.Bot.Discord,
+		Interaction:  it,
+		TimeReceived: time.Now(),
+	}
+	h.Bot.EventHandler.HandleInteraction(dit)
+	h.Wait()
+	return dit
+}
+
+// member returns a copy of the member author is in a guild, as gateway events
+// carry it, and fails the test if author is not in the guild.
+func (h *Harness) member(guildID string, author *discordgo.User) *discordgo.Member {
+	h.t.Helper()
+	member, err := h.Session.State().Member(guildID, author.ID)
+	if err != nil {
+		h.t.Fatalf("%v is not a member of guild %v", author.ID, guildID)
+	}
+	m := *member
+	m.Roles = append([]string(nil), member.Roles...)
+	return &m
+}
+
+// Wait waits for running handlers to finish, and fails the test if they take
+// longer than WaitTimeout.
+func (h *Harness) Wait() {
+	h.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), WaitTimeout)
+	defer cancel()
+	if err := h.Bot.WaitIdle(ctx); err != nil {
+		h.t.Fatalf("Handlers did not finish: %v", err)
+	}
+}
+
+// Replies returns what the bot sent to the channel of the harness.
+func (h *Harness) Replies() []*discordgo.Message {
+	return h.Session.Messages(ChannelID)
+}
+
+// LastReply returns the last message the bot sent to the channel of the harness,
+// and fails the test if it sent none.
+func (h *Harness) LastReply() *discordgo.Message {
+	h.t.Helper()
+	replies := h.Replies()
+	if len(replies) == 0 {
+		h.t.Fatalf("Expected a reply")
+	}
+	return replies[len(replies)-1]
+}
+
+func (h *Harness) newID() string {
+	return strconv.FormatInt(h.nextID.Add(1), 10)
+}
@@ -0,0 +1,153 @@
+package bottest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// warnStore records warns the way a module would keep them in its database.
+type warnStore struct {
+	warns map[string][]string
+	calls []string
+}
+
+func (s *warnStore) AddWarn(guildID, userID, reason string) (int, error) {
+	s.calls = append(s.calls, fmt.Sprintf("AddWarn(%v, %v, %v)", guildID, userID, reason))
+	key := guildID + ":" + userID
+	s.warns[key] = append(s.warns[key], reason)
+	return len(s.warns[key]), nil
+}
+
+type warnModule struct {
+	*bot.ModuleBase
+	store *warnStore
+}
+
+func newWarnModule(b *bot.Bot, store *warnStore) *warnModule {
+	return &warnModule{ModuleBase: bot.NewModule(b, "warns", mio.NewDiscardLogger()), store: store}
+}
+
+func (m *warnModule) Hook() error {
+	warn := bot.NewModuleCommandBuilder(m, "warn").
+		Triggers("warn").
+		Usage("warn [user] <reason>").
+		RequiredPerms(discordgo.PermissionBanMembers).
+		CheckBotPerms().
+		AllowedTypes(discord.MessageTypeCreate).
+		Run(m.warn).
+		Build()
+	ping := bot.NewModuleApplicationCommandBuilder(m, "ping").
+		Type(discordgo.ChatApplicationCommand).
+		Description("ping").
+		Execute(func(it *discord.DiscordApplicationCommand) { _ = it.Respond("pong") }).
+		Build()
+	if err := m.RegisterApplicationCommands(ping); err != nil {
+		return err
+	}
+	return m.RegisterCommands(warn)
+}
+
+func (m *warnModule) warn(msg *discord.DiscordMessage, _ *bot.CommandArgs) error {
+	if len(msg.Args()) < 2 {
+		return bot.UsageError("Who should be warned?")
+	}
+	target, err := msg.GetMemberAtArg(1)
+	if err != nil {
+		return bot.NotFoundError("Could not find that user!")
+	}
+	reason := strings.Join(msg.RawArgs()[2:], " ")
+	count, err := m.store.AddWarn(msg.GuildID(), target.User.ID, reason)
+	if err != nil {
+		return err
+	}
+	if ch, err := msg.Sess.UserChannelCreate(target.User.ID); err == nil {
+		_, _ = msg.Sess.ChannelMessageSend(ch.ID, "You have been warned for: "+reason)
+	}
+	if count >= 2 {
+		if err := msg.Sess.GuildBanCreateWithReason(msg.GuildID(), target.User.ID, "Acquired 2 warnings.", 0); err != nil {
+			return err
+		}
+		_, _ = msg.Reply(target.User.Mention() + " has been banned")
+		return nil
+	}
+	_, _ = msg.Reply(target.User.Mention() + " has been warned")
+	return nil
+}
+
+func newWarnHarness(t *testing.T) (*Harness, *warnStore) {
+	h := New(t)
+	h.Session.AddRole(GuildID, "300", "Bot", 2, discordgo.PermissionBanMembers)
+	h.Session.AddRole(GuildID, "301", "Mod", 1, discordgo.PermissionBanMembers)
+	require.NoError(t, h.Session.GuildMemberRoleAdd(GuildID, BotUserID, "300"))
+	store := &warnStore{warns: make(map[string][]string)}
+	h.Register(newWarnModule(h.Bot, store))
+	return h, store
+}
+
+func TestHarness_Warn(t *testing.T) {
+	h, store := newWarnHarness(t)
+	target := h.Member("20", "spammer")
+
+	h.Send(h.Owner, "m?warn <@20> spam")
+	assert.Equal(t, "<@20> has been warned", h.LastReply().Content)
+	assert.Equal(t, ChannelID, h.Replies()[0].MessageReference.ChannelID)
+	assert.Equal(t, []string{"AddWarn(100, 20, spam)"}, store.calls)
+	require.Len(t, h.Session.DMs(target.ID), 1)
+	assert.Equal(t, "You have been warned for: spam", h.Session.DMs(target.ID)[0].Content)
+
+	h.Send(h.Owner, "m?warn <@20> more spam")
+	assert.Equal(t, "<@20> has been banned", h.LastReply().Content)
+	bans := h.Session.Bans(GuildID)
+	require.Len(t, bans, 1)
+	assert.Equal(t, "20", bans[0].User.ID)
+	assert.Equal(t, "Acquired 2 warnings.", bans[0].Reason)
+	_, err := h.Session.GuildMember(GuildID, "20")
+	assert.Error(t, err, "banned member should be removed from the guild")
+}
+
+func TestHarness_WarnErrors(t *testing.T) {
+	h, store := newWarnHarness(t)
+
+	h.Send(h.Owner, "m?warn")
+	assert.Equal(t, "Who should be warned?\nUsage: `m?warn [user] <reason>`", h.LastReply().Content)
+
+	h.Send(h.Owner, "m?warn <@404> spam")
+	assert.Equal(t, "Could not find that user!", h.LastReply().Content)
+	assert.Empty(t, store.calls)
+}
+
+func TestHarness_Permissions(t *testing.T) {
+	h, store := newWarnHarness(t)
+	mod := h.Member("30", "mod", "301")
+	member := h.Member("31", "member")
+	h.Member("20", "spammer")
+
+	h.Send(member, "m?warn <@20> spam")
+	assert.Empty(t, h.Replies(), "members without ban members should not be able to warn")
+
+	h.Send(mod, "m?warn <@20> spam")
+	assert.Len(t, h.Replies(), 1)
+
+	// a channel overwrite can take the permission away again
+	require.NoError(t, h.Session.ChannelPermissionSet(ChannelID, "301", discordgo.PermissionOverwriteTypeRole, 0, discordgo.PermissionBanMembers))
+	h.Send(mod, "m?warn <@20> spam")
+	assert.Len(t, h.Replies(), 1)
+	assert.Len(t, store.calls, 1)
+}
+
+func TestHarness_Command(t *testing.T) {
+	h, _ := newWarnHarness(t)
+	h.Command(h.Owner, discordgo.ApplicationCommandInteractionData{Name: "ping", CommandType: discordgo.ChatApplicationCommand})
+
+	responses := h.Session.Responses()
+	require.Len(t, responses, 1)
+	assert.Equal(t, "pong", responses[0].Data.Content)
+}
@@ -0,0 +1,815 @@
+package bottest
+
+import (
+	"errors"
+	"image"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+)
+
+var (
+	ErrSessionClosed   = errors.New("session is closed")
+	ErrUnknownChannel  = errors.New("unknown channel")
+	ErrUnknownMessage  = errors.New("unknown message")
+	ErrUnknownUser     = errors.New("unknown user")
+	ErrUnknownBan      = errors.New("unknown ban")
+	ErrUnknownCommand  = errors.New("unknown application command")
+	ErrUnknownFollowUp = errors.New("unknown follow-up message")
+)
+
+// BotUserID is the ID of the bot user of a Session.
+const BotUserID = "1"
+
+var _ discord.DiscordSession = (*Session)(nil)
+
+// Session is an in-memory stand-in for a Discord session. Guilds, channels, roles
+// and members live in its state, so permissions are worked out the way discordgo
+// works them out, and everything the bot does is logged instead of sent.
+type Session struct {
+	state *discordgo.State
+
+	mu        sync.Mutex
+	open      bool
+	nextID    int
+	users     map[string]*discordgo.User
+	messages  map[string][]*discordgo.Message
+	dms       map[string]string
+	bans      map[string][]*discordgo.GuildBan
+	kicks     map[string][]string
+	commands  map[string][]*discordgo.ApplicationCommand
+	responses []*discordgo.InteractionResponse
+	edits     []*discordgo.WebhookEdit
+	followUps []*discordgo.Message
+	statuses  []discordgo.UpdateStatusData
+}
+
+// NewSession returns a session which knows no guilds yet, and whose bot user has
+// the ID BotUserID.
+func NewSession() *Session {
+	s := &Session{
+		state:    discordgo.NewState(),
+		nextID:   1000,
+		users:    make(map[string]*discordgo.User),
+		messages: make(map[string][]*discordgo.Message),
+		dms:      make(map[string]string),
+		bans:     make(map[string][]*discordgo.GuildBan),
+		kicks:    make(map[string][]string),
+		commands: make(map[string][]*discordgo.ApplicationCommand),
+	}
+	s.state.User = &discordgo.User{ID: BotUserID, Username: "Mio", Bot: true}
+	s.users[BotUserID] = s.state.User
+	return s
+}
+
+// newID returns a new snowflake-like ID. s.mu must be held.
+func (s *Session) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+// AddUser makes u known to the session, without adding it to any guild.
+func (s *Session) AddUser(u *discordgo.User) *discordgo.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[u.ID] = u
+	return u
+}
+
+// AddGuild adds a guild owned by ownerID, with an @everyone role that has perms,
+// and the bot as a member.
+func (s *Session) AddGuild(guildID, name, ownerID string, perms int64) *discordgo.Guild {
+	g := &discordgo.Guild{
+		ID:      guildID,
+		Name:    name,
+		OwnerID: ownerID,
+		Roles:   []*discordgo.Role{{ID: guildID, Name: "@everyone", Permissions: perms}},
+	}
+	_ = s.state.GuildAdd(g)
+	s.AddMember(guildID, s.state.User)
+	guild, _ := s.state.Guild(guildID)
+	return guild
+}
+
+// AddChannel adds a text channel to a guild.
+func (s *Session) AddChannel(guildID, channelID, name string, overwrites ...*discordgo.PermissionOverwrite) *discordgo.Channel {
+	c := &discordgo.Channel{
+		ID:                   channelID,
+		GuildID:              guildID,
+		Name:                 name,
+		Type:                 discordgo.ChannelTypeGuildText,
+		PermissionOverwrites: overwrites,
+	}
+	_ = s.state.ChannelAdd(c)
+	return c
+}
+
+// AddRole adds a role to a guild. Roles with a higher position are higher in the
+// role hierarchy.
+func (s *Session) AddRole(guildID, roleID, name string, position int, perms int64) *discordgo.Role {
+	r := &discordgo.Role{ID: roleID, Name: name, Position: position, Permissions: perms}
+	_ = s.state.RoleAdd(guildID, r)
+	return r
+}
+
+// AddMember adds u to a guild with roleIDs, and makes it known to the session.
+func (s *Session) AddMember(guildID string, u *discordgo.User, roleIDs ...string) *discordgo.Member {
+	s.AddUser(u)
+	m := &discordgo.Member{GuildID: guildID, User: u, Roles: roleIDs, JoinedAt: time.Now()}
+	_ = s.state.MemberAdd(m)
+	return m
+}
+
+// Messages returns the messages the bot sent to a channel, oldest first.
+func (s *Session) Messages(channelID string) []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.Message(nil), s.messages[channelID]...)
+}
+
+// DMs returns the direct messages the bot sent to a user, oldest first.
+func (s *Session) DMs(userID string) []*discordgo.Message {
+	s.mu.Lock()
+	channelID, ok := s.dms[userID]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.Messages(channelID)
+}
+
+// Bans returns the bans of a guild.
+func (s *Session) Bans(guildID string) []*discordgo.GuildBan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.GuildBan(nil), s.bans[guildID]...)
+}
+
+// Kicks returns the IDs of the users that were kicked from a guild.
+func (s *Session) Kicks(guildID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.kicks[guildID]...)
+}
+
+// Responses returns the responses to interactions, oldest first.
+func (s *Session) Responses() []*discordgo.InteractionResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.InteractionResponse(nil), s.responses...)
+}
+
+// ResponseEdits returns the edits of interaction responses, oldest first.
+func (s *Session) ResponseEdits() []*discordgo.WebhookEdit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.WebhookEdit(nil), s.edits...)
+}
+
+// FollowUps returns the follow-up messages of interactions, oldest first.
+func (s *Session) FollowUps() []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.Message(nil), s.followUps...)
+}
+
+// Statuses returns the statuses the bot has set, oldest first.
+func (s *Session) Statuses() []discordgo.UpdateStatusData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]discordgo.UpdateStatusData(nil), s.statuses...)
+}
+
+func (s *Session) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.open = true
+	return nil
+}
+
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.open = false
+	return nil
+}
+
+func (s *Session) ShardID() int {
+	return 0
+}
+
+func (s *Session) State() *discordgo.State {
+	return s.state
+}
+
+func (s *Session) Real() *discordgo.Session {
+	return nil
+}
+
+// AddHandler accepts gateway event handlers, but the session never sends any
+// gateway events.
+func (s *Session) AddHandler(handler interface{}) func() {
+	return func() {}
+}
+
+func (s *Session) AddHandlerOnce(handler interface{}) func() {
+	return func() {}
+}
+
+func (s *Session) ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commands[guildID] = nil
+	for _, c := range commands {
+		s.commands[guildID] = append(s.commands[guildID], s.storeCommand(appID, guildID, "", c))
+	}
+	return append([]*discordgo.ApplicationCommand(nil), s.commands[guildID]...), nil
+}
+
+func (s *Session) ApplicationCommandCreate(appID string, guildID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created := s.storeCommand(appID, guildID, "", cmd)
+	s.commands[guildID] = append(s.commands[guildID], created)
+	return created, nil
+}
+
+func (s *Session) ApplicationCommandDelete(appID, guildID, cmdID string, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, c := range s.commands[guildID] {
+		if c.ID == cmdID {
+			s.commands[guildID] = append(s.commands[guildID][:i], s.commands[guildID][i+1:]...)
+			return nil
+		}
+	}
+	return ErrUnknownCommand
+}
+
+func (s *Session) ApplicationCommandEdit(appID, guildID, cmdID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, c := range s.commands[guildID] {
+		if c.ID == cmdID {
+			s.commands[guildID][i] = s.storeCommand(appID, guildID, cmdID, cmd)
+			return s.commands[guildID][i], nil
+		}
+	}
+	return nil, ErrUnknownCommand
+}
+
+func (s *Session) ApplicationCommands(appID, guildID string, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.ApplicationCommand(nil), s.commands[guildID]...), nil
+}
+
+// storeCommand returns a copy of c as Discord would store it. s.mu must be held.
+func (s *Session) storeCommand(appID, guildID, id string, c *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+	if id == "" {
+		id = s.newID()
+	}
+	stored := *c
+	stored.ID = id
+	stored.ApplicationID = appID
+	stored.GuildID = guildID
+	return &stored
+}
+
+func (s *Session) Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	return s.state.Channel(channelID)
+}
+
+// send logs a message from the bot to a channel.
+func (s *Session) send(channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	c, err := s.state.Channel(channelID)
+	if err != nil {
+		return nil, ErrUnknownChannel
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.open {
+		return nil, ErrSessionClosed
+	}
+	msg := &discordgo.Message{
+		ID:               s.newID(),
+		ChannelID:        channelID,
+		GuildID:          c.GuildID,
+		Content:          data.Content,
+		Embeds:           data.Embeds,
+		Components:       data.Components,
+		TTS:              data.TTS,
+		MessageReference: data.Reference,
+		Author:           s.state.User,
+		Timestamp:        time.Now(),
+	}
+	if data.Embed != nil {
+		msg.Embeds = append(msg.Embeds, data.Embed)
+	}
+	for _, f := range append(data.Files, data.File) {
+		if f != nil {
+			msg.Attachments = append(msg.Attachments, &discordgo.MessageAttachment{ID: s.newID(), Filename: f.Name, ContentType: f.ContentType})
+		}
+	}
+	s.messages[channelID] = append(s.messages[channelID], msg)
+	return msg, nil
+}
+
+// message returns a message the bot sent. s.mu must be held.
+func (s *Session) message(channelID, messageID string) (*discordgo.Message, int, error) {
+	for i, m := range s.messages[channelID] {
+		if m.ID == messageID {
+			return m, i, nil
+		}
+	}
+	return nil, -1, ErrUnknownMessage
+}
+
+func (s *Session) ChannelFileSend(channelID string, name string, r io.Reader, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.send(channelID, &discordgo.MessageSend{File: &discordgo.File{Name: name, Reader: r}})
+}
+
+func (s *Session) ChannelMessageDelete(channelID string, messageID string, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, i, err := s.message(channelID, messageID)
+	if err != nil {
+		// messages of users are not logged, but deleting them is fine
+		return nil
+	}
+	s.messages[channelID] = append(s.messages[channelID][:i], s.messages[channelID][i+1:]...)
+	return nil
+}
+
+func (s *Session) ChannelMessageEdit(channelID string, messageID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.ChannelMessageEditComplex(discordgo.NewMessageEdit(channelID, messageID).SetContent(content))
+}
+
+func (s *Session) ChannelMessageEditComplex(m *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, _, err := s.message(m.Channel, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	if m.Content != nil {
+		msg.Content = *m.Content
+	}
+	if m.Embeds != nil {
+		msg.Embeds = m.Embeds
+	}
+	if m.Components != nil {
+		msg.Components = m.Components
+	}
+	now := time.Now()
+	msg.EditedTimestamp = &now
+	return msg, nil
+}
+
+func (s *Session) ChannelMessageEditEmbed(channelID string, messageID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.ChannelMessageEditEmbeds(channelID, messageID, []*discordgo.MessageEmbed{embed})
+}
+
+func (s *Session) ChannelMessageEditEmbeds(channelID string, messageID string, embeds []*discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.ChannelMessageEditComplex(discordgo.NewMessageEdit(channelID, messageID).SetEmbeds(embeds))
+}
+
+func (s *Session) ChannelMessagePin(channelID string, messageID string, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, _, err := s.message(channelID, messageID)
+	if err != nil {
+		return err
+	}
+	msg.Pinned = true
+	return nil
+}
+
+func (s *Session) ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.send(channelID, &discordgo.MessageSend{Content: content})
+}
+
+func (s *Session) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.send(channelID, data)
+}
+
+func (s *Session) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.send(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+func (s *Session) ChannelMessageSendEmbedReply(channelID string, embed *discordgo.MessageEmbed, reference *discordgo.MessageReference, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.send(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}, Reference: reference})
+}
+
+func (s *Session) ChannelMessageSendEmbeds(channelID string, embeds []*discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.send(channelID, &discordgo.MessageSend{Embeds: embeds})
+}
+
+func (s *Session) ChannelMessageSendEmbedsReply(channelID string, embeds []*discordgo.MessageEmbed, reference *discordgo.MessageReference, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.send(channelID, &discordgo.MessageSend{Embeds: embeds, Reference: reference})
+}
+
+func (s *Session) ChannelMessageSendReply(channelID string, content string, reference *discordgo.MessageReference, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.send(channelID, &discordgo.MessageSend{Content: content, Reference: reference})
+}
+
+// ChannelMessages returns the messages the bot sent to the channel, newest first
+// like Discord returns them. Only limit is respected.
+func (s *Session) ChannelMessages(channelID string, limit int, beforeID string, afterID string, aroundID string, options ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+	msgs := s.Messages(channelID)
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+	if limit > 0 && len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+	return msgs, nil
+}
+
+func (s *Session) ChannelMessagesBulkDelete(channelID string, messages []string, options ...discordgo.RequestOption) error {
+	for _, id := range messages {
+		_ = s.ChannelMessageDelete(channelID, id)
+	}
+	return nil
+}
+
+func (s *Session) ChannelPermissionSet(channelID, targetID string, targetType discordgo.PermissionOverwriteType, allow, deny int64, options ...discordgo.RequestOption) error {
+	c, err := s.state.Channel(channelID)
+	if err != nil {
+		return ErrUnknownChannel
+	}
+	s.state.Lock()
+	defer s.state.Unlock()
+	for _, o := range c.PermissionOverwrites {
+		if o.ID == targetID {
+			o.Type, o.Allow, o.Deny = targetType, allow, deny
+			return nil
+		}
+	}
+	c.PermissionOverwrites = append(c.PermissionOverwrites, &discordgo.PermissionOverwrite{ID: targetID, Type: targetType, Allow: allow, Deny: deny})
+	return nil
+}
+
+func (s *Session) ChannelTyping(channelID string, options ...discordgo.RequestOption) error {
+	return nil
+}
+
+func (s *Session) Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	return s.state.Guild(guildID)
+}
+
+func (s *Session) GuildBanCreate(guildID string, userID string, days int, options ...discordgo.RequestOption) error {
+	return s.GuildBanCreateWithReason(guildID, userID, "", days)
+}
+
+// GuildBanCreateWithReason bans a user, and removes them from the guild if they
+// are a member.
+func (s *Session) GuildBanCreateWithReason(guildID string, userID string, reason string, days int, options ...discordgo.RequestOption) error {
+	if _, err := s.state.Guild(guildID); err != nil {
+		return err
+	}
+	u, err := s.User(userID)
+	if err != nil {
+		u = &discordgo.User{ID: userID}
+	}
+	s.removeMember(guildID, userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bans[guildID] = append(s.bans[guildID], &discordgo.GuildBan{Reason: reason, User: u})
+	return nil
+}
+
+func (s *Session) GuildBanDelete(guildID string, userID string, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, b := range s.bans[guildID] {
+		if b.User.ID == userID {
+			s.bans[guildID] = append(s.bans[guildID][:i], s.bans[guildID][i+1:]...)
+			return nil
+		}
+	}
+	return ErrUnknownBan
+}
+
+func (s *Session) GuildBans(guildID string, limit int, beforeID string, afterID string, options ...discordgo.RequestOption) ([]*discordgo.GuildBan, error) {
+	bans := s.Bans(guildID)
+	if limit > 0 && len(bans) > limit {
+		bans = bans[:limit]
+	}
+	return bans, nil
+}
+
+func (s *Session) GuildChannels(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
+	g, err := s.state.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	s.state.RLock()
+	defer s.state.RUnlock()
+	return append([]*discordgo.Channel(nil), g.Channels...), nil
+}
+
+func (s *Session) GuildIcon(guildID string, options ...discordgo.RequestOption) (image.Image, error) {
+	return nil, errors.New("guild has no icon")
+}
+
+func (s *Session) GuildMember(guildID string, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error) {
+	return s.state.Member(guildID, userID)
+}
+
+func (s *Session) GuildMemberAdd(guildID string, userID string, data *discordgo.GuildMemberAddParams, options ...discordgo.RequestOption) error {
+	u, err := s.User(userID)
+	if err != nil {
+		return err
+	}
+	var roles []string
+	if data != nil {
+		roles = data.Roles
+	}
+	s.AddMember(guildID, u, roles...)
+	return nil
+}
+
+func (s *Session) GuildMemberDelete(guildID string, userID string, options ...discordgo.RequestOption) error {
+	return s.GuildMemberDeleteWithReason(guildID, userID, "")
+}
+
+// GuildMemberDeleteWithReason kicks a member.
+func (s *Session) GuildMemberDeleteWithReason(guildID string, userID string, reason string, options ...discordgo.RequestOption) error {
+	if _, err := s.state.Member(guildID, userID); err != nil {
+		return err
+	}
+	s.removeMember(guildID, userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kicks[guildID] = append(s.kicks[guildID], userID)
+	return nil
+}
+
+func (s *Session) removeMember(guildID, userID string) {
+	if m, err := s.state.Member(guildID, userID); err == nil {
+		_ = s.state.MemberRemove(m)
+	}
+}
+
+func (s *Session) GuildMemberRoleAdd(guildID string, userID string, roleID string, options ...discordgo.RequestOption) error {
+	m, err := s.state.Member(guildID, userID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.state.Role(guildID, roleID); err != nil {
+		return err
+	}
+	s.state.Lock()
+	defer s.state.Unlock()
+	for _, r := range m.Roles {
+		if r == roleID {
+			return nil
+		}
+	}
+	m.Roles = append(m.Roles, roleID)
+	return nil
+}
+
+func (s *Session) GuildMemberRoleRemove(guildID string, userID string, roleID string, options ...discordgo.RequestOption) error {
+	m, err := s.state.Member(guildID, userID)
+	if err != nil {
+		return err
+	}
+	s.state.Lock()
+	defer s.state.Unlock()
+	for i, r := range m.Roles {
+		if r == roleID {
+			m.Roles = append(m.Roles[:i], m.Roles[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *Session) GuildMemberTimeout(guildID string, userID string, until *time.Time, options ...discordgo.RequestOption) error {
+	m, err := s.state.Member(guildID, userID)
+	if err != nil {
+		return err
+	}
+	s.state.Lock()
+	defer s.state.Unlock()
+	m.CommunicationDisabledUntil = until
+	return nil
+}
+
+func (s *Session) GuildMembers(guildID string, after string, limit int, options ...discordgo.RequestOption) ([]*discordgo.Member, error) {
+	g, err := s.state.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	s.state.RLock()
+	defer s.state.RUnlock()
+	members := append([]*discordgo.Member(nil), g.Members...)
+	if limit > 0 && len(members) > limit {
+		members = members[:limit]
+	}
+	return members, nil
+}
+
+func (s *Session) GuildRoleCreate(guildID string, data *discordgo.RoleParams, options ...discordgo.RequestOption) (*discordgo.Role, error) {
+	s.mu.Lock()
+	id := s.newID()
+	s.mu.Unlock()
+	r := &discordgo.Role{ID: id}
+	applyRoleParams(r, data)
+	if err := s.state.RoleAdd(guildID, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *Session) GuildRoleDelete(guildID string, roleID string, options ...discordgo.RequestOption) error {
+	return s.state.RoleRemove(guildID, roleID)
+}
+
+func (s *Session) GuildRoleEdit(guildID string, roleID string, data *discordgo.RoleParams, options ...discordgo.RequestOption) (*discordgo.Role, error) {
+	r, err := s.state.Role(guildID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	s.state.Lock()
+	defer s.state.Unlock()
+	applyRoleParams(r, data)
+	return r, nil
+}
+
+func applyRoleParams(r *discordgo.Role, data *discordgo.RoleParams) {
+	if data == nil {
+		return
+	}
+	if data.Name != "" {
+		r.Name = data.Name
+	}
+	if data.Color != nil {
+		r.Color = *data.Color
+	}
+	if data.Hoist != nil {
+		r.Hoist = *data.Hoist
+	}
+	if data.Permissions != nil {
+		r.Permissions = *data.Permissions
+	}
+	if data.Mentionable != nil {
+		r.Mentionable = *data.Mentionable
+	}
+}
+
+func (s *Session) GuildRoles(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Role, error) {
+	g, err := s.state.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	s.state.RLock()
+	defer s.state.RUnlock()
+	return append([]*discordgo.Role(nil), g.Roles...), nil
+}
+
+func (s *Session) GuildSplash(guildID string, options ...discordgo.RequestOption) (image.Image, error) {
+	return nil, errors.New("guild has no splash")
+}
+
+// The state already has every member, so requesting them does nothing.
+
+func (s *Session) RequestGuildMembers(guildID string, query string, limit int, nonce string, presences bool) error {
+	return nil
+}
+
+func (s *Session) RequestGuildMembersBatch(guildIDs []string, query string, limit int, nonce string, presences bool) error {
+	return nil
+}
+
+func (s *Session) RequestGuildMembersBatchList(guildIDs []string, userIDs []string, limit int, nonce string, presences bool) error {
+	return nil
+}
+
+func (s *Session) RequestGuildMembersList(guildID string, userIDs []string, limit int, nonce string, presences bool) error {
+	return nil
+}
+
+func (s *Session) User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userID]
+	if !ok {
+		return nil, ErrUnknownUser
+	}
+	return u, nil
+}
+
+// UserChannelCreate returns the DM channel of a user, creating it the first time.
+// What the bot sends to it can be found with DMs.
+func (s *Session) UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	u, err := s.User(recipientID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	channelID, ok := s.dms[recipientID]
+	if !ok {
+		channelID = s.newID()
+		s.dms[recipientID] = channelID
+	}
+	s.mu.Unlock()
+	if ok {
+		return s.state.Channel(channelID)
+	}
+
+	c := &discordgo.Channel{ID: channelID, Type: discordgo.ChannelTypeDM, Recipients: []*discordgo.User{u}}
+	if err := s.state.ChannelAdd(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Session) UpdateStatusComplex(usd discordgo.UpdateStatusData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses = append(s.statuses, usd)
+	return nil
+}
+
+func (s *Session) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func (s *Session) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.edits = append(s.edits, newresp)
+	msg := &discordgo.Message{ID: s.newID(), ChannelID: interaction.ChannelID, GuildID: interaction.GuildID, Author: s.state.User}
+	if newresp.Content != nil {
+		msg.Content = *newresp.Content
+	}
+	return msg, nil
+}
+
+func (s *Session) FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg := &discordgo.Message{
+		ID:        s.newID(),
+		ChannelID: interaction.ChannelID,
+		GuildID:   interaction.GuildID,
+		Content:   data.Content,
+		Embeds:    data.Embeds,
+		Flags:     data.Flags,
+		Author:    s.state.User,
+		Timestamp: time.Now(),
+	}
+	s.followUps = append(s.followUps, msg)
+	return msg, nil
+}
+
+func (s *Session) followUp(messageID string) (int, error) {
+	for i, m := range s.followUps {
+		if m.ID == messageID {
+			return i, nil
+		}
+	}
+	return -1, ErrUnknownFollowUp
+}
+
+func (s *Session) FollowupMessageEdit(interaction *discordgo.Interaction, messageID string, data *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.followUp(messageID)
+	if err != nil {
+		return nil, err
+	}
+	msg := s.followUps[i]
+	if data.Content != nil {
+		msg.Content = *data.Content
+	}
+	if data.Embeds != nil {
+		msg.Embeds = *data.Embeds
+	}
+	return msg, nil
+}
+
+func (s *Session) FollowupMessageDelete(interaction *discordgo.Interaction, messageID string, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.followUp(messageID)
+	if err != nil {
+		return err
+	}
+	s.followUps = append(s.followUps[:i], s.followUps[i+1:]...)
+	return nil
+}
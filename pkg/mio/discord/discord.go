@@ -35,6 +35,10 @@ type DiscordSession interface {
 	AddHandler(handler interface{}) func()
 	AddHandlerOnce(handler interface{}) func()
 	ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) (createdCommands []*discordgo.ApplicationCommand, err error)
+	ApplicationCommandCreate(appID string, guildID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (ccmd *discordgo.ApplicationCommand, err error)
+	ApplicationCommandDelete(appID, guildID, cmdID string, options ...discordgo.RequestOption) error
+	ApplicationCommandEdit(appID, guildID, cmdID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (updated *discordgo.ApplicationCommand, err error)
+	ApplicationCommands(appID, guildID string, options ...discordgo.RequestOption) (cmd []*discordgo.ApplicationCommand, err error)
 	Channel(channelID string, options ...discordgo.RequestOption) (st *discordgo.Channel, err error)
 	ChannelFileSend(channelID, name string, r io.Reader, options ...discordgo.RequestOption) (*discordgo.Message, error)
 	ChannelMessageDelete(channelID string, messageID string, options ...discordgo.RequestOption) (err error)
@@ -82,6 +86,10 @@ type DiscordSession interface {
 	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (st *discordgo.Channel, err error)
 	UpdateStatusComplex(usd discordgo.UpdateStatusData) (err error)
 	InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error
+	InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	FollowupMessageEdit(interaction *discordgo.Interaction, messageID string, data *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	FollowupMessageDelete(interaction *discordgo.Interaction, messageID string, options ...discordgo.RequestOption) error
 }
 
 type SessionWrapper struct {
@@ -273,6 +281,7 @@ func (d *Discord) onInteractionCreate(s *discordgo.Session, m *discordgo.Interac
 		Interaction:  m.Interaction,
 		TimeReceived: time.Now(),
 		Shard:        s.ShardID,
+		response:     &responseState{},
 	}
 }
 
@@ -1,18 +1,59 @@
 package discord
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+var (
+	ErrModalFieldNotFound         = errors.New("modal field not found")
+	ErrInteractionAcknowledged    = errors.New("interaction has already been responded to")
+	ErrInteractionNotAcknowledged = errors.New("interaction has not been responded to")
+)
+
 type DiscordInteraction struct {
 	Sess         DiscordSession `json:"-"`
 	Discord      *Discord       `json:"-"`
 	Interaction  *discordgo.Interaction
 	TimeReceived time.Time
 	Shard        int
+
+	ctx      context.Context
+	response *responseState
+}
+
+// responseState keeps track of how an interaction was responded to. It is shared
+// by the copies WithContext makes.
+type responseState struct {
+	mu           sync.Mutex
+	acknowledged bool
+	deferred     bool
+	ephemeral    bool
+}
+
+// Context returns the context of the interaction. Handlers get a context that is
+// cancelled on shutdown or when their deadline passes. It is never nil.
+func (it *DiscordInteraction) Context() context.Context {
+	if it.ctx == nil {
+		return context.Background()
+	}
+	return it.ctx
+}
+
+// WithContext returns a shallow copy of the interaction with its context set to ctx.
+func (it *DiscordInteraction) WithContext(ctx context.Context) *DiscordInteraction {
+	it.state()
+	it2 := *it
+	it2.ctx = ctx
+	return &it2
 }
 
 func (it *DiscordInteraction) ID() string {
@@ -38,12 +79,144 @@ func (it *DiscordInteraction) IsDM() bool {
 	return it.Interaction.GuildID == ""
 }
 
+// AuthorHasPermissions returns whether the author has perm in the channel of the interaction.
+// Authors never have permissions in DMs.
+func (it *DiscordInteraction) AuthorHasPermissions(perm int64) bool {
+	if it.Interaction.Member == nil {
+		return false
+	}
+	perms := it.Interaction.Member.Permissions
+	return perms&discordgo.PermissionAdministrator != 0 || perms&perm == perm
+}
+
+func (it *DiscordInteraction) state() *responseState {
+	if it.response == nil {
+		it.response = &responseState{}
+	}
+	return it.response
+}
+
+// respond sends resp as the response to the interaction. Once the interaction has
+// been deferred, messages are edited into the deferred response instead, and
+// ErrInteractionAcknowledged is returned if it has already been responded to.
+// Ephemeral messages after a public deferral are sent as follow-ups, as the
+// deferred response can not be made ephemeral.
+func (it *DiscordInteraction) respond(resp *discordgo.InteractionResponse) error {
+	st := it.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.deferred && isMessageResponse(resp.Type) {
+		if !st.ephemeral && resp.Data != nil && resp.Data.Flags&discordgo.MessageFlagsEphemeral != 0 {
+			_, err := it.Sess.FollowupMessageCreate(it.Interaction, true, webhookParams(resp.Data))
+			return err
+		}
+		_, err := it.Sess.InteractionResponseEdit(it.Interaction, webhookEdit(resp.Data))
+		return err
+	}
+	if st.acknowledged {
+		return ErrInteractionAcknowledged
+	}
+	if err := it.Sess.InteractionRespond(it.Interaction, resp); err != nil {
+		return err
+	}
+	st.acknowledged = true
+	return nil
+}
+
+func isMessageResponse(t discordgo.InteractionResponseType) bool {
+	return t == discordgo.InteractionResponseChannelMessageWithSource || t == discordgo.InteractionResponseUpdateMessage
+}
+
+func webhookEdit(data *discordgo.InteractionResponseData) *discordgo.WebhookEdit {
+	if data == nil {
+		data = &discordgo.InteractionResponseData{}
+	}
+	return &discordgo.WebhookEdit{
+		Content:         &data.Content,
+		Embeds:          &data.Embeds,
+		Components:      &data.Components,
+		Files:           data.Files,
+		AllowedMentions: data.AllowedMentions,
+	}
+}
+
+func webhookParams(data *discordgo.InteractionResponseData) *discordgo.WebhookParams {
+	return &discordgo.WebhookParams{
+		Content:         data.Content,
+		Embeds:          data.Embeds,
+		Components:      data.Components,
+		Files:           data.Files,
+		AllowedMentions: data.AllowedMentions,
+		Flags:           data.Flags,
+	}
+}
+
+// Acknowledged returns whether the interaction has been responded to or deferred.
+func (it *DiscordInteraction) Acknowledged() bool {
+	st := it.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.acknowledged
+}
+
+// Defer acknowledges the interaction and shows that the bot is thinking, which
+// gives handlers 15 minutes to respond instead of 3 seconds. Responding afterwards
+// edits the deferred response, which stays ephemeral if ephemeral is set. If it
+// is not, ephemeral messages are sent as follow-ups instead.
+func (it *DiscordInteraction) Defer(ephemeral bool) error {
+	st := it.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.acknowledged {
+		return ErrInteractionAcknowledged
+	}
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}
+	if ephemeral {
+		resp.Data = &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}
+	}
+	if err := it.Sess.InteractionRespond(it.Interaction, resp); err != nil {
+		return err
+	}
+	st.acknowledged = true
+	st.deferred = true
+	st.ephemeral = ephemeral
+	return nil
+}
+
+// EditOriginal edits the response to the interaction.
+func (it *DiscordInteraction) EditOriginal(edit *discordgo.WebhookEdit) (*discordgo.Message, error) {
+	if !it.Acknowledged() {
+		return nil, ErrInteractionNotAcknowledged
+	}
+	return it.Sess.InteractionResponseEdit(it.Interaction, edit)
+}
+
+// FollowUp sends a message after the interaction has been responded to.
+func (it *DiscordInteraction) FollowUp(params *discordgo.WebhookParams) (*discordgo.Message, error) {
+	if !it.Acknowledged() {
+		return nil, ErrInteractionNotAcknowledged
+	}
+	return it.Sess.FollowupMessageCreate(it.Interaction, true, params)
+}
+
+// EditFollowUp edits a message sent with FollowUp.
+func (it *DiscordInteraction) EditFollowUp(messageID string, edit *discordgo.WebhookEdit) (*discordgo.Message, error) {
+	return it.Sess.FollowupMessageEdit(it.Interaction, messageID, edit)
+}
+
+// DeleteFollowUp deletes a message sent with FollowUp.
+func (it *DiscordInteraction) DeleteFollowUp(messageID string) error {
+	return it.Sess.FollowupMessageDelete(it.Interaction, messageID)
+}
+
 func (it *DiscordInteraction) RespondComplex(data *discordgo.InteractionResponseData, responseType discordgo.InteractionResponseType) error {
 	resp := &discordgo.InteractionResponse{
 		Type: responseType,
 		Data: data,
 	}
-	return it.Sess.InteractionRespond(it.Interaction, resp)
+	return it.respond(resp)
 }
 
 func (it *DiscordInteraction) Respond(text string) error {
@@ -53,7 +226,7 @@ func (it *DiscordInteraction) Respond(text string) error {
 			Content: text,
 		},
 	}
-	return it.Sess.InteractionRespond(it.Interaction, resp)
+	return it.respond(resp)
 }
 
 func (it *DiscordInteraction) RespondEmpty() error {
@@ -61,7 +234,7 @@ func (it *DiscordInteraction) RespondEmpty() error {
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{},
 	}
-	return it.Sess.InteractionRespond(it.Interaction, resp)
+	return it.respond(resp)
 }
 
 func (it *DiscordInteraction) UpdateRespose(text string) error {
@@ -71,7 +244,7 @@ func (it *DiscordInteraction) UpdateRespose(text string) error {
 			Content: text,
 		},
 	}
-	return it.Sess.InteractionRespond(it.Interaction, resp)
+	return it.respond(resp)
 }
 
 func (it *DiscordInteraction) RespondEmbed(embed *discordgo.MessageEmbed) error {
@@ -81,7 +254,7 @@ func (it *DiscordInteraction) RespondEmbed(embed *discordgo.MessageEmbed) error
 			Embeds: []*discordgo.MessageEmbed{embed},
 		},
 	}
-	return it.Sess.InteractionRespond(it.Interaction, resp)
+	return it.respond(resp)
 }
 
 func (it *DiscordInteraction) UpdateResposeEmbed(embed *discordgo.MessageEmbed) error {
@@ -91,7 +264,7 @@ func (it *DiscordInteraction) UpdateResposeEmbed(embed *discordgo.MessageEmbed)
 			Embeds: []*discordgo.MessageEmbed{embed},
 		},
 	}
-	return it.Sess.InteractionRespond(it.Interaction, resp)
+	return it.respond(resp)
 }
 
 func (it *DiscordInteraction) RespondEphemeral(text string) error {
@@ -102,7 +275,41 @@ func (it *DiscordInteraction) RespondEphemeral(text string) error {
 			Flags:   discordgo.MessageFlagsEphemeral,
 		},
 	}
-	return it.Sess.InteractionRespond(it.Interaction, resp)
+	return it.respond(resp)
+}
+
+// MaxAutocompleteChoices is how many choices Discord shows for an autocomplete interaction.
+const MaxAutocompleteChoices = 25
+
+// RespondChoices responds to an autocomplete interaction with choices. Choices past
+// MaxAutocompleteChoices are dropped.
+func (it *DiscordInteraction) RespondChoices(choices []*discordgo.ApplicationCommandOptionChoice) error {
+	if len(choices) > MaxAutocompleteChoices {
+		choices = choices[:MaxAutocompleteChoices]
+	}
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	}
+	return it.respond(resp)
+}
+
+// RespondStringChoices responds to an autocomplete interaction with choices that
+// are named after their values.
+func (it *DiscordInteraction) RespondStringChoices(values ...string) error {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(values))
+	for _, v := range values {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: v, Value: v})
+	}
+	return it.RespondChoices(choices)
+}
+
+// RespondModal opens the modal described by data, which builders.ModalBuilder
+// builds. Modals can not be opened in response to a modal submit.
+func (it *DiscordInteraction) RespondModal(data *discordgo.InteractionResponseData) error {
+	return it.RespondComplex(data, discordgo.InteractionResponseModal)
 }
 
 func (it *DiscordInteraction) RespondFile(text, name string, reader io.Reader) error {
@@ -116,7 +323,7 @@ func (it *DiscordInteraction) RespondFile(text, name string, reader io.Reader) e
 			}},
 		},
 	}
-	return it.Sess.InteractionRespond(it.Interaction, resp)
+	return it.respond(resp)
 }
 
 type DiscordApplicationCommand struct {
@@ -164,6 +371,145 @@ func flattenOptionsImpl(options []*discordgo.ApplicationCommandInteractionDataOp
 	}
 }
 
+// SubcommandPath returns the subcommand that was used, as group:subcommand or
+// subcommand, or an empty string for commands without subcommands.
+func (d *DiscordApplicationCommand) SubcommandPath() string {
+	if len(d.Data.Options) == 0 {
+		return ""
+	}
+	opt := d.Data.Options[0]
+	switch opt.Type {
+	case discordgo.ApplicationCommandOptionSubCommand:
+		return opt.Name
+	case discordgo.ApplicationCommandOptionSubCommandGroup:
+		if len(opt.Options) > 0 {
+			return opt.Name + ":" + opt.Options[0].Name
+		}
+	}
+	return ""
+}
+
+// DiscordSubcommand is an application command that was used through one of its
+// subcommands. Its option getters take the names of the options of the
+// subcommand, and report false for options that are missing or of another type.
+type DiscordSubcommand struct {
+	*DiscordApplicationCommand
+	Path string
+}
+
+// Option returns the option of the subcommand called name.
+func (d *DiscordSubcommand) Option(name string) (*discordgo.ApplicationCommandInteractionDataOption, bool) {
+	return d.Options(d.Path + ":" + name)
+}
+
+func (d *DiscordSubcommand) typedOption(name string, t discordgo.ApplicationCommandOptionType) (*discordgo.ApplicationCommandInteractionDataOption, bool) {
+	opt, ok := d.Option(name)
+	if !ok || opt.Type != t {
+		return nil, false
+	}
+	return opt, true
+}
+
+func (d *DiscordSubcommand) String(name string) (string, bool) {
+	opt, ok := d.typedOption(name, discordgo.ApplicationCommandOptionString)
+	if !ok {
+		return "", false
+	}
+	return opt.StringValue(), true
+}
+
+func (d *DiscordSubcommand) Int(name string) (int64, bool) {
+	opt, ok := d.typedOption(name, discordgo.ApplicationCommandOptionInteger)
+	if !ok {
+		return 0, false
+	}
+	return opt.IntValue(), true
+}
+
+func (d *DiscordSubcommand) Float(name string) (float64, bool) {
+	opt, ok := d.typedOption(name, discordgo.ApplicationCommandOptionNumber)
+	if !ok {
+		return 0, false
+	}
+	return opt.FloatValue(), true
+}
+
+func (d *DiscordSubcommand) Bool(name string) (bool, bool) {
+	opt, ok := d.typedOption(name, discordgo.ApplicationCommandOptionBoolean)
+	if !ok {
+		return false, false
+	}
+	return opt.BoolValue(), true
+}
+
+// User returns the user given to the option called name, as Discord resolved it.
+func (d *DiscordSubcommand) User(name string) (*discordgo.User, bool) {
+	opt, ok := d.typedOption(name, discordgo.ApplicationCommandOptionUser)
+	if !ok {
+		return nil, false
+	}
+	id := fmt.Sprint(opt.Value)
+	if d.Data.Resolved != nil && d.Data.Resolved.Users[id] != nil {
+		return d.Data.Resolved.Users[id], true
+	}
+	return &discordgo.User{ID: id}, true
+}
+
+// Role returns the role given to the option called name, as Discord resolved it.
+func (d *DiscordSubcommand) Role(name string) (*discordgo.Role, bool) {
+	opt, ok := d.typedOption(name, discordgo.ApplicationCommandOptionRole)
+	if !ok {
+		return nil, false
+	}
+	id := fmt.Sprint(opt.Value)
+	if d.Data.Resolved != nil && d.Data.Resolved.Roles[id] != nil {
+		return d.Data.Resolved.Roles[id], true
+	}
+	return &discordgo.Role{ID: id}, true
+}
+
+// Channel returns the channel given to the option called name, as Discord
+// resolved it.
+func (d *DiscordSubcommand) Channel(name string) (*discordgo.Channel, bool) {
+	opt, ok := d.typedOption(name, discordgo.ApplicationCommandOptionChannel)
+	if !ok {
+		return nil, false
+	}
+	id := fmt.Sprint(opt.Value)
+	if d.Data.Resolved != nil && d.Data.Resolved.Channels[id] != nil {
+		return d.Data.Resolved.Channels[id], true
+	}
+	return &discordgo.Channel{ID: id}, true
+}
+
+// DiscordAutocomplete is sent while a user types an option of an application
+// command that suggests values.
+type DiscordAutocomplete struct {
+	*DiscordApplicationCommand
+}
+
+// Focused returns the option that is being typed, and its key as Options keys it.
+func (d *DiscordAutocomplete) Focused() (string, *discordgo.ApplicationCommandInteractionDataOption, bool) {
+	if d.options == nil {
+		d.options = flattenOptions(d.Data.Options)
+	}
+	for key, opt := range d.options {
+		if opt.Focused {
+			return key, opt, true
+		}
+	}
+	return "", nil, false
+}
+
+// Query returns what has been typed into the focused option so far.
+func (d *DiscordAutocomplete) Query() string {
+	_, opt, ok := d.Focused()
+	if !ok || opt.Value == nil {
+		return ""
+	}
+	return fmt.Sprint(opt.Value)
+}
+
 type DiscordMessageComponent struct {
 	*DiscordInteraction
 	Data discordgo.MessageComponentInteractionData
@@ -173,3 +519,55 @@ type DiscordModalSubmit struct {
 	*DiscordInteraction
 	Data discordgo.ModalSubmitInteractionData
 }
+
+// Field returns the value of the text input with customID.
+func (d *DiscordModalSubmit) Field(customID string) (string, error) {
+	for _, c := range d.Data.Components {
+		row, ok := c.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rc := range row.Components {
+			if input, ok := rc.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return input.Value, nil
+			}
+		}
+	}
+	return "", ErrModalFieldNotFound
+}
+
+// FieldOr returns the trimmed value of the text input with customID, or def if it
+// is missing or was left empty.
+func (d *DiscordModalSubmit) FieldOr(customID, def string) string {
+	val, err := d.Field(customID)
+	if val = strings.TrimSpace(val); err != nil || val == "" {
+		return def
+	}
+	return val
+}
+
+// FieldInt returns the value of the text input with customID as an int.
+func (d *DiscordModalSubmit) FieldInt(customID string) (int, error) {
+	val, err := d.Field(customID)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(val))
+}
+
+// Fields returns the values of all text inputs, keyed by their custom IDs.
+func (d *DiscordModalSubmit) Fields() map[string]string {
+	fields := make(map[string]string)
+	for _, c := range d.Data.Components {
+		row, ok := c.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rc := range row.Components {
+			if input, ok := rc.(*discordgo.TextInput); ok {
+				fields[input.CustomID] = input.Value
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,217 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/discord/mocks"
+)
+
+func TestDiscordInteraction_AuthorHasPermissions(t *testing.T) {
+	tests := []struct {
+		name   string
+		member *discordgo.Member
+		perm   int64
+		want   bool
+	}{
+		{"DM", nil, discordgo.PermissionSendMessages, false},
+		{"has permission", &discordgo.Member{Permissions: discordgo.PermissionBanMembers | discordgo.PermissionKickMembers}, discordgo.PermissionBanMembers, true},
+		{"missing permission", &discordgo.Member{Permissions: discordgo.PermissionKickMembers}, discordgo.PermissionBanMembers, false},
+		{"administrator", &discordgo.Member{Permissions: discordgo.PermissionAdministrator}, discordgo.PermissionBanMembers, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := &DiscordInteraction{Interaction: &discordgo.Interaction{Member: tt.member}}
+			if got := it.AuthorHasPermissions(tt.perm); got != tt.want {
+				t.Errorf("DiscordInteraction.AuthorHasPermissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscordModalSubmit_Fields(t *testing.T) {
+	it := &DiscordModalSubmit{Data: discordgo.ModalSubmitInteractionData{
+		Components: []discordgo.MessageComponent{
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				&discordgo.TextInput{CustomID: "reason", Value: " spam "},
+			}},
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				&discordgo.TextInput{CustomID: "page", Value: "3"},
+			}},
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				&discordgo.TextInput{CustomID: "empty", Value: "  "},
+			}},
+		},
+	}}
+
+	if got, err := it.Field("reason"); err != nil || got != " spam " {
+		t.Errorf("DiscordModalSubmit.Field() = %q, %v, want %q", got, err, " spam ")
+	}
+	if _, err := it.Field("missing"); err != ErrModalFieldNotFound {
+		t.Errorf("DiscordModalSubmit.Field() error = %v, want %v", err, ErrModalFieldNotFound)
+	}
+	if got := it.FieldOr("reason", "none"); got != "spam" {
+		t.Errorf("DiscordModalSubmit.FieldOr() = %q, want %q", got, "spam")
+	}
+	if got := it.FieldOr("empty", "none"); got != "none" {
+		t.Errorf("DiscordModalSubmit.FieldOr() = %q, want %q", got, "none")
+	}
+	if got, err := it.FieldInt("page"); err != nil || got != 3 {
+		t.Errorf("DiscordModalSubmit.FieldInt() = %v, %v, want 3", got, err)
+	}
+	if _, err := it.FieldInt("reason"); err == nil {
+		t.Errorf("DiscordModalSubmit.FieldInt() expected an error for a non-number")
+	}
+	if got := it.Fields(); len(got) != 3 || got["page"] != "3" {
+		t.Errorf("DiscordModalSubmit.Fields() = %v", got)
+	}
+}
+
+type responseRecorder struct {
+	*mocks.DiscordSessionMock
+	responses []*discordgo.InteractionResponse
+	edits     []*discordgo.WebhookEdit
+	followUps []*discordgo.WebhookParams
+}
+
+func (s *responseRecorder) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func (s *responseRecorder) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.edits = append(s.edits, newresp)
+	return &discordgo.Message{}, nil
+}
+
+func (s *responseRecorder) FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.followUps = append(s.followUps, data)
+	return &discordgo.Message{ID: "2", Content: data.Content}, nil
+}
+
+func TestDiscordInteraction_Defer(t *testing.T) {
+	sess := &responseRecorder{DiscordSessionMock: mocks.NewDiscordSession("token", 1)}
+	it := &DiscordInteraction{Sess: sess, Interaction: &discordgo.Interaction{}}
+
+	if _, err := it.FollowUp(&discordgo.WebhookParams{Content: "early"}); err != ErrInteractionNotAcknowledged {
+		t.Errorf("DiscordInteraction.FollowUp() error = %v, want %v", err, ErrInteractionNotAcknowledged)
+	}
+	if err := it.Defer(true); err != nil {
+		t.Fatalf("DiscordInteraction.Defer() error = %v", err)
+	}
+	if len(sess.responses) != 1 || sess.responses[0].Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Fatalf("Expected a deferred response, got %v", sess.responses)
+	}
+	if sess.responses[0].Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Errorf("Expected the deferred response to be ephemeral")
+	}
+	if err := it.Defer(false); err != ErrInteractionAcknowledged {
+		t.Errorf("DiscordInteraction.Defer() error = %v, want %v", err, ErrInteractionAcknowledged)
+	}
+
+	// copies share how the interaction was responded to
+	if err := it.WithContext(context.Background()).Respond("done"); err != nil {
+		t.Fatalf("DiscordInteraction.Respond() error = %v", err)
+	}
+	if len(sess.responses) != 1 || len(sess.edits) != 1 || *sess.edits[0].Content != "done" {
+		t.Errorf("Expected the response to be edited in, got %v responses and %v edits", len(sess.responses), len(sess.edits))
+	}
+
+	msg, err := it.FollowUp(&discordgo.WebhookParams{Content: "more"})
+	if err != nil || msg.Content != "more" {
+		t.Errorf("DiscordInteraction.FollowUp() = %v, %v", msg, err)
+	}
+}
+
+func TestDiscordInteraction_EphemeralAfterPublicDefer(t *testing.T) {
+	sess := &responseRecorder{DiscordSessionMock: mocks.NewDiscordSession("token", 1)}
+	it := &DiscordInteraction{Sess: sess, Interaction: &discordgo.Interaction{}}
+
+	if err := it.Defer(false); err != nil {
+		t.Fatalf("DiscordInteraction.Defer() error = %v", err)
+	}
+	if err := it.RespondEphemeral("secret"); err != nil {
+		t.Fatalf("DiscordInteraction.RespondEphemeral() error = %v", err)
+	}
+	if len(sess.edits) != 0 {
+		t.Errorf("Expected the public response to not be edited, got %v edits", len(sess.edits))
+	}
+	if len(sess.followUps) != 1 || sess.followUps[0].Content != "secret" || sess.followUps[0].Flags != discordgo.MessageFlagsEphemeral {
+		t.Errorf("Expected an ephemeral follow-up, got %v", sess.followUps)
+	}
+
+	// ephemeral deferrals are edited as usual
+	sess = &responseRecorder{DiscordSessionMock: mocks.NewDiscordSession("token", 1)}
+	it = &DiscordInteraction{Sess: sess, Interaction: &discordgo.Interaction{}}
+	_ = it.Defer(true)
+	_ = it.RespondEphemeral("secret")
+	if len(sess.edits) != 1 || len(sess.followUps) != 0 {
+		t.Errorf("Expected the ephemeral response to be edited, got %v edits and %v follow-ups", len(sess.edits), len(sess.followUps))
+	}
+}
+
+func TestDiscordInteraction_RespondAcknowledges(t *testing.T) {
+	sess := &responseRecorder{DiscordSessionMock: mocks.NewDiscordSession("token", 1)}
+	it := &DiscordInteraction{Sess: sess, Interaction: &discordgo.Interaction{}}
+
+	if it.Acknowledged() {
+		t.Errorf("Expected a new interaction to not be acknowledged")
+	}
+	_ = it.Respond("hi")
+	if !it.Acknowledged() {
+		t.Errorf("Expected the interaction to be acknowledged after responding")
+	}
+	if err := it.Defer(false); err != ErrInteractionAcknowledged {
+		t.Errorf("DiscordInteraction.Defer() error = %v, want %v", err, ErrInteractionAcknowledged)
+	}
+	if err := it.Respond("again"); err != ErrInteractionAcknowledged {
+		t.Errorf("DiscordInteraction.Respond() error = %v, want %v", err, ErrInteractionAcknowledged)
+	}
+	if _, err := it.EditOriginal(&discordgo.WebhookEdit{}); err != nil {
+		t.Errorf("DiscordInteraction.EditOriginal() error = %v", err)
+	}
+}
+
+func TestDiscordSubcommand(t *testing.T) {
+	d := &DiscordApplicationCommand{Data: discordgo.ApplicationCommandInteractionData{
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+			Name: "role",
+			Type: discordgo.ApplicationCommandOptionSubCommandGroup,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+				Name: "edit",
+				Type: discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "role", Type: discordgo.ApplicationCommandOptionRole, Value: "5"},
+					{Name: "amount", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(3)},
+				},
+			}},
+		}},
+		Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+			Roles: map[string]*discordgo.Role{"5": {ID: "5", Name: "mods"}},
+		},
+	}}
+
+	path := d.SubcommandPath()
+	if path != "role:edit" {
+		t.Fatalf("DiscordApplicationCommand.SubcommandPath() = %v, want role:edit", path)
+	}
+	sub := &DiscordSubcommand{DiscordApplicationCommand: d, Path: path}
+	if role, ok := sub.Role("role"); !ok || role.Name != "mods" {
+		t.Errorf("DiscordSubcommand.Role() = %v, %v", role, ok)
+	}
+	if n, ok := sub.Int("amount"); !ok || n != 3 {
+		t.Errorf("DiscordSubcommand.Int() = %v, %v", n, ok)
+	}
+	if _, ok := sub.String("amount"); ok {
+		t.Errorf("DiscordSubcommand.String() should not return options of another type")
+	}
+	if _, ok := sub.User("user"); ok {
+		t.Errorf("DiscordSubcommand.User() should not return missing options")
+	}
+
+	plain := &DiscordApplicationCommand{}
+	if path := plain.SubcommandPath(); path != "" {
+		t.Errorf("DiscordApplicationCommand.SubcommandPath() = %v, want empty", path)
+	}
+}
@@ -1,6 +1,7 @@
 package discord
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -80,6 +81,28 @@ type DiscordMessage struct {
 	MessageType  MessageType
 	TimeReceived time.Time
 	Shard        int
+
+	// Prefix is the command prefix the message was invoked with, exactly as
+	// it appears in the content. It is empty if the message has no prefix.
+	Prefix string
+
+	ctx context.Context
+}
+
+// Context returns the context of the message. Handlers get a context that is
+// cancelled on shutdown or when their deadline passes. It is never nil.
+func (m *DiscordMessage) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// WithContext returns a shallow copy of the message with its context set to ctx.
+func (m *DiscordMessage) WithContext(ctx context.Context) *DiscordMessage {
+	m2 := *m
+	m2.ctx = ctx
+	return &m2
 }
 
 // Reply replies directly to a DiscordMessage
@@ -138,14 +161,25 @@ func (m *DiscordMessage) Delete() error {
 	return m.Sess.ChannelMessageDelete(m.ChannelID(), m.ID())
 }
 
-// Args returns the split content of a DiscordMessage in lowercase.
+// Args returns the split content of a DiscordMessage in lowercase. The
+// command prefix, if any, is not included.
 func (m *DiscordMessage) Args() []string {
-	return strings.Fields(strings.ToLower(m.Message.Content))
+	return strings.Fields(strings.ToLower(m.CommandContent()))
 }
 
-// RawArgs returns the raw split content of a DiscordMessage.
+// RawArgs returns the raw split content of a DiscordMessage. The command
+// prefix, if any, is not included.
 func (m *DiscordMessage) RawArgs() []string {
-	return strings.Fields(m.Message.Content)
+	return strings.Fields(m.CommandContent())
+}
+
+// CommandContent returns the raw content of a DiscordMessage with the
+// command prefix trimmed off.
+func (m *DiscordMessage) CommandContent() string {
+	if m.Prefix == "" || !strings.HasPrefix(m.Message.Content, m.Prefix) {
+		return m.Message.Content
+	}
+	return strings.TrimSpace(m.Message.Content[len(m.Prefix):])
 }
 
 // RawContent returns the raw content of a DiscordMessage.
@@ -30,6 +30,28 @@ func TestDiscordMessage_RawArgs(t *testing.T) {
 	}
 }
 
+func TestDiscordMessage_CommandContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		prefix  string
+		want    string
+	}{
+		{"no prefix", "I am a message", "", "I am a message"},
+		{"prefix", "m?ping now", "m?", "ping now"},
+		{"prefix with space", "m? ping now", "m?", "ping now"},
+		{"mention", "<@1> ping now", "<@1>", "ping now"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &DiscordMessage{Message: &discordgo.Message{Content: tt.content}, Prefix: tt.prefix}
+			if got := msg.CommandContent(); got != tt.want {
+				t.Errorf("DiscordMessage.CommandContent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDiscordMessage_RawContent(t *testing.T) {
 	msg := &DiscordMessage{Message: &discordgo.Message{Content: "I am a message"}}
 	expected := "I am a message"
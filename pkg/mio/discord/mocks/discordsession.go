@@ -24,6 +24,10 @@ type DiscordSessionMock struct {
 	handlers     map[string][]interface{}
 	onceHandlers map[string][]interface{}
 
+	commandsMu sync.Mutex
+	commands   map[string][]*discordgo.ApplicationCommand
+	commandID  int
+
 	IsOpen          bool
 	CloseShouldFail bool
 }
@@ -37,6 +41,7 @@ func NewDiscordSession(token string, shards int) *DiscordSessionMock {
 		state:        discordgo.NewState(),
 		handlers:     make(map[string][]interface{}, 0),
 		onceHandlers: make(map[string][]interface{}, 0),
+		commands:     make(map[string][]*discordgo.ApplicationCommand),
 	}
 	return s
 }
@@ -107,12 +112,84 @@ func (s *DiscordSessionMock) AddHandlerOnce(handler interface{}) func() {
 
 func (s *DiscordSessionMock) ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) (createdCommands []*discordgo.ApplicationCommand, err error) {
 	for _, c := range commands {
-		if strings.ToLower(c.Name) != c.Name {
-			return nil, errors.New("lower case name")
+		if err := validateCommand(c); err != nil {
+			return nil, err
+		}
+	}
+	s.commandsMu.Lock()
+	defer s.commandsMu.Unlock()
+	s.commands[guildID] = nil
+	for _, c := range commands {
+		s.commands[guildID] = append(s.commands[guildID], s.storeCommand(appID, guildID, "", c))
+	}
+	return s.commands[guildID], nil
+}
+
+func (s *DiscordSessionMock) ApplicationCommandCreate(appID string, guildID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (ccmd *discordgo.ApplicationCommand, err error) {
+	if err := validateCommand(cmd); err != nil {
+		return nil, err
+	}
+	s.commandsMu.Lock()
+	defer s.commandsMu.Unlock()
+	created := s.storeCommand(appID, guildID, "", cmd)
+	s.commands[guildID] = append(s.commands[guildID], created)
+	return created, nil
+}
+
+func (s *DiscordSessionMock) ApplicationCommandDelete(appID, guildID, cmdID string, options ...discordgo.RequestOption) error {
+	s.commandsMu.Lock()
+	defer s.commandsMu.Unlock()
+	for i, c := range s.commands[guildID] {
+		if c.ID == cmdID {
+			s.commands[guildID] = append(s.commands[guildID][:i], s.commands[guildID][i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("unknown application command")
+}
+
+func (s *DiscordSessionMock) ApplicationCommandEdit(appID, guildID, cmdID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (updated *discordgo.ApplicationCommand, err error) {
+	if err := validateCommand(cmd); err != nil {
+		return nil, err
+	}
+	s.commandsMu.Lock()
+	defer s.commandsMu.Unlock()
+	for i, c := range s.commands[guildID] {
+		if c.ID == cmdID {
+			s.commands[guildID][i] = s.storeCommand(appID, guildID, cmdID, cmd)
+			return s.commands[guildID][i], nil
 		}
-		// can check options too, but im lazy
 	}
-	return commands, nil
+	return nil, errors.New("unknown application command")
+}
+
+func (s *DiscordSessionMock) ApplicationCommands(appID, guildID string, options ...discordgo.RequestOption) (cmd []*discordgo.ApplicationCommand, err error) {
+	s.commandsMu.Lock()
+	defer s.commandsMu.Unlock()
+	return append([]*discordgo.ApplicationCommand(nil), s.commands[guildID]...), nil
+}
+
+// validateCommand does some of the validation Discord does on commands.
+func validateCommand(c *discordgo.ApplicationCommand) error {
+	isChat := c.Type == 0 || c.Type == discordgo.ChatApplicationCommand
+	if isChat && strings.ToLower(c.Name) != c.Name {
+		return errors.New("lower case name")
+	}
+	// can check options too, but im lazy
+	return nil
+}
+
+// storeCommand returns a copy of c as Discord would store it. s.commandsMu must be held.
+func (s *DiscordSessionMock) storeCommand(appID, guildID, id string, c *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+	if id == "" {
+		s.commandID++
+		id = fmt.Sprint(s.commandID)
+	}
+	stored := *c
+	stored.ID = id
+	stored.ApplicationID = appID
+	stored.GuildID = guildID
+	return &stored
 }
 
 func (s *DiscordSessionMock) Channel(channelID string, options ...discordgo.RequestOption) (st *discordgo.Channel, err error) {
@@ -324,5 +401,24 @@ func (s *DiscordSessionMock) UpdateStatusComplex(usd discordgo.UpdateStatusData)
 }
 
 func (s *DiscordSessionMock) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	if !s.IsOpen {
+		return errors.New("session is closed")
+	}
+	return nil
+}
+
+func (s *DiscordSessionMock) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	panic("not implemented")
+}
+
+func (s *DiscordSessionMock) FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	panic("not implemented")
+}
+
+func (s *DiscordSessionMock) FollowupMessageEdit(interaction *discordgo.Interaction, messageID string, data *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	panic("not implemented")
+}
+
+func (s *DiscordSessionMock) FollowupMessageDelete(interaction *discordgo.Interaction, messageID string, options ...discordgo.RequestOption) error {
 	panic("not implemented")
 }
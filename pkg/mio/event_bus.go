@@ -1,99 +1,334 @@
 package mio
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"runtime/debug"
+	"slices"
+	"sort"
 	"sync"
 )
 
+// EventBus delivers events to the handlers subscribed to their type. Event types
+// are told apart by their full type identity, so two packages can both have a
+// Ready event.
 type EventBus struct {
 	lock     sync.Mutex
-	handlers map[string][]*eventHandler
+	handlers map[reflect.Type][]*eventHandler
+	// delivery holds the handlers an event of each type is delivered to, which are
+	// its own handlers and the ones subscribed to any, sorted by priority. It is
+	// kept up to date as handlers come and go, so publish does not have to merge.
+	delivery map[reflect.Type][]*eventHandler
+	logger   Logger
+
+	// pending is the amount of handler calls that have not returned yet, and
+	// idle is closed once it reaches 0.
+	pending int
+	idle    chan struct{}
+}
+
+// DeliveryMode decides how a handler is called when an event is published.
+type DeliveryMode int
+
+const (
+	// DeliverAsync calls the handler in a new goroutine for every event.
+	DeliverAsync DeliveryMode = iota
+	// DeliverSync calls the handler in the goroutine that publishes the event,
+	// before Publish returns.
+	DeliverSync
+	// DeliverOrdered calls the handler in a goroutine of its own, one event at a
+	// time, in the order the events were published.
+	DeliverOrdered
+)
+
+// HandlerOption configures a handler when it is subscribed.
+type HandlerOption func(*eventHandler)
+
+// Once removes the handler after the first event it receives.
+func Once() HandlerOption {
+	return func(h *eventHandler) { h.once = true }
+}
+
+// Sync makes the handler use DeliverSync.
+func Sync() HandlerOption {
+	return func(h *eventHandler) { h.mode = DeliverSync }
+}
+
+// Ordered makes the handler use DeliverOrdered.
+func Ordered() HandlerOption {
+	return func(h *eventHandler) { h.mode = DeliverOrdered }
+}
+
+// Priority sets the priority of the handler. Handlers with a higher priority are
+// called first, and handlers with the same priority in the order they were added.
+// Only synchronous handlers are guaranteed to also finish in that order.
+func Priority(p int) HandlerOption {
+	return func(h *eventHandler) { h.priority = p }
 }
 
 type eventHandler struct {
-	callback reflect.Value
+	call     func(event any)
 	once     bool
+	mode     DeliveryMode
+	priority int
+
+	// queue holds the events an ordered handler has not handled yet, and draining
+	// is true while a goroutine works through it. Both are guarded by the bus lock.
+	queue    []any
+	draining bool
 }
 
-func NewEventBus() *EventBus {
+func NewEventBus(logger Logger) *EventBus {
 	return &EventBus{
-		handlers: make(map[string][]*eventHandler),
+		handlers: make(map[reflect.Type][]*eventHandler),
+		delivery: make(map[reflect.Type][]*eventHandler),
+		logger:   logger,
 	}
 }
 
-func (eb *EventBus) removeHandler(eventType string, handler *eventHandler) {
-	eb.lock.Lock()
-	defer eb.lock.Unlock()
+// allEvents is the type of handlers that receive every event.
+var allEvents = reflect.TypeFor[any]()
 
-	handlers := eb.handlers[eventType]
-	for i, h := range handlers {
-		if h == handler {
-			eb.handlers[eventType] = append(handlers[:i], handlers[i+1:]...)
-			return
-		}
-	}
+// Subscribe adds a handler for events of type T, and returns a function that
+// removes it again. Handlers subscribed to any receive every event, which is
+// how sinks can forward everything that happens on the bus.
+func Subscribe[T any](eb *EventBus, handler func(T), opts ...HandlerOption) func() {
+	return eb.subscribe(reflect.TypeFor[T](), func(event any) { handler(event.(T)) }, opts)
 }
 
-func (eb *EventBus) AddHandler(handler any) func() {
-	eb.lock.Lock()
-	defer eb.lock.Unlock()
+// Publish delivers event to the handlers subscribed to T.
+func Publish[T any](eb *EventBus, event T) {
+	eb.publish(reflect.TypeFor[T](), event)
+}
 
+// AddHandler adds handler, which must be a function that takes the event as its
+// only argument, and returns a function that removes it again. Subscribe should be
+// preferred, as it is checked at compile time.
+func (eb *EventBus) AddHandler(handler any, opts ...HandlerOption) func() {
 	handlerValue := reflect.ValueOf(handler)
 	handlerType := handlerValue.Type()
 	if handlerType.Kind() != reflect.Func || handlerType.NumIn() != 1 || handlerType.NumOut() != 0 {
 		panic("handler must be a function that takes exactly one argument and returns nothing")
 	}
 
-	eventType := handlerType.In(0).Elem().Name()
-	if _, ok := eb.handlers[eventType]; !ok {
-		eb.handlers[eventType] = make([]*eventHandler, 0)
+	call := func(event any) {
+		handlerValue.Call([]reflect.Value{reflect.ValueOf(event)})
 	}
+	return eb.subscribe(handlerType.In(0), call, opts)
+}
+
+// AddOnceHandler is like AddHandler, but removes the handler after the first
+// event it receives.
+func (eb *EventBus) AddOnceHandler(handler any, opts ...HandlerOption) func() {
+	return eb.AddHandler(handler, append(opts, Once())...)
+}
 
-	eh := &eventHandler{
-		callback: handlerValue,
-		once:     false,
+// Emit delivers event to the handlers of its dynamic type.
+func (eb *EventBus) Emit(event any) {
+	eb.publish(reflect.TypeOf(event), event)
+}
+
+func (eb *EventBus) subscribe(eventType reflect.Type, call func(any), opts []HandlerOption) func() {
+	eh := &eventHandler{call: call}
+	for _, opt := range opts {
+		opt(eh)
 	}
-	eb.handlers[eventType] = append(eb.handlers[eventType], eh)
+
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+	// the slices are never changed in place, as publish uses them without the lock
+	handlers := make([]*eventHandler, 0, len(eb.handlers[eventType])+1)
+	handlers = append(handlers, eb.handlers[eventType]...)
+	handlers = append(handlers, eh)
+	sortHandlers(handlers)
+	eb.handlers[eventType] = handlers
+	eb.updateDelivery(eventType)
 
 	return func() {
 		eb.removeHandler(eventType, eh)
 	}
 }
 
-func (eb *EventBus) AddOnceHandler(handler any) func() {
+func (eb *EventBus) removeHandler(eventType reflect.Type, handler *eventHandler) {
 	eb.lock.Lock()
 	defer eb.lock.Unlock()
+	eb.removeHandlers(eventType, func(h *eventHandler) bool { return h == handler })
+}
 
-	handlerValue := reflect.ValueOf(handler)
-	handlerType := handlerValue.Type()
-	if handlerType.Kind() != reflect.Func || handlerType.NumIn() != 1 || handlerType.NumOut() != 0 {
-		panic("handler must be a function that takes exactly one argument and returns nothing")
+// removeHandlers removes the handlers of eventType that match. The bus lock must be held.
+func (eb *EventBus) removeHandlers(eventType reflect.Type, match func(h *eventHandler) bool) {
+	handlers := eb.handlers[eventType]
+	if !slices.ContainsFunc(handlers, match) {
+		return
 	}
-
-	eventType := handlerType.In(0).Elem().Name()
-	if _, ok := eb.handlers[eventType]; !ok {
-		eb.handlers[eventType] = make([]*eventHandler, 0)
+	remaining := make([]*eventHandler, 0, len(handlers))
+	for _, h := range handlers {
+		if !match(h) {
+			remaining = append(remaining, h)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(eb.handlers, eventType)
+	} else {
+		eb.handlers[eventType] = remaining
 	}
+	eb.updateDelivery(eventType)
+}
 
-	eh := &eventHandler{
-		callback: handlerValue,
-		once:     true,
+// updateDelivery updates the delivery lists after the handlers of eventType
+// changed. As handlers subscribed to any receive every event, a change to them
+// updates every list. The bus lock must be held.
+func (eb *EventBus) updateDelivery(eventType reflect.Type) {
+	if eventType != allEvents {
+		eb.mergeDelivery(eventType)
+		return
+	}
+	for t := range eb.handlers {
+		if t != allEvents {
+			eb.mergeDelivery(t)
+		}
 	}
-	eb.handlers[eventType] = append(eb.handlers[eventType], eh)
+}
 
-	return func() {
-		eb.removeHandler(eventType, eh)
+// mergeDelivery sets the delivery list of eventType. Events of types without one
+// go to the handlers subscribed to any. The bus lock must be held.
+func (eb *EventBus) mergeDelivery(eventType reflect.Type) {
+	handlers, all := eb.handlers[eventType], eb.handlers[allEvents]
+	switch {
+	case len(handlers) == 0:
+		delete(eb.delivery, eventType)
+	case len(all) == 0:
+		eb.delivery[eventType] = handlers
+	default:
+		merged := make([]*eventHandler, 0, len(handlers)+len(all))
+		merged = append(append(merged, handlers...), all...)
+		sortHandlers(merged)
+		eb.delivery[eventType] = merged
 	}
 }
 
-func (eb *EventBus) Emit(event any) {
-	eventType := reflect.TypeOf(event).Elem().Name()
-	if handlers, ok := eb.handlers[eventType]; ok {
-		for _, handler := range handlers {
-			if handler.once {
-				eb.removeHandler(eventType, handler)
+// sortHandlers sorts handlers by priority, keeping the order they were added in
+// for handlers with the same priority.
+func sortHandlers(handlers []*eventHandler) {
+	sort.SliceStable(handlers, func(i, j int) bool {
+		return handlers[i].priority > handlers[j].priority
+	})
+}
+
+func (eb *EventBus) publish(eventType reflect.Type, event any) {
+	eb.lock.Lock()
+	handlers, ok := eb.delivery[eventType]
+	if !ok {
+		handlers = eb.handlers[allEvents]
+	}
+	if len(handlers) == 0 {
+		eb.lock.Unlock()
+		return
+	}
+	once := func(h *eventHandler) bool { return h.once }
+	eb.removeHandlers(eventType, once)
+	if eventType != allEvents {
+		eb.removeHandlers(allEvents, once)
+	}
+
+	var drain []*eventHandler
+	for _, h := range handlers {
+		switch h.mode {
+		case DeliverAsync:
+			eb.addPending()
+		case DeliverOrdered:
+			eb.addPending()
+			h.queue = append(h.queue, event)
+			if !h.draining {
+				h.draining = true
+				drain = append(drain, h)
 			}
-			go handler.callback.Call([]reflect.Value{reflect.ValueOf(event)})
+		}
+	}
+	eb.lock.Unlock()
+
+	for _, h := range handlers {
+		switch h.mode {
+		case DeliverSync:
+			eb.call(h, event)
+		case DeliverAsync:
+			go func(h *eventHandler) {
+				defer eb.handlerDone()
+				eb.call(h, event)
+			}(h)
+		}
+	}
+	for _, h := range drain {
+		go eb.drain(h)
+	}
+}
+
+// drain calls an ordered handler with its queued events until there are none left.
+func (eb *EventBus) drain(h *eventHandler) {
+	for {
+		eb.lock.Lock()
+		if len(h.queue) == 0 {
+			h.draining = false
+			eb.lock.Unlock()
+			return
+		}
+		event := h.queue[0]
+		h.queue[0] = nil
+		h.queue = h.queue[1:]
+		eb.lock.Unlock()
+
+		eb.call(h, event)
+		eb.handlerDone()
+	}
+}
+
+// call calls a handler, and logs it if the handler panics.
+func (eb *EventBus) call(h *eventHandler, event any) {
+	defer func() {
+		if r := recover(); r != nil && eb.logger != nil {
+			eb.logger.Error("Event handler panicked",
+				"event", fmt.Sprintf("%T", event),
+				"error", r,
+				"stack trace", string(debug.Stack()),
+			)
+		}
+	}()
+	h.call(event)
+}
+
+// addPending registers a handler call that has not returned yet. The bus lock must be held.
+func (eb *EventBus) addPending() {
+	if eb.pending == 0 {
+		eb.idle = make(chan struct{})
+	}
+	eb.pending++
+}
+
+func (eb *EventBus) handlerDone() {
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+	eb.pending--
+	if eb.pending == 0 {
+		close(eb.idle)
+	}
+}
+
+// Wait blocks until every emitted event has been handled, or until ctx is done.
+func (eb *EventBus) Wait(ctx context.Context) error {
+	for {
+		eb.lock.Lock()
+		if eb.pending == 0 {
+			eb.lock.Unlock()
+			return nil
+		}
+		idle := eb.idle
+		eb.lock.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 }
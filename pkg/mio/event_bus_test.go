@@ -1,8 +1,13 @@
 package mio
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,17 +21,17 @@ type testEvent struct {
 func testEventHandler(event *testEvent) {}
 
 func TestNewEventBus(t *testing.T) {
-	bus := NewEventBus()
+	bus := NewEventBus(NewDiscardLogger())
 	assert.NotNil(t, bus)
 	assert.IsType(t, &EventBus{}, bus)
 }
 
 func TestAddHandler(t *testing.T) {
-	bus := NewEventBus()
+	bus := NewEventBus(NewDiscardLogger())
 	removeFunc := bus.AddHandler(testEventHandler)
 	assert.NotNil(t, removeFunc)
 
-	eventType := reflect.TypeOf((*testEvent)(nil)).Elem().Name()
+	eventType := reflect.TypeOf((*testEvent)(nil))
 	assert.Contains(t, bus.handlers, eventType)
 	assert.Len(t, bus.handlers[eventType], 1)
 
@@ -44,11 +49,11 @@ func TestAddHandler(t *testing.T) {
 }
 
 func TestAddOnceHandler(t *testing.T) {
-	bus := NewEventBus()
+	bus := NewEventBus(NewDiscardLogger())
 	removeFunc := bus.AddOnceHandler(testEventHandler)
 	assert.NotNil(t, removeFunc)
 
-	eventType := reflect.TypeOf((*testEvent)(nil)).Elem().Name()
+	eventType := reflect.TypeOf((*testEvent)(nil))
 	assert.Contains(t, bus.handlers, eventType)
 	assert.Len(t, bus.handlers[eventType], 1)
 	assert.True(t, bus.handlers[eventType][0].once)
@@ -68,7 +73,7 @@ func TestAddOnceHandler(t *testing.T) {
 
 func TestEmit(t *testing.T) {
 	t.Run("Single handler", func(t *testing.T) {
-		bus := NewEventBus()
+		bus := NewEventBus(NewDiscardLogger())
 		called := sync.WaitGroup{}
 		called.Add(1)
 		val := 0
@@ -84,28 +89,28 @@ func TestEmit(t *testing.T) {
 	})
 
 	t.Run("Multiple handlers", func(t *testing.T) {
-		bus := NewEventBus()
+		bus := NewEventBus(NewDiscardLogger())
 		called := sync.WaitGroup{}
 		called.Add(2)
-		val := 0
+		var val atomic.Int64
 
 		bus.AddHandler(func(e *testEvent) {
-			val += e.Value
+			val.Add(int64(e.Value))
 			called.Done()
 		})
 
 		bus.AddHandler(func(e *testEvent) {
-			val += e.Value
+			val.Add(int64(e.Value))
 			called.Done()
 		})
 
 		bus.Emit(&testEvent{Value: 1})
 		called.Wait()
-		assert.Equal(t, val, 2)
+		assert.Equal(t, val.Load(), int64(2))
 	})
 
 	t.Run("Once handler", func(t *testing.T) {
-		bus := NewEventBus()
+		bus := NewEventBus(NewDiscardLogger())
 		called := sync.WaitGroup{}
 		called.Add(1)
 		val := 0
@@ -124,7 +129,7 @@ func TestEmit(t *testing.T) {
 
 func TestRemoveHandler(t *testing.T) {
 	t.Run("Remove handler", func(t *testing.T) {
-		bus := NewEventBus()
+		bus := NewEventBus(NewDiscardLogger())
 		called := sync.WaitGroup{}
 		called.Add(1)
 		val := 0
@@ -152,7 +157,7 @@ func TestRemoveHandler(t *testing.T) {
 	})
 
 	t.Run("Remove once handler", func(t *testing.T) {
-		bus := NewEventBus()
+		bus := NewEventBus(NewDiscardLogger())
 		called := sync.WaitGroup{}
 		called.Add(1)
 		val := 0
@@ -179,3 +184,132 @@ func TestRemoveHandler(t *testing.T) {
 		}
 	})
 }
+
+func TestEventBus_Wait(t *testing.T) {
+	bus := NewEventBus(NewDiscardLogger())
+	release := make(chan struct{})
+	bus.AddHandler(func(e *testEvent) {
+		<-release
+	})
+	bus.Emit(&testEvent{Value: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	assert.ErrorIs(t, bus.Wait(ctx), context.DeadlineExceeded, "Wait should time out while a handler is running")
+
+	close(release)
+	assert.NoError(t, bus.Wait(context.Background()))
+}
+
+// outerTestEvent is the package level testEvent, for tests that shadow it.
+type outerTestEvent = testEvent
+
+func TestEventBus_TypeIdentity(t *testing.T) {
+	// testEvent in here has the same name as the package level one
+	type testEvent struct {
+		Value int
+	}
+	bus := NewEventBus(NewDiscardLogger())
+	called, otherCalled := 0, 0
+	bus.AddHandler(func(e *testEvent) { called++ }, Sync())
+	Subscribe(bus, func(e *outerTestEvent) { otherCalled++ }, Sync())
+
+	bus.Emit(&testEvent{Value: 1})
+	Publish(bus, &testEvent{Value: 1})
+	assert.Equal(t, 2, called)
+	assert.Equal(t, 0, otherCalled, "Events with the same name from somewhere else should not be delivered")
+}
+
+func TestSubscribe(t *testing.T) {
+	bus := NewEventBus(NewDiscardLogger())
+	var got []int
+	remove := Subscribe(bus, func(e *testEvent) { got = append(got, e.Value) }, Sync())
+	Subscribe(bus, func(e testEvent) { got = append(got, -e.Value) }, Sync())
+
+	Publish(bus, &testEvent{Value: 1})
+	bus.Emit(&testEvent{Value: 2})
+	Publish(bus, testEvent{Value: 3})
+	remove()
+	Publish(bus, &testEvent{Value: 4})
+	assert.Equal(t, []int{1, 2, -3}, got)
+}
+
+func TestPriority(t *testing.T) {
+	bus := NewEventBus(NewDiscardLogger())
+	var got []string
+	Subscribe(bus, func(e *testEvent) { got = append(got, "low") }, Sync(), Priority(-1))
+	Subscribe(bus, func(e *testEvent) { got = append(got, "first") }, Sync())
+	Subscribe(bus, func(e *testEvent) { got = append(got, "high") }, Sync(), Priority(10))
+	Subscribe(bus, func(e *testEvent) { got = append(got, "second") }, Sync())
+
+	Publish(bus, &testEvent{})
+	assert.Equal(t, []string{"high", "first", "second", "low"}, got)
+}
+
+func TestOrdered(t *testing.T) {
+	bus := NewEventBus(NewDiscardLogger())
+	var got []int
+	Subscribe(bus, func(e *testEvent) {
+		time.Sleep(time.Microsecond * time.Duration(100-e.Value))
+		got = append(got, e.Value)
+	}, Ordered())
+
+	want := make([]int, 100)
+	for i := range want {
+		want[i] = i
+		Publish(bus, &testEvent{Value: i})
+	}
+	assert.NoError(t, bus.Wait(context.Background()))
+	assert.Equal(t, want, got)
+}
+
+func TestEventBus_Panic(t *testing.T) {
+	buf := new(bytes.Buffer)
+	bus := NewEventBus(NewLogger(buf))
+	called := false
+	Subscribe(bus, func(e *testEvent) { panic("oh no") }, Sync(), Priority(1))
+	Subscribe(bus, func(e *testEvent) { called = true }, Sync())
+	Subscribe(bus, func(e *testEvent) { panic("oh no") }, Ordered())
+
+	assert.NotPanics(t, func() { Publish(bus, &testEvent{}) })
+	assert.True(t, called, "Handlers after a panicking handler should still be called")
+	assert.NoError(t, bus.Wait(context.Background()))
+	assert.Equal(t, 2, strings.Count(buf.String(), "Event handler panicked"))
+	assert.Contains(t, buf.String(), "*mio.testEvent")
+}
+
+func TestSubscribe_All(t *testing.T) {
+	bus := NewEventBus(NewDiscardLogger())
+	var got []string
+	Subscribe(bus, func(e any) { got = append(got, fmt.Sprintf("all %T", e)) }, Sync())
+	Subscribe(bus, func(e *testEvent) { got = append(got, "testEvent") }, Sync(), Priority(1))
+
+	Publish(bus, &testEvent{})
+	bus.Emit(&outerTestEvent{})
+	Publish(bus, "hello")
+	assert.Equal(t, []string{"testEvent", "all *mio.testEvent", "testEvent", "all *mio.testEvent", "all string"}, got)
+}
+
+func TestSubscribe_AllChanges(t *testing.T) {
+	bus := NewEventBus(NewDiscardLogger())
+	var got []string
+	Subscribe(bus, func(e *testEvent) { got = append(got, "testEvent") }, Sync())
+	remove := Subscribe(bus, func(e any) { got = append(got, "all") }, Sync(), Priority(1))
+	Subscribe(bus, func(e any) { got = append(got, "once") }, Sync(), Once())
+
+	Publish(bus, &testEvent{})
+	Publish(bus, &testEvent{})
+	remove()
+	Publish(bus, &testEvent{})
+	assert.Equal(t, []string{"all", "testEvent", "once", "all", "testEvent", "testEvent"}, got)
+}
+
+func TestPublish_NoMerge(t *testing.T) {
+	bus := NewEventBus(NewDiscardLogger())
+	Subscribe(bus, func(e *testEvent) {}, Sync())
+	Subscribe(bus, func(e any) {}, Sync())
+
+	evt := &testEvent{}
+	allocs := testing.AllocsPerRun(100, func() { Publish(bus, evt) })
+	assert.Zero(t, allocs, "publish should not merge the handler lists")
+}
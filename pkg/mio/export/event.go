@@ -0,0 +1,156 @@
+// Package export ships the events a bot publishes on its EventBus to places
+// outside of the bot, like a JSON lines file or an HTTP webhook.
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+)
+
+// SchemaVersion is the version of Event. It changes when fields are changed or
+// removed, but not when fields are added.
+const SchemaVersion = 1
+
+// Event is the schema bot events are exported with.
+type Event struct {
+	Version int       `json:"version"`
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+
+	GuildID   string `json:"guild_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+
+	// Module and Handler are the module and name of the command, passive or
+	// interaction handler the event is about.
+	Module  string `json:"module,omitempty"`
+	Handler string `json:"handler,omitempty"`
+
+	// LatencyMS is how long after the message or interaction was received the
+	// event happened, in milliseconds.
+	LatencyMS int64 `json:"latency_ms,omitempty"`
+	// TimeoutMS is the timeout a handler exceeded, in milliseconds.
+	TimeoutMS int64 `json:"timeout_ms,omitempty"`
+
+	// ErrorKind is set for failed commands, and Error for failed and panicked ones.
+	ErrorKind string `json:"error_kind,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// FromBotEvent converts an event published by a bot.Bot. It returns false for
+// events that are not exported.
+func FromBotEvent(evt any) (*Event, bool) {
+	var e *Event
+	switch evt := evt.(type) {
+	case *bot.CommandRan:
+		e = newEvent(bot.BotEventCommandRan)
+		e.setMessage(evt.Message)
+		e.setHandler(evt.Command.Mod, evt.Command.Name)
+	case *bot.CommandPanicked:
+		e = newEvent(bot.BotEventCommandPanicked)
+		e.setMessage(evt.Message)
+		e.setHandler(evt.Command.Mod, evt.Command.Name)
+		e.Error = fmt.Sprint(evt.Reason)
+	case *bot.PassiveRan:
+		e = newEvent(bot.BotEventPassiveRan)
+		e.setMessage(evt.Message)
+		e.setHandler(evt.Passive.Mod, evt.Passive.Name)
+	case *bot.PassivePanicked:
+		e = newEvent(bot.BotEventPassivePanicked)
+		e.setMessage(evt.Message)
+		e.setHandler(evt.Passive.Mod, evt.Passive.Name)
+		e.Error = fmt.Sprint(evt.Reason)
+	case *bot.ApplicationCommandRan:
+		e = newEvent(bot.BotEventApplicationCommandRan)
+		e.setInteraction(evt.Interaction.DiscordInteraction)
+		e.setHandler(evt.ApplicationCommand.Mod, evt.ApplicationCommand.Name)
+	case *bot.ApplicationCommandPanicked:
+		e = newEvent(bot.BotEventApplicationCommandPanicked)
+		e.setInteraction(evt.Interaction.DiscordInteraction)
+		e.setHandler(evt.ApplicationCommand.Mod, evt.ApplicationCommand.Name)
+		e.Error = fmt.Sprint(evt.Reason)
+	case *bot.MessageComponentRan:
+		e = newEvent(bot.BotEventMessageComponentRan)
+		e.setInteraction(evt.Interaction.DiscordInteraction)
+		e.setHandler(evt.MessageComponent.Mod, evt.MessageComponent.Name)
+	case *bot.MessageComponentPanicked:
+		e = newEvent(bot.BotEventMessageComponentPanicked)
+		e.setInteraction(evt.Interaction.DiscordInteraction)
+		e.setHandler(evt.MessageComponent.Mod, evt.MessageComponent.Name)
+		e.Error = fmt.Sprint(evt.Reason)
+	case *bot.ModalSubmitRan:
+		e = newEvent(bot.BotEventModalSubmitRan)
+		e.setInteraction(evt.Interaction.DiscordInteraction)
+		e.setHandler(evt.ModalSubmit.Mod, evt.ModalSubmit.Name)
+	case *bot.ModalSubmitPanicked:
+		e = newEvent(bot.BotEventModalSubmitPanicked)
+		e.setInteraction(evt.Interaction.DiscordInteraction)
+		e.setHandler(evt.ModalSubmit.Mod, evt.ModalSubmit.Name)
+		e.Error = fmt.Sprint(evt.Reason)
+	case *bot.CommandTimedOut:
+		e = newEvent(bot.BotEventCommandTimedOut)
+		e.setInvocation(evt.Invocation)
+		e.TimeoutMS = evt.Timeout.Milliseconds()
+	case *bot.CommandFailed:
+		e = newEvent(bot.BotEventCommandFailed)
+		e.setInvocation(evt.Invocation)
+		e.ErrorKind = evt.Err.Kind.String()
+		e.Error = evt.Err.Error()
+	case *bot.MessageProcessed:
+		e = newEvent(bot.BotEventMessageProcessed)
+	case *bot.InteractionProcessed:
+		e = newEvent(bot.BotEventInteractionProcessed)
+	default:
+		return nil, false
+	}
+	return e, true
+}
+
+func newEvent(t bot.BotEvent) *Event {
+	return &Event{Version: SchemaVersion, Type: t.String(), Time: time.Now().UTC()}
+}
+
+func (e *Event) setMessage(msg *discord.DiscordMessage) {
+	if msg == nil || msg.Message == nil {
+		return
+	}
+	e.GuildID = msg.GuildID()
+	e.ChannelID = msg.ChannelID()
+	e.UserID = msg.AuthorID()
+	e.setLatency(msg.TimeReceived)
+}
+
+func (e *Event) setInteraction(it *discord.DiscordInteraction) {
+	if it == nil || it.Interaction == nil {
+		return
+	}
+	e.GuildID = it.GuildID()
+	e.ChannelID = it.ChannelID()
+	e.UserID = it.AuthorID()
+	e.setLatency(it.TimeReceived)
+}
+
+func (e *Event) setInvocation(inv *bot.Invocation) {
+	if inv.Message != nil {
+		e.setMessage(inv.Message)
+	} else {
+		e.setInteraction(inv.Interaction)
+	}
+	e.setHandler(inv.Module, inv.Name)
+}
+
+func (e *Event) setHandler(mod bot.Module, name string) {
+	if mod != nil {
+		e.Module = mod.Name()
+	}
+	e.Handler = name
+}
+
+func (e *Event) setLatency(received time.Time) {
+	if !received.IsZero() {
+		e.LatencyMS = time.Since(received).Milliseconds()
+	}
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+	"github.com/intrntsrfr/meido/pkg/mio/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessage() *discord.DiscordMessage {
+	return &discord.DiscordMessage{
+		Message: &discordgo.Message{
+			ID:        "1",
+			GuildID:   "2",
+			ChannelID: "3",
+			Author:    &discordgo.User{ID: "4"},
+		},
+		TimeReceived: time.Now().Add(-time.Second),
+	}
+}
+
+func TestFromBotEvent(t *testing.T) {
+	msg := testMessage()
+	cmd := &bot.ModuleCommand{Name: "warn"}
+
+	e, ok := FromBotEvent(&bot.CommandRan{Command: cmd, Message: msg})
+	require.True(t, ok)
+	assert.Equal(t, SchemaVersion, e.Version)
+	assert.Equal(t, "command_ran", e.Type)
+	assert.Equal(t, "2", e.GuildID)
+	assert.Equal(t, "3", e.ChannelID)
+	assert.Equal(t, "4", e.UserID)
+	assert.Equal(t, "warn", e.Handler)
+	assert.GreaterOrEqual(t, e.LatencyMS, int64(1000))
+
+	e, ok = FromBotEvent(&bot.CommandPanicked{Command: cmd, Message: msg, Reason: "oh no"})
+	require.True(t, ok)
+	assert.Equal(t, "command_panicked", e.Type)
+	assert.Equal(t, "oh no", e.Error)
+
+	inv := &bot.Invocation{Type: bot.InvocationCommand, Name: "warn", Message: msg, Command: cmd}
+	e, ok = FromBotEvent(&bot.CommandFailed{Invocation: inv, Err: bot.AsCommandError(bot.NotFoundError("Could not find that user!"))})
+	require.True(t, ok)
+	assert.Equal(t, "command_failed", e.Type)
+	assert.Equal(t, "not_found", e.ErrorKind)
+	assert.Equal(t, "4", e.UserID)
+
+	e, ok = FromBotEvent(&bot.CommandTimedOut{Invocation: inv, Timeout: time.Second * 3})
+	require.True(t, ok)
+	assert.Equal(t, int64(3000), e.TimeoutMS)
+
+	e, ok = FromBotEvent(&bot.MessageProcessed{})
+	require.True(t, ok)
+	assert.Equal(t, "message_processed", e.Type)
+
+	_, ok = FromBotEvent(&discordgo.Ready{})
+	assert.False(t, ok)
+}
+
+func TestEvent_JSON(t *testing.T) {
+	e := &Event{Version: 1, Type: "message_processed", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	b, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":1,"type":"message_processed","time":"2024-01-02T03:04:05Z"}`, string(b))
+}
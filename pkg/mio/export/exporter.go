@@ -0,0 +1,211 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio"
+)
+
+const (
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = time.Second * 5
+	DefaultBufferSize    = 10000
+	DefaultMaxRetries    = 3
+	DefaultRetryBackoff  = time.Second
+	// writeTimeout is how long a single write to a sink may take.
+	writeTimeout = time.Second * 10
+)
+
+// ExporterConfig configures an Exporter. The defaults are used for values of 0.
+type ExporterConfig struct {
+	// BatchSize is the most events written to the sink at once.
+	BatchSize int
+	// FlushInterval is how long events wait for a batch to fill up before they
+	// are written anyway.
+	FlushInterval time.Duration
+	// BufferSize is how many events can wait to be written. Events are dropped
+	// while the buffer is full, so a slow sink never holds up the bot.
+	BufferSize int
+	// MaxRetries is how many times a failed batch is retried before it is
+	// dropped, waiting RetryBackoff before the first retry and twice as long
+	// before each one after. Once the exporter is closing, batches are retried
+	// without waiting.
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// ExporterStats is a snapshot of what an Exporter has done.
+type ExporterStats struct {
+	Exported int64
+	Dropped  int64
+	Failed   int64
+}
+
+// Exporter batches events and writes them to a Sink in the background.
+type Exporter struct {
+	sink   Sink
+	cfg    ExporterConfig
+	logger mio.Logger
+
+	events chan *Event
+	mu     sync.RWMutex
+	closed bool
+	// closing is closed by Close, which cuts the backoff of a failing batch short.
+	closing chan struct{}
+	done    chan struct{}
+
+	exported atomic.Int64
+	dropped  atomic.Int64
+	failed   atomic.Int64
+}
+
+// NewExporter creates an Exporter and starts writing to sink.
+func NewExporter(sink Sink, cfg ExporterConfig, logger mio.Logger) *Exporter {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+	e := &Exporter{
+		sink:    sink,
+		cfg:     cfg,
+		logger:  logger,
+		events:  make(chan *Event, cfg.BufferSize),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// Attach exports the bot events published on bus, and returns a function that
+// stops it again.
+func (e *Exporter) Attach(bus *mio.EventBus) func() {
+	// the handler only queues the event, so it can run in the publishing goroutine
+	return mio.Subscribe(bus, func(evt any) {
+		if ev, ok := FromBotEvent(evt); ok {
+			e.Export(ev)
+		}
+	}, mio.Sync())
+}
+
+// Export queues an event to be written. It returns false if the event was dropped,
+// because the buffer is full or the exporter is closed.
+func (e *Exporter) Export(evt *Event) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		e.dropped.Add(1)
+		return false
+	}
+	select {
+	case e.events <- evt:
+		return true
+	default:
+		e.dropped.Add(1)
+		return false
+	}
+}
+
+// Stats returns what the exporter has done so far.
+func (e *Exporter) Stats() ExporterStats {
+	return ExporterStats{
+		Exported: e.exported.Load(),
+		Dropped:  e.dropped.Load(),
+		Failed:   e.failed.Load(),
+	}
+}
+
+// Close writes the events that are still queued, and closes the sink.
+func (e *Exporter) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	close(e.events)
+	close(e.closing)
+	e.mu.Unlock()
+
+	<-e.done
+	return e.sink.Close()
+}
+
+func (e *Exporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Event, 0, e.cfg.BatchSize)
+	for {
+		select {
+		case evt, ok := <-e.events:
+			if !ok {
+				e.flush(batch)
+				return
+			}
+			batch = append(batch, evt)
+			if len(batch) >= e.cfg.BatchSize {
+				e.flush(batch)
+				batch = make([]*Event, 0, e.cfg.BatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				e.flush(batch)
+				batch = make([]*Event, 0, e.cfg.BatchSize)
+			}
+		}
+	}
+}
+
+// flush writes a batch to the sink, retrying with backoff if it fails.
+func (e *Exporter) flush(batch []*Event) {
+	if len(batch) == 0 {
+		return
+	}
+	backoff := e.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		err := e.sink.Write(ctx, batch)
+		cancel()
+		if err == nil {
+			e.exported.Add(int64(len(batch)))
+			return
+		}
+
+		var serr *StatusError
+		if attempt >= e.cfg.MaxRetries || (errors.As(err, &serr) && !serr.Retryable()) {
+			e.failed.Add(int64(len(batch)))
+			e.logger.Error("Could not export events", "events", len(batch), "attempts", attempt+1, "error", err)
+			return
+		}
+		e.logger.Warn("Exporting events failed, retrying", "events", len(batch), "backoff", backoff.String(), "error", err)
+		e.wait(backoff)
+		backoff *= 2
+	}
+}
+
+// wait waits for d, or until the exporter is closing.
+func (e *Exporter) wait(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-e.closing:
+	}
+}
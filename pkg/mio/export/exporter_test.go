@@ -0,0 +1,193 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/intrntsrfr/meido/pkg/mio"
+	"github.com/intrntsrfr/meido/pkg/mio/bot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	batches [][]*Event
+	fails   int
+	closed  bool
+}
+
+func (s *memorySink) Write(ctx context.Context, events []*Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("sink is down")
+	}
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestExporter_Batching(t *testing.T) {
+	sink := &memorySink{}
+	e := NewExporter(sink, ExporterConfig{BatchSize: 2, FlushInterval: time.Hour}, mio.NewDiscardLogger())
+	for i := 0; i < 5; i++ {
+		assert.True(t, e.Export(&Event{Type: "a"}))
+	}
+	require.NoError(t, e.Close())
+
+	assert.True(t, sink.closed)
+	require.Len(t, sink.batches, 3)
+	assert.Len(t, sink.batches[0], 2)
+	assert.Len(t, sink.batches[2], 1, "Close should flush what is left")
+	assert.Equal(t, int64(5), e.Stats().Exported)
+	assert.False(t, e.Export(&Event{Type: "a"}), "Events exported after Close should be dropped")
+}
+
+func TestExporter_FlushInterval(t *testing.T) {
+	sink := &memorySink{}
+	e := NewExporter(sink, ExporterConfig{FlushInterval: time.Millisecond * 10}, mio.NewDiscardLogger())
+	defer e.Close()
+	e.Export(&Event{Type: "a"})
+
+	assert.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.batches) == 1
+	}, time.Second, time.Millisecond*5)
+}
+
+func TestExporter_Retry(t *testing.T) {
+	sink := &memorySink{fails: 2}
+	e := NewExporter(sink, ExporterConfig{RetryBackoff: time.Millisecond}, mio.NewDiscardLogger())
+	e.Export(&Event{Type: "a"})
+	require.NoError(t, e.Close())
+	assert.Len(t, sink.batches, 1)
+	assert.Equal(t, ExporterStats{Exported: 1}, e.Stats())
+
+	sink = &memorySink{fails: 10}
+	e = NewExporter(sink, ExporterConfig{MaxRetries: 2, RetryBackoff: time.Millisecond}, mio.NewDiscardLogger())
+	e.Export(&Event{Type: "a"})
+	require.NoError(t, e.Close())
+	assert.Empty(t, sink.batches)
+	assert.Equal(t, 7, sink.fails, "A batch should be tried once, and then MaxRetries times")
+	assert.Equal(t, ExporterStats{Failed: 1}, e.Stats())
+}
+
+func TestExporter_CloseDuringBackoff(t *testing.T) {
+	sink := &memorySink{fails: 1}
+	e := NewExporter(sink, ExporterConfig{BatchSize: 1, RetryBackoff: time.Hour}, mio.NewDiscardLogger())
+	e.Export(&Event{Type: "a"})
+	assert.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return sink.fails == 0
+	}, time.Second, time.Millisecond*5)
+
+	start := time.Now()
+	require.NoError(t, e.Close())
+	assert.Less(t, time.Since(start), time.Second, "Close should not wait out the backoff")
+	assert.Equal(t, ExporterStats{Exported: 1}, e.Stats(), "the batch should be retried right away")
+}
+
+type blockingSink struct {
+	memorySink
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(ctx context.Context, events []*Event) error {
+	<-s.release
+	return s.memorySink.Write(ctx, events)
+}
+
+func TestExporter_BufferFull(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	e := NewExporter(sink, ExporterConfig{BufferSize: 1, BatchSize: 1}, mio.NewDiscardLogger())
+
+	// one event is held up by the sink, and one fits in the buffer
+	accepted := 0
+	for i := 0; i < 100; i++ {
+		if e.Export(&Event{}) {
+			accepted++
+		}
+	}
+	close(sink.release)
+	require.NoError(t, e.Close())
+	assert.LessOrEqual(t, accepted, 2)
+	assert.Equal(t, int64(100-accepted), e.Stats().Dropped)
+	assert.Equal(t, int64(accepted), e.Stats().Exported)
+}
+
+func TestExporter_Attach(t *testing.T) {
+	bus := mio.NewEventBus(mio.NewDiscardLogger())
+	sink := &memorySink{}
+	e := NewExporter(sink, ExporterConfig{}, mio.NewDiscardLogger())
+	detach := e.Attach(bus)
+
+	bus.Emit(&bot.MessageProcessed{})
+	bus.Emit(&bot.CommandRan{Command: &bot.ModuleCommand{Name: "ping"}, Message: testMessage()})
+	bus.Emit(&struct{}{})
+	detach()
+	bus.Emit(&bot.MessageProcessed{})
+	require.NoError(t, e.Close())
+
+	require.Len(t, sink.batches, 1)
+	require.Len(t, sink.batches[0], 2)
+	assert.Equal(t, "message_processed", sink.batches[0][0].Type)
+	assert.Equal(t, "ping", sink.batches[0][1].Handler)
+}
+
+func TestWebhookSink(t *testing.T) {
+	var calls atomic.Int32
+	var got webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL)
+	sink.Header.Set("Authorization", "Bearer token")
+	e := NewExporter(sink, ExporterConfig{RetryBackoff: time.Millisecond}, mio.NewDiscardLogger())
+	e.Export(&Event{Type: "a"})
+	e.Export(&Event{Type: "b"})
+	require.NoError(t, e.Close())
+
+	assert.Equal(t, int32(2), calls.Load(), "A 503 should be retried")
+	require.Len(t, got.Events, 2)
+	assert.Equal(t, "b", got.Events[1].Type)
+}
+
+func TestWebhookSink_ClientError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	e := NewExporter(NewWebhookSink(srv.URL), ExporterConfig{RetryBackoff: time.Millisecond}, mio.NewDiscardLogger())
+	e.Export(&Event{Type: "a"})
+	require.NoError(t, e.Close())
+	assert.Equal(t, int32(1), calls.Load(), "A 400 should not be retried")
+	assert.Equal(t, int64(1), e.Stats().Failed)
+}
@@ -0,0 +1,123 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Sink is somewhere events are shipped to.
+type Sink interface {
+	// Write writes a batch of events. Batches that fail are retried, so sinks
+	// should write either all or none of a batch if they can.
+	Write(ctx context.Context, events []*Event) error
+	Close() error
+}
+
+var ErrSinkClosed = errors.New("sink is closed")
+
+const (
+	DefaultFileMaxSize    = 100 << 20
+	DefaultFileMaxBackups = 5
+)
+
+// FileSink writes events to a file as JSON lines. Once the file is larger than
+// MaxSize it is rotated, which renames it to path.1, path.1 to path.2 and so on,
+// keeping MaxBackups old files.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+
+	MaxSize    int64
+	MaxBackups int
+}
+
+// NewFileSink opens a FileSink that appends to the file at path. The defaults are
+// used for values of 0.
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultFileMaxSize
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultFileMaxBackups
+	}
+	s := &FileSink{path: path, MaxSize: maxSize, MaxBackups: maxBackups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	s.file, s.size = f, info.Size()
+	return nil
+}
+
+func (s *FileSink) Write(ctx context.Context, events []*Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return ErrSinkClosed
+	}
+
+	var lines []byte
+	for _, e := range events {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, b...), '\n')
+	}
+	if s.size > 0 && s.size+int64(len(lines)) > s.MaxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(lines)
+	s.size += int64(n)
+	return err
+}
+
+// rotate moves the current file out of the way and opens a new one.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+	_ = os.Remove(fmt.Sprintf("%v.%v", s.path, s.MaxBackups))
+	for i := s.MaxBackups - 1; i > 0; i-- {
+		_ = os.Rename(fmt.Sprintf("%v.%v", s.path, i), fmt.Sprintf("%v.%v", s.path, i+1))
+	}
+	// the file is reopened even if it could not be moved, so the sink keeps working
+	renameErr := os.Rename(s.path, s.path+".1")
+	if err := s.open(); err != nil {
+		return err
+	}
+	return renameErr
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readEvents(t *testing.T, path string) []*Event {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []*Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &e))
+		events = append(events, &e)
+	}
+	return events
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := NewFileSink(path, 0, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), []*Event{{Type: "a"}, {Type: "b"}}))
+	require.NoError(t, sink.Write(context.Background(), []*Event{{Type: "c"}}))
+	require.NoError(t, sink.Close())
+	assert.ErrorIs(t, sink.Write(context.Background(), []*Event{{Type: "d"}}), ErrSinkClosed)
+
+	events := readEvents(t, path)
+	require.Len(t, events, 3)
+	assert.Equal(t, "c", events[2].Type)
+
+	// reopening appends to the file
+	sink, err = NewFileSink(path, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), []*Event{{Type: "d"}}))
+	require.NoError(t, sink.Close())
+	assert.Len(t, readEvents(t, path), 4)
+}
+
+func TestFileSink_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	line, _ := json.Marshal(&Event{Type: "a"})
+	// room for two events per file
+	sink, err := NewFileSink(path, int64(len(line)+1)*2, 2)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	for i := 0; i < 7; i++ {
+		require.NoError(t, sink.Write(context.Background(), []*Event{{Type: "a"}}))
+	}
+	assert.Len(t, readEvents(t, path), 1)
+	assert.Len(t, readEvents(t, path+".1"), 2)
+	assert.Len(t, readEvents(t, path+".2"), 2)
+	assert.NoFileExists(t, path+".3", "Only MaxBackups old files should be kept")
+}
@@ -0,0 +1,79 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookSink POSTs batches of events to a URL, as a JSON object with the events
+// in its events field.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+	// Header is added to every request, which is where an authorization header goes.
+	Header http.Header
+}
+
+// NewWebhookSink returns a WebhookSink that posts to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		URL:    url,
+		Client: &http.Client{Timeout: time.Second * 10},
+		Header: make(http.Header),
+	}
+}
+
+type webhookPayload struct {
+	Events []*Event `json:"events"`
+}
+
+// StatusError is returned by WebhookSink when the webhook responds with a status
+// that is not 2xx.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook responded with status %v", e.StatusCode)
+}
+
+// Retryable reports whether the request can succeed if it is sent again.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+func (s *WebhookSink) Write(ctx context.Context, events []*Event) error {
+	body, err := json.Marshal(webhookPayload{Events: events})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range s.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &StatusError{StatusCode: res.StatusCode}
+	}
+	return nil
+}
+
+func (s *WebhookSink) Close() error {
+	s.Client.CloseIdleConnections()
+	return nil
+}
@@ -43,15 +43,17 @@ var (
 )
 
 type logger struct {
-	mutex sync.Mutex
+	// mutex is shared with named loggers, as they write to the same output
+	mutex *sync.Mutex
 	name  string
 	Out   io.Writer
 }
 
 func NewLogger(out io.Writer) Logger {
 	return &logger{
-		name: "",
-		Out:  out,
+		mutex: &sync.Mutex{},
+		name:  "",
+		Out:   out,
 	}
 }
 
@@ -127,7 +129,8 @@ func (l *logger) Named(name string) Logger {
 	}
 
 	return &logger{
-		name: strings.Join([]string{l.name, name}, "."),
-		Out:  l.Out,
+		mutex: l.mutex,
+		name:  strings.Join([]string{l.name, name}, "."),
+		Out:   l.Out,
 	}
 }
@@ -12,6 +12,10 @@ var (
 	ErrCallbackNotFound      = errors.New("callback for this key not found")
 )
 
+// CallbackManager hands messages to running commands by key.
+//
+// Deprecated: use bot.Prompts, which supports timeouts, filters, reactions and
+// component clicks, and cleans up after itself.
 type CallbackManager struct {
 	sync.Mutex
 	ch map[string]chan *discord.DiscordMessage
@@ -46,10 +50,15 @@ func (c *CallbackManager) Get(key string) (chan *discord.DiscordMessage, error)
 	return ch, nil
 }
 
-// Delete removes a channel for communication with a running command
+// Delete removes a channel for communication with a running command. Deleting
+// a missing key does nothing.
 func (c *CallbackManager) Delete(key string) {
 	c.Lock()
 	defer c.Unlock()
-	close(c.ch[key])
+	ch, ok := c.ch[key]
+	if !ok {
+		return
+	}
+	close(ch)
 	delete(c.ch, key)
 }
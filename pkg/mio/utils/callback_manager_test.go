@@ -49,4 +49,7 @@ func TestCooldownService_Delete(t *testing.T) {
 	if _, ok := handler.ch[key]; ok {
 		t.Errorf("Channel should have been deleted")
 	}
+
+	// deleting again should not panic
+	handler.Delete(key)
 }
@@ -5,6 +5,10 @@ import (
 	"time"
 )
 
+// CooldownManager keeps keys on cooldown for a duration.
+//
+// Deprecated: use bot.CooldownStore, which supports sliding window and token
+// bucket cooldowns, and can be kept in a database.
 type CooldownManager struct {
 	sync.Mutex
 	m map[string]time.Time
@@ -0,0 +1,72 @@
+package utils
+
+import "sync"
+
+// WaiterBufferSize is how many events a waiter can hold before further events to
+// it are dropped.
+const WaiterBufferSize = 8
+
+// Waiters hands events to the goroutines that are waiting for them.
+type Waiters[T any] struct {
+	sync.Mutex
+	next    int
+	waiters map[int]*waiter[T]
+}
+
+type waiter[T any] struct {
+	match func(T) bool
+	ch    chan T
+}
+
+func NewWaiters[T any]() *Waiters[T] {
+	return &Waiters[T]{
+		waiters: make(map[int]*waiter[T]),
+	}
+}
+
+// Wait registers a waiter for the events match returns true for. The returned
+// cancel func removes the waiter, and must be called once it is done. The
+// channel is never closed.
+func (w *Waiters[T]) Wait(match func(T) bool) (<-chan T, func()) {
+	w.Lock()
+	defer w.Unlock()
+	id := w.next
+	w.next++
+	wt := &waiter[T]{match: match, ch: make(chan T, WaiterBufferSize)}
+	w.waiters[id] = wt
+
+	var once sync.Once
+	return wt.ch, func() {
+		once.Do(func() {
+			w.Lock()
+			defer w.Unlock()
+			delete(w.waiters, id)
+		})
+	}
+}
+
+// Deliver hands v to every waiter it matches, without blocking. It returns
+// whether any waiter matched.
+func (w *Waiters[T]) Deliver(v T) bool {
+	w.Lock()
+	defer w.Unlock()
+	delivered := false
+	for _, wt := range w.waiters {
+		if !wt.match(v) {
+			continue
+		}
+		delivered = true
+		select {
+		case wt.ch <- v:
+		default:
+		}
+	}
+	return delivered
+}
+
+// Len returns the number of waiters.
+func (w *Waiters[T]) Len() int {
+	w.Lock()
+	defer w.Unlock()
+	return len(w.waiters)
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestWaiters_Deliver(t *testing.T) {
+	w := NewWaiters[int]()
+	even, cancelEven := w.Wait(func(n int) bool { return n%2 == 0 })
+	defer cancelEven()
+	all, cancelAll := w.Wait(func(int) bool { return true })
+
+	if !w.Deliver(2) {
+		t.Errorf("Waiters.Deliver() = false, want true")
+	}
+	if got := <-even; got != 2 {
+		t.Errorf("Expected 2 from even waiter, got %v", got)
+	}
+	if got := <-all; got != 2 {
+		t.Errorf("Expected 2 from all waiter, got %v", got)
+	}
+
+	w.Deliver(3)
+	select {
+	case got := <-even:
+		t.Errorf("Even waiter was not expected to get %v", got)
+	default:
+	}
+
+	cancelAll()
+	cancelAll()
+	if w.Len() != 1 {
+		t.Errorf("Waiters.Len() = %v, want 1", w.Len())
+	}
+	if w.Deliver(5) {
+		t.Errorf("Waiters.Deliver() = true, want false")
+	}
+}
+
+func TestWaiters_DeliverDoesNotBlock(t *testing.T) {
+	w := NewWaiters[int]()
+	_, cancel := w.Wait(func(int) bool { return true })
+	defer cancel()
+	for i := 0; i < WaiterBufferSize*2; i++ {
+		w.Deliver(i)
+	}
+}
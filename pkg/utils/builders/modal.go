@@ -0,0 +1,45 @@
+package builders
+
+import "github.com/bwmarrin/discordgo"
+
+// ModalBuilder builds the response data of a modal. Every text input gets an
+// action row of its own, as Discord requires.
+type ModalBuilder struct {
+	modal *discordgo.InteractionResponseData
+}
+
+func NewModalBuilder(customID, title string) *ModalBuilder {
+	return &ModalBuilder{modal: &discordgo.InteractionResponseData{
+		CustomID: customID,
+		Title:    title,
+	}}
+}
+
+func (b *ModalBuilder) AddTextInput(input *discordgo.TextInput) *ModalBuilder {
+	b.modal.Components = append(b.modal.Components, &discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{input},
+	})
+	return b
+}
+
+func (b *ModalBuilder) AddShortText(customID, label string, required bool) *ModalBuilder {
+	return b.AddTextInput(&discordgo.TextInput{
+		CustomID: customID,
+		Label:    label,
+		Style:    discordgo.TextInputShort,
+		Required: required,
+	})
+}
+
+func (b *ModalBuilder) AddParagraph(customID, label string, required bool) *ModalBuilder {
+	return b.AddTextInput(&discordgo.TextInput{
+		CustomID: customID,
+		Label:    label,
+		Style:    discordgo.TextInputParagraph,
+		Required: required,
+	})
+}
+
+func (b *ModalBuilder) Build() *discordgo.InteractionResponseData {
+	return b.modal
+}
@@ -0,0 +1,43 @@
+package builders
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewModalBuilder(t *testing.T) {
+	modal := NewModalBuilder("id", "Title").Build()
+	assert.Equal(t, "id", modal.CustomID)
+	assert.Equal(t, "Title", modal.Title)
+	assert.Empty(t, modal.Components)
+}
+
+func TestModalBuilder_AddTextInput(t *testing.T) {
+	input := &discordgo.TextInput{CustomID: "input", Label: "Input", MaxLength: 10}
+	modal := NewModalBuilder("id", "Title").AddTextInput(input).Build()
+
+	assert.Len(t, modal.Components, 1)
+	row, ok := modal.Components[0].(*discordgo.ActionsRow)
+	assert.True(t, ok, "Expected component to be of type *discordgo.ActionsRow")
+	assert.Equal(t, []discordgo.MessageComponent{input}, row.Components)
+}
+
+func TestModalBuilder_TextStyles(t *testing.T) {
+	modal := NewModalBuilder("id", "Title").
+		AddShortText("short", "Short", true).
+		AddParagraph("long", "Long", false).
+		Build()
+
+	assert.Len(t, modal.Components, 2)
+	short := modal.Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput)
+	assert.Equal(t, "short", short.CustomID)
+	assert.Equal(t, discordgo.TextInputShort, short.Style)
+	assert.True(t, short.Required)
+
+	long := modal.Components[1].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput)
+	assert.Equal(t, "long", long.CustomID)
+	assert.Equal(t, discordgo.TextInputParagraph, long.Style)
+	assert.False(t, long.Required)
+}
@@ -40,3 +40,12 @@ func (c *Config) GetStringSlice(key string) []string {
 	}
 	return []string{}
 }
+
+func (c *Config) GetBool(key string) bool {
+	if v, found := c.data[key]; found {
+		if vt, ok := v.(bool); ok {
+			return vt
+		}
+	}
+	return false
+}
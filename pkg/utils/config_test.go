@@ -59,3 +59,17 @@ func TestConfigBase_GetStringSlice(t *testing.T) {
 		t.Errorf("GetStringSlice() with non-existent key = %v, want empty slice", got)
 	}
 }
+
+func TestConfigBase_GetBool(t *testing.T) {
+	config := NewConfig()
+	testKey := "testKey"
+
+	config.Set(testKey, true)
+	if got := config.GetBool(testKey); !got {
+		t.Errorf("GetBool() = %v, want %v", got, true)
+	}
+
+	if got := config.GetBool("nonExistentKey"); got {
+		t.Errorf("GetBool() with non-existent key = %v, want false", got)
+	}
+}
@@ -30,6 +30,7 @@ func TrimChannelID(id string) string {
 }
 
 func TrimRoleID(id string) string {
+	id = strings.TrimPrefix(id, "<@&")
 	id = strings.TrimPrefix(id, "<&")
 	id = strings.TrimPrefix(id, "!")
 	return strings.TrimSuffix(id, ">")
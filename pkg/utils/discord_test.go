@@ -68,6 +68,7 @@ func TestTrimRoleID(t *testing.T) {
 		{"ID", "394302349721731072", "394302349721731072"},
 		{"<&ID>", "<&394302349721731072>", "394302349721731072"},
 		{"<&!ID>", "<&!394302349721731072>", "394302349721731072"},
+		{"<@&ID>", "<@&394302349721731072>", "394302349721731072"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {